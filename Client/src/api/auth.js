@@ -11,7 +11,7 @@ export const authApi = {
    * @param {Object} userData - { first_name, last_name, email, password, favourite_genres }
    */
   register: async (userData) => {
-    const response = await apiClient.post('/register', userData);
+    const response = await apiClient.post('/auth/register', userData);
     return response.data;
   },
 
@@ -20,7 +20,7 @@ export const authApi = {
    * @param {Object} credentials - { email, password }
    */
   login: async (credentials) => {
-    const response = await apiClient.post('/login', credentials);
+    const response = await apiClient.post('/auth/login', credentials);
     return response.data;
   },
 
@@ -28,7 +28,7 @@ export const authApi = {
    * Logout user - clears cookies server-side
    */
   logout: async () => {
-    const response = await apiClient.post('/logout');
+    const response = await apiClient.post('/auth/logout');
     return response.data;
   },
 
@@ -36,7 +36,7 @@ export const authApi = {
    * Refresh access token using refresh token cookie
    */
   refresh: async () => {
-    const response = await apiClient.post('/refresh');
+    const response = await apiClient.post('/auth/refresh');
     return response.data;
   },
 
@@ -44,7 +44,7 @@ export const authApi = {
    * Get current user profile
    */
   getProfile: async () => {
-    const response = await apiClient.get('/profile');
+    const response = await apiClient.get('/me');
     return response.data;
   },
 };
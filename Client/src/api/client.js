@@ -12,7 +12,7 @@ import axios from 'axios';
 
 // Base API configuration
 const apiClient = axios.create({
-  baseURL: '/api', // Uses Vite proxy in development
+  baseURL: '/api/v1', // Uses Vite proxy in development
   timeout: 10000,
   withCredentials: true, // CRUCIAL: Sends cookies with requests
   headers: {
@@ -51,7 +51,7 @@ apiClient.interceptors.response.use(
     // If error is 401 and we haven't already tried to refresh
     if (error.response?.status === 401 && !originalRequest._retry) {
       // Don't retry refresh endpoint itself
-      if (originalRequest.url === '/refresh') {
+      if (originalRequest.url === '/auth/refresh') {
         return Promise.reject(error);
       }
 
@@ -68,7 +68,7 @@ apiClient.interceptors.response.use(
       isRefreshing = true;
 
       try {
-        await apiClient.post('/refresh');
+        await apiClient.post('/auth/refresh');
         processQueue();
         return apiClient(originalRequest);
       } catch (refreshError) {
@@ -81,11 +81,14 @@ apiClient.interceptors.response.use(
       }
     }
 
-    // Normalize error response
+    // Normalize error response (the API returns RFC 7807 application/problem+json)
+    const problem = error.response?.data;
     const normalizedError = {
-      message: error.response?.data?.error || error.message || 'An error occurred',
+      message: problem?.detail || problem?.title || error.message || 'An error occurred',
       status: error.response?.status,
-      details: error.response?.data?.details,
+      code: problem?.code,
+      fieldErrors: problem?.errors || [],
+      requestId: problem?.request_id,
     };
 
     return Promise.reject(normalizedError);
@@ -19,7 +19,7 @@ export const moviesApi = {
    * @param {string} imdbId - The IMDB ID of the movie
    */
   getById: async (imdbId) => {
-    const response = await apiClient.get(`/movie/${imdbId}`);
+    const response = await apiClient.get(`/movies/${imdbId}`);
     return response.data;
   },
 
@@ -27,7 +27,7 @@ export const moviesApi = {
    * Get recommended movies based on user's favorite genres (protected)
    */
   getRecommended: async () => {
-    const response = await apiClient.get('/recommendedmovies');
+    const response = await apiClient.get('/me/recommendations');
     return response.data;
   },
 
@@ -36,7 +36,7 @@ export const moviesApi = {
    * @param {Object} movieData - Movie data object
    */
   add: async (movieData) => {
-    const response = await apiClient.post('/addmovie', movieData);
+    const response = await apiClient.post('/movies', movieData);
     return response.data;
   },
 
@@ -46,7 +46,7 @@ export const moviesApi = {
    * @param {Object} reviewData - { admin_review, ranking }
    */
   updateReview: async (imdbId, reviewData) => {
-    const response = await apiClient.patch(`/updatereview/${imdbId}`, reviewData);
+    const response = await apiClient.patch(`/movies/${imdbId}/review`, reviewData);
     return response.data;
   },
 };
@@ -0,0 +1,144 @@
+// Package apierror defines the API's typed errors and renders them as
+// RFC 7807 application/problem+json responses.
+//
+// Handlers report failures with c.Error(apierror.X(...)) and return;
+// Middleware turns the last error into the response body.
+package apierror
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Machine-readable error codes. Clients should switch on these rather than on messages.
+const (
+	CodeInvalidJSON        = "invalid_json"
+	CodeValidationFailed   = "validation_failed"
+	CodeInvalidParameter   = "invalid_parameter"
+	CodeRouteNotFound      = "route_not_found"
+	CodeMethodNotAllowed   = "method_not_allowed"
+	CodeMovieNotFound      = "movie_not_found"
+	CodeMovieExists        = "movie_exists"
+	CodeUserNotFound       = "user_not_found"
+	CodeEmailTaken         = "email_taken"
+	CodeGenreNotFound      = "genre_not_found"
+	CodeGenreExists        = "genre_exists"
+	CodeGenreInUse         = "genre_in_use"
+	CodePersonNotFound     = "person_not_found"
+	CodePersonInUse        = "person_in_use"
+	CodeCollectionNotFound = "collection_not_found"
+	CodeMovieInCollection  = "movie_in_collection"
+	CodeImportNotFound     = "import_not_found"
+	CodePayloadTooLarge    = "payload_too_large"
+	CodeMetadataNotFound   = "metadata_not_found"
+	CodeEnrichmentFailed   = "enrichment_failed"
+	CodeEnrichmentDisabled = "enrichment_disabled"
+	CodeUnsupportedMedia   = "unsupported_media_type"
+	CodeImageNotFound      = "image_not_found"
+	CodeStorageDisabled    = "storage_disabled"
+	CodePublicURLUnset     = "public_url_unset"
+	CodeTrailerNotFound    = "trailer_not_found"
+	CodeURLExpired         = "url_expired"
+	CodeURLInvalid         = "url_invalid"
+	CodeInvalidCredentials = "invalid_credentials"
+	CodeUnauthenticated    = "unauthenticated"
+	CodeForbidden          = "forbidden"
+	CodeTokenMissing       = "token_missing"
+	CodeTokenInvalid       = "token_invalid"
+	CodeTokenExpired       = "token_expired"
+	CodeTokenRevoked       = "token_revoked"
+	CodeInternal           = "internal_error"
+)
+
+// problemTypePrefix namespaces the RFC 7807 "type" member; the suffix is the error code.
+const problemTypePrefix = "urn:magicstream:problem:"
+
+// FieldError describes one invalid request field, named as it appears in the JSON body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error is an API error with an HTTP status and a stable code.
+// Err, when set, is the underlying cause; it is logged but never sent to clients.
+type Error struct {
+	Status int
+	Code   string
+	Detail string
+	Fields []FieldError
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Detail, e.Err)
+	}
+	return e.Code + ": " + e.Detail
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an Error with the given status, code and client-facing detail.
+func New(status int, code, detail string) *Error {
+	return &Error{Status: status, Code: code, Detail: detail}
+}
+
+// Wrap attaches an internal cause to the error.
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+func BadRequest(code, detail string) *Error {
+	return New(http.StatusBadRequest, code, detail)
+}
+
+func Unauthorized(code, detail string) *Error {
+	return New(http.StatusUnauthorized, code, detail)
+}
+
+func Forbidden(code, detail string) *Error {
+	return New(http.StatusForbidden, code, detail)
+}
+
+func NotFound(code, detail string) *Error {
+	return New(http.StatusNotFound, code, detail)
+}
+
+func Conflict(code, detail string) *Error {
+	return New(http.StatusConflict, code, detail)
+}
+
+// Internal hides err behind a generic 500; the detail is safe to show to clients.
+func Internal(detail string, err error) *Error {
+	return New(http.StatusInternalServerError, CodeInternal, detail).Wrap(err)
+}
+
+// Problem is the RFC 7807 response body, extended with code, request_id and errors.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// Problem renders e for the request at instance.
+func (e *Error) Problem(instance, requestID string) Problem {
+	return Problem{
+		Type:      problemTypePrefix + e.Code,
+		Title:     http.StatusText(e.Status),
+		Status:    e.Status,
+		Detail:    e.Detail,
+		Instance:  instance,
+		Code:      e.Code,
+		RequestID: requestID,
+		Errors:    e.Fields,
+	}
+}
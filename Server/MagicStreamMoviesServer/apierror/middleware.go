@@ -0,0 +1,63 @@
+package apierror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/logging"
+)
+
+// ContentType is the media type of every error response.
+const ContentType = "application/problem+json"
+
+// Middleware renders the last error attached with c.Error as problem+json,
+// unless the handler already wrote a response. Errors that are not *Error
+// become a generic 500 so internal messages never reach clients.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		var apiErr *Error
+		if !errors.As(err, &apiErr) {
+			apiErr = Internal("An unexpected error occurred", err)
+		}
+
+		if apiErr.Status >= http.StatusInternalServerError {
+			logging.FromContext(c.Request.Context()).Error("request failed", "code", apiErr.Code, "error", err)
+		}
+
+		Render(c, apiErr)
+	}
+}
+
+// Render writes e as problem+json and aborts the handler chain.
+func Render(c *gin.Context, e *Error) {
+	problem := e.Problem(c.Request.URL.Path, c.GetString("requestId"))
+
+	// Validators and cache policies set earlier in the chain describe the success body, not this one.
+	h := c.Writer.Header()
+	h.Del("ETag")
+	h.Del("Last-Modified")
+	h.Set("Cache-Control", "no-store")
+	c.Header("Content-Type", ContentType) // gin's JSON renderer keeps an existing Content-Type
+	c.AbortWithStatusJSON(e.Status, problem)
+}
+
+// NoRoute and NoMethod replace gin's plain-text 404/405 bodies.
+func NoRoute() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		Render(c, NotFound(CodeRouteNotFound, "No route matches "+c.Request.Method+" "+c.Request.URL.Path))
+	}
+}
+
+func NoMethod() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		Render(c, New(http.StatusMethodNotAllowed, CodeMethodNotAllowed, c.Request.Method+" is not allowed on "+c.Request.URL.Path))
+	}
+}
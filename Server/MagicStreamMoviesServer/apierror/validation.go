@@ -0,0 +1,168 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// NewValidator returns a validator that reports fields by their JSON names (or
+// query parameter names for structs bound from the query string), so
+// ValidationErrors never leak Go struct field names to clients.
+func NewValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" {
+			name, _, _ = strings.Cut(f.Tag.Get("form"), ",")
+		}
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+	return v
+}
+
+// Validation translates a struct validation error into a 400 validation_failed error
+// listing every invalid field. Errors that are not validator.ValidationErrors are
+// treated as internal, since they indicate a programming mistake.
+func Validation(err error) *Error {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return Internal("Validation could not be performed", err)
+	}
+
+	fields := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: ruleMessage(fe),
+		})
+	}
+
+	e := BadRequest(CodeValidationFailed, "One or more fields are invalid")
+	e.Fields = fields
+	return e.Wrap(err)
+}
+
+// InvalidBody translates a JSON binding error into a 400 response.
+// Type mismatches are reported per field like validation errors.
+func InvalidBody(err error) *Error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		e := BadRequest(CodeValidationFailed, "One or more fields are invalid")
+		e.Fields = []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("must be of type %s", jsonType(typeErr.Type)),
+		}}
+		return e.Wrap(err)
+	}
+
+	if errors.Is(err, io.EOF) {
+		return BadRequest(CodeInvalidJSON, "Request body is empty").Wrap(err)
+	}
+	return BadRequest(CodeInvalidJSON, "Request body is not valid JSON").Wrap(err)
+}
+
+// fieldPath drops the top-level struct name: "User.favourite_genres[0].genre_name"
+// becomes "favourite_genres[0].genre_name".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if _, rest, ok := strings.Cut(ns, "."); ok {
+		return rest
+	}
+	return ns
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "min", "max", "len":
+		return sizeMessage(fe)
+	case "gt", "gte", "lt", "lte":
+		return fmt.Sprintf("must be %s %s", comparisons[fe.Tag()], fe.Param())
+	case "gtefield", "ltefield":
+		return fmt.Sprintf("must be %s %s", comparisons[strings.TrimSuffix(fe.Tag(), "field")], fieldName(fe.Param()))
+	case "datetime":
+		if fe.Param() == "2006-01-02" {
+			return "must be a date formatted as YYYY-MM-DD"
+		}
+		return "must be a time formatted as " + fe.Param()
+	case "lowercase":
+		return "must be lowercase"
+	case "alpha":
+		return "must contain only letters"
+	case "unique":
+		return "must not contain duplicates"
+	}
+	return fmt.Sprintf("failed the %q rule", fe.Tag())
+}
+
+var comparisons = map[string]string{
+	"gt":  "greater than",
+	"gte": "at least",
+	"lt":  "less than",
+	"lte": "at most",
+}
+
+func sizeMessage(fe validator.FieldError) string {
+	bound := map[string]string{"min": "at least", "max": "at most", "len": "exactly"}[fe.Tag()]
+
+	switch fe.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("must be %s %s characters long", bound, fe.Param())
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("must contain %s %s items", bound, fe.Param())
+	}
+	return fmt.Sprintf("must be %s %s", bound, fe.Param())
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return t.String()
+}
+
+// fieldName converts the Go field name a cross-field rule refers to, e.g. "YearFrom",
+// into the snake_case name clients use.
+func fieldName(goName string) string {
+	var b strings.Builder
+	for i, r := range goName {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToLower(b.String())
+}
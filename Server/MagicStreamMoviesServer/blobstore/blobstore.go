@@ -0,0 +1,111 @@
+// Package blobstore keeps uploaded files such as poster images and trailers,
+// either on the local filesystem or in an S3-compatible bucket (AWS S3, MinIO, ...).
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by Get when no blob has the key.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores immutable blobs under slash-separated keys such as
+// "posters/<id>/w185.jpg". Keys must not contain "." or ".." segments.
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing any blob already there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob under key, or returns ErrNotFound. The caller closes the Object.
+	Get(ctx context.Context, key string) (*Object, error)
+	// Delete removes the blob under key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Object is an open blob. It is seekable so it can be served with range requests.
+type Object struct {
+	io.ReadSeekCloser
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Supported values for BLOB_BACKEND.
+const (
+	BackendFilesystem = "filesystem"
+	BackendS3         = "s3"
+)
+
+// Config selects and configures the blob store.
+type Config struct {
+	Backend string
+	Dir     string
+
+	S3Endpoint  string
+	S3Bucket    string
+	S3Region    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+}
+
+// LoadConfig reads the blob store configuration from the environment:
+//
+//	BLOB_BACKEND   filesystem (default) or s3
+//	BLOB_DIR       root directory of the filesystem backend (default ./data/blobs)
+//	S3_ENDPOINT    host[:port] of the S3 API, e.g. s3.amazonaws.com or localhost:9000 for MinIO
+//	S3_BUCKET      bucket name; created on startup if missing
+//	S3_REGION      bucket region (optional)
+//	S3_ACCESS_KEY  access key ID
+//	S3_SECRET_KEY  secret access key
+//	S3_USE_SSL     "false" to talk plain HTTP, e.g. to a local MinIO (default true)
+func LoadConfig() Config {
+	cfg := Config{
+		Backend:     strings.ToLower(os.Getenv("BLOB_BACKEND")),
+		Dir:         os.Getenv("BLOB_DIR"),
+		S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+		S3Bucket:    os.Getenv("S3_BUCKET"),
+		S3Region:    os.Getenv("S3_REGION"),
+		S3AccessKey: os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("S3_SECRET_KEY"),
+		S3UseSSL:    !strings.EqualFold(os.Getenv("S3_USE_SSL"), "false"),
+	}
+	if cfg.Backend == "" {
+		cfg.Backend = BackendFilesystem
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = "data/blobs"
+	}
+	return cfg
+}
+
+// Open returns the configured blob store.
+func Open(ctx context.Context, cfg Config) (BlobStore, error) {
+	switch cfg.Backend {
+	case BackendFilesystem:
+		return NewFilesystem(cfg.Dir)
+	case BackendS3:
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET must be set for BLOB_BACKEND=%s", BackendS3)
+		}
+		return NewS3(ctx, cfg)
+	}
+	return nil, fmt.Errorf("unknown BLOB_BACKEND %q (want %s or %s)", cfg.Backend, BackendFilesystem, BackendS3)
+}
+
+// validKey rejects keys that could escape the store's root.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("invalid blob key %q", key)
+		}
+	}
+	return nil
+}
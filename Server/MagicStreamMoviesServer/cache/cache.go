@@ -0,0 +1,76 @@
+// Package cache provides a read-through cache for catalogue lookups, backed by
+// an in-process LRU or by any Redis-protocol server.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache stores opaque values with a time-to-live.
+type Cache interface {
+	// Get reports whether key was present and not expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Close() error
+}
+
+// Supported values for CACHE_BACKEND.
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+	BackendNone   = "none"
+)
+
+// Config selects and tunes the cache.
+type Config struct {
+	Backend    string
+	TTL        time.Duration
+	MaxEntries int
+	RedisURL   string
+}
+
+// LoadConfig reads the cache configuration from the environment:
+//
+//	CACHE_BACKEND      memory (default), redis or none
+//	CACHE_TTL          entry lifetime, e.g. "5m" (default 5m)
+//	CACHE_MAX_ENTRIES  LRU capacity for the memory backend (default 1000)
+//	REDIS_URL          e.g. redis://:password@localhost:6379/0 (redis backend)
+func LoadConfig() Config {
+	cfg := Config{
+		Backend:    strings.ToLower(os.Getenv("CACHE_BACKEND")),
+		TTL:        5 * time.Minute,
+		MaxEntries: 1000,
+		RedisURL:   os.Getenv("REDIS_URL"),
+	}
+	if cfg.Backend == "" {
+		cfg.Backend = BackendMemory
+	}
+	if d, err := time.ParseDuration(os.Getenv("CACHE_TTL")); err == nil && d > 0 {
+		cfg.TTL = d
+	}
+	if n, err := strconv.Atoi(os.Getenv("CACHE_MAX_ENTRIES")); err == nil && n > 0 {
+		cfg.MaxEntries = n
+	}
+	return cfg
+}
+
+// Open returns the configured cache, or nil for the "none" backend.
+func Open(ctx context.Context, cfg Config) (Cache, error) {
+	switch cfg.Backend {
+	case BackendNone:
+		return nil, nil
+	case BackendMemory:
+		return NewLRU(cfg.MaxEntries), nil
+	case BackendRedis:
+		if cfg.RedisURL == "" {
+			return nil, fmt.Errorf("REDIS_URL must be set for CACHE_BACKEND=%s", BackendRedis)
+		}
+		return NewRedis(ctx, cfg.RedisURL)
+	}
+	return nil, fmt.Errorf("unknown CACHE_BACKEND %q (want %s, %s or %s)", cfg.Backend, BackendMemory, BackendRedis, BackendNone)
+}
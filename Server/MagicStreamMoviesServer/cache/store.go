@@ -0,0 +1,386 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/logging"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/metrics"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+)
+
+const keyPrefix = "magicstream:catalog:"
+
+// Wrap puts c in front of the catalogue reads of store: movies, genres, people and
+// collections. Every catalogue write made through the returned store invalidates all cached catalogue
+// entries, so new edit paths are covered as long as they go through the repositories.
+// A nil c returns store unchanged.
+func Wrap(store repository.Store, c Cache, ttl time.Duration) repository.Store {
+	if c == nil {
+		return store
+	}
+
+	catalog := &catalog{cache: c, ttl: ttl}
+	store.Movies = &cachedMovieRepository{next: store.Movies, catalog: catalog}
+	store.Genres = &cachedGenreRepository{next: store.Genres, catalog: catalog}
+	store.People = &cachedPersonRepository{next: store.People, catalog: catalog}
+	store.Collections = &cachedCollectionRepository{next: store.Collections, catalog: catalog}
+	return store
+}
+
+// catalog namespaces entries under a generation stored in the cache itself.
+// Invalidation writes a new generation instead of deleting keys, which works
+// the same for the LRU and for a shared Redis; orphaned entries age out via TTL.
+type catalog struct {
+	cache Cache
+	ttl   time.Duration
+}
+
+var generationKey = keyPrefix + "generation"
+
+// generationTTL outlives any entry, so a generation is never dropped while entries under it are live.
+const generationTTL = 30 * 24 * time.Hour
+
+func (c *catalog) generation(ctx context.Context) (string, error) {
+	gen, ok, err := c.cache.Get(ctx, generationKey)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return string(gen), nil
+	}
+	// Missing (first use, or evicted): start a fresh generation rather than
+	// reusing an old one whose entries might be stale.
+	return c.invalidate(ctx)
+}
+
+func (c *catalog) invalidate(ctx context.Context) (string, error) {
+	gen := strconv.FormatInt(time.Now().UnixNano(), 36)
+	return gen, c.cache.Set(ctx, generationKey, []byte(gen), generationTTL)
+}
+
+// invalidateAfterWrite is called after a successful write. Failure is logged, not
+// returned: the write itself succeeded and entries expire within the TTL anyway.
+func (c *catalog) invalidateAfterWrite(ctx context.Context) {
+	if _, err := c.invalidate(ctx); err != nil {
+		logging.FromContext(ctx).Warn("failed to invalidate catalogue cache", "error", err)
+	}
+}
+
+// readThrough returns the cached value for key or loads, stores and returns it.
+// Cache failures fall back to load so an unavailable cache never fails a request.
+func readThrough[T any](ctx context.Context, c *catalog, lookup, key string, load func() (T, error)) (T, error) {
+	gen, err := c.generation(ctx)
+	if err != nil {
+		metrics.RecordCacheLookup(lookup, metrics.CacheError)
+		logging.FromContext(ctx).Warn("catalogue cache unavailable", "lookup", lookup, "error", err)
+		return load()
+	}
+	fullKey := keyPrefix + gen + ":" + key
+
+	if data, ok, err := c.cache.Get(ctx, fullKey); err == nil && ok {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			metrics.RecordCacheLookup(lookup, metrics.CacheHit)
+			return value, nil
+		}
+	} else if err != nil {
+		metrics.RecordCacheLookup(lookup, metrics.CacheError)
+		return load()
+	}
+	metrics.RecordCacheLookup(lookup, metrics.CacheMiss)
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	if data, err := json.Marshal(value); err == nil {
+		if err := c.cache.Set(ctx, fullKey, data, c.ttl); err != nil {
+			logging.FromContext(ctx).Warn("failed to populate catalogue cache", "lookup", lookup, "error", err)
+		}
+	}
+	return value, nil
+}
+
+// ---------- MOVIES ----------
+
+// cachedMovieRepository implements every method explicitly (no embedding), so a
+// new repository method fails to compile here until its caching or invalidation is decided.
+type cachedMovieRepository struct {
+	next    repository.MovieRepository
+	catalog *catalog
+}
+
+func (r *cachedMovieRepository) List(ctx context.Context, filter repository.MovieFilter) ([]models.Movie, error) {
+	key, err := json.Marshal(filter)
+	if err != nil {
+		return r.next.List(ctx, filter)
+	}
+	return readThrough(ctx, r.catalog, "movie_list", "movies:"+string(key), func() ([]models.Movie, error) {
+		return r.next.List(ctx, filter)
+	})
+}
+
+// Stream is never cached: exports should see the database, and caching would
+// mean buffering the whole result.
+func (r *cachedMovieRepository) Stream(ctx context.Context, filter repository.MovieFilter, fn func(models.Movie) error) error {
+	return r.next.Stream(ctx, filter, fn)
+}
+
+func (r *cachedMovieRepository) FindByImdbID(ctx context.Context, imdbID string) (models.Movie, error) {
+	return readThrough(ctx, r.catalog, "movie", "movie:"+imdbID, func() (models.Movie, error) {
+		return r.next.FindByImdbID(ctx, imdbID)
+	})
+}
+
+func (r *cachedMovieRepository) ListByGenreNames(ctx context.Context, genreNames []string, limit int) ([]models.Movie, error) {
+	names := slices.Clone(genreNames)
+	slices.Sort(names)
+	key := "by-genre:" + strconv.Itoa(limit) + ":" + strings.Join(names, "\x1f")
+
+	return readThrough(ctx, r.catalog, "movies_by_genre", key, func() ([]models.Movie, error) {
+		return r.next.ListByGenreNames(ctx, genreNames, limit)
+	})
+}
+
+func (r *cachedMovieRepository) Create(ctx context.Context, movie models.Movie) (models.Movie, error) {
+	created, err := r.next.Create(ctx, movie)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return created, err
+}
+
+func (r *cachedMovieRepository) UpdateReview(ctx context.Context, imdbID, review string, ranking models.Ranking) error {
+	err := r.next.UpdateReview(ctx, imdbID, review, ranking)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
+
+func (r *cachedMovieRepository) Upsert(ctx context.Context, movie models.Movie) (bool, error) {
+	created, err := r.next.Upsert(ctx, movie)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return created, err
+}
+
+func (r *cachedMovieRepository) UpsertMany(ctx context.Context, movies []models.Movie) (int, error) {
+	created, err := r.next.UpsertMany(ctx, movies)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return created, err
+}
+
+func (r *cachedMovieRepository) CountByGenre(ctx context.Context, genreId int) (int64, error) {
+	return r.next.CountByGenre(ctx, genreId)
+}
+
+func (r *cachedMovieRepository) ReplaceGenre(ctx context.Context, fromIDs []int, to models.Genre) (int64, error) {
+	n, err := r.next.ReplaceGenre(ctx, fromIDs, to)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return n, err
+}
+
+func (r *cachedMovieRepository) ListByImdbIDs(ctx context.Context, imdbIDs []string) ([]models.Movie, error) {
+	return readThrough(ctx, r.catalog, "movies_by_id", "by-id:"+strings.Join(imdbIDs, "\x1f"), func() ([]models.Movie, error) {
+		return r.next.ListByImdbIDs(ctx, imdbIDs)
+	})
+}
+
+// ListByPerson is cached because it backs every filmography page.
+func (r *cachedMovieRepository) ListByPerson(ctx context.Context, personId string) ([]models.Movie, error) {
+	return readThrough(ctx, r.catalog, "movies_by_person", "by-person:"+personId, func() ([]models.Movie, error) {
+		return r.next.ListByPerson(ctx, personId)
+	})
+}
+
+func (r *cachedMovieRepository) UpdateCredits(ctx context.Context, imdbID string, credits []models.Credit) error {
+	err := r.next.UpdateCredits(ctx, imdbID, credits)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
+
+func (r *cachedMovieRepository) UpdateMetadata(ctx context.Context, imdbID string, movie models.Movie) error {
+	err := r.next.UpdateMetadata(ctx, imdbID, movie)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
+
+// ListStale is not cached: only the refresher calls it, and it must see fresh timestamps.
+func (r *cachedMovieRepository) ListStale(ctx context.Context, before time.Time, limit int) ([]models.Movie, error) {
+	return r.next.ListStale(ctx, before, limit)
+}
+
+func (r *cachedMovieRepository) UpdatePoster(ctx context.Context, imdbID, posterPath string) error {
+	err := r.next.UpdatePoster(ctx, imdbID, posterPath)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
+
+// ---------- GENRES ----------
+
+type cachedGenreRepository struct {
+	next    repository.GenreRepository
+	catalog *catalog
+}
+
+func (r *cachedGenreRepository) List(ctx context.Context) ([]models.Genre, error) {
+	return readThrough(ctx, r.catalog, "genre_list", "genres", func() ([]models.Genre, error) {
+		return r.next.List(ctx)
+	})
+}
+
+func (r *cachedGenreRepository) FindByID(ctx context.Context, genreId int) (models.Genre, error) {
+	return readThrough(ctx, r.catalog, "genre", "genre:"+strconv.Itoa(genreId), func() (models.Genre, error) {
+		return r.next.FindByID(ctx, genreId)
+	})
+}
+
+func (r *cachedGenreRepository) Create(ctx context.Context, genre models.Genre) error {
+	err := r.next.Create(ctx, genre)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
+
+func (r *cachedGenreRepository) Rename(ctx context.Context, genreId int, name string) error {
+	err := r.next.Rename(ctx, genreId, name)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
+
+func (r *cachedGenreRepository) Delete(ctx context.Context, genreId int) error {
+	err := r.next.Delete(ctx, genreId)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
+
+func (r *cachedGenreRepository) Upsert(ctx context.Context, genre models.Genre) (bool, error) {
+	created, err := r.next.Upsert(ctx, genre)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return created, err
+}
+
+// ---------- PEOPLE ----------
+
+type cachedPersonRepository struct {
+	next    repository.PersonRepository
+	catalog *catalog
+}
+
+func (r *cachedPersonRepository) FindByID(ctx context.Context, personId string) (models.Person, error) {
+	return readThrough(ctx, r.catalog, "person", "person:"+personId, func() (models.Person, error) {
+		return r.next.FindByID(ctx, personId)
+	})
+}
+
+// FindByIDs is only used to check credits before a write, so it always reads through.
+func (r *cachedPersonRepository) FindByIDs(ctx context.Context, personIds []string) ([]models.Person, error) {
+	return r.next.FindByIDs(ctx, personIds)
+}
+
+func (r *cachedPersonRepository) Search(ctx context.Context, query string, limit int) ([]models.Person, error) {
+	key := "people:" + strconv.Itoa(limit) + ":" + strings.ToLower(query)
+	return readThrough(ctx, r.catalog, "person_search", key, func() ([]models.Person, error) {
+		return r.next.Search(ctx, query, limit)
+	})
+}
+
+func (r *cachedPersonRepository) Create(ctx context.Context, person models.Person) error {
+	err := r.next.Create(ctx, person)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
+
+func (r *cachedPersonRepository) Update(ctx context.Context, person models.Person) error {
+	err := r.next.Update(ctx, person)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
+
+func (r *cachedPersonRepository) Delete(ctx context.Context, personId string) error {
+	err := r.next.Delete(ctx, personId)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
+
+// ---------- COLLECTIONS ----------
+
+type cachedCollectionRepository struct {
+	next    repository.CollectionRepository
+	catalog *catalog
+}
+
+func (r *cachedCollectionRepository) List(ctx context.Context) ([]models.Collection, error) {
+	return readThrough(ctx, r.catalog, "collection_list", "collections", func() ([]models.Collection, error) {
+		return r.next.List(ctx)
+	})
+}
+
+func (r *cachedCollectionRepository) FindByID(ctx context.Context, collectionId string) (models.Collection, error) {
+	return readThrough(ctx, r.catalog, "collection", "collection:"+collectionId, func() (models.Collection, error) {
+		return r.next.FindByID(ctx, collectionId)
+	})
+}
+
+// FindByMovie runs on every GetMovie. Errors are never cached, so for movies outside
+// any collection (ErrNotFound) the lookup always reaches the store.
+func (r *cachedCollectionRepository) FindByMovie(ctx context.Context, imdbID string) (models.Collection, error) {
+	return readThrough(ctx, r.catalog, "collection_by_movie", "collection-of:"+imdbID, func() (models.Collection, error) {
+		return r.next.FindByMovie(ctx, imdbID)
+	})
+}
+
+func (r *cachedCollectionRepository) Create(ctx context.Context, collection models.Collection) error {
+	err := r.next.Create(ctx, collection)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
+
+func (r *cachedCollectionRepository) Update(ctx context.Context, collection models.Collection) error {
+	err := r.next.Update(ctx, collection)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
+
+func (r *cachedCollectionRepository) Delete(ctx context.Context, collectionId string) error {
+	err := r.next.Delete(ctx, collectionId)
+	if err == nil {
+		r.catalog.invalidateAfterWrite(ctx)
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Version reports the catalogue generation as an httpcache.Versioner, so catalogue
+// responses can be validated without running their handlers. Only use it with a
+// cache every instance and the admin CLI share, such as Redis: an in-process LRU
+// never hears of writes made elsewhere.
+type Version struct {
+	catalog *catalog
+}
+
+// NewVersion returns the Version of the catalogue cached in c with entries living ttl.
+func NewVersion(c Cache, ttl time.Duration) *Version {
+	return &Version{catalog: &catalog{cache: c, ttl: ttl}}
+}
+
+// Version returns the current generation and when it was started. A write that
+// bypassed the cache reaches clients once cached entries expire, so the version
+// also moves on every ttl; validators then expire no later than the data.
+func (v *Version) Version(ctx context.Context) (string, time.Time, bool) {
+	gen, err := v.catalog.generation(ctx)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(gen, 36, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+
+	modified := time.Unix(0, nanos)
+	if v.catalog.ttl > 0 {
+		if epoch := time.Now().Truncate(v.catalog.ttl); epoch.After(modified) {
+			modified = epoch
+		}
+	}
+	return gen + "-" + strconv.FormatInt(modified.Unix(), 36), modified, true
+}
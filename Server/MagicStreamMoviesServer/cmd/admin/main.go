@@ -0,0 +1,498 @@
+// Command admin performs maintenance tasks against the same storage backend as the server:
+// bootstrapping admins, seeding the catalogue, running migrations and revoking sessions.
+//
+// Usage:
+//
+//	go run ./cmd/admin <command> [flags]
+//
+// Run it from the server directory so the same .env is picked up.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/cache"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/enrichment"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/importer"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/storage"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, backend *storage.Backend, args []string) error
+}
+
+var commands = []command{
+	{"create-admin", "create a new ADMIN user", createAdmin},
+	{"promote", "grant ADMIN role to an existing user", promote},
+	{"seed-genres", "upsert genres from a JSON fixture", seedGenres},
+	{"seed-movies", "upsert movies from a JSON fixture", seedMovies},
+	{"import-movies", "validate and upsert movies from a CSV or JSON Lines file", importMovies},
+	{"enrich-movies", "fill in movie metadata from the external movie database", enrichMovies},
+	{"migrate", "run database migrations: up, down or status", migrate},
+	{"revoke-sessions", "revoke refresh tokens for one user or everyone", revokeSessions},
+	{"stats", "print catalogue and user statistics", stats},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: admin <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun 'admin <command> -h' for command flags.")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == os.Args[1] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	backend, err := storage.Open(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open storage backend:", err)
+		os.Exit(1)
+	}
+	defer backend.Close(context.Background())
+
+	passwordConfig, err := utils.LoadPasswordHashConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid password hashing configuration:", err)
+		backend.Close(context.Background())
+		os.Exit(1)
+	}
+	utils.SetPasswordHashConfig(passwordConfig)
+
+	// Servers sharing a Redis cache must see catalogue writes made here, so they go
+	// through the same cache layer and invalidate it. An LRU lives in each server's
+	// own memory, out of reach.
+	if cacheConfig := cache.LoadConfig(); cacheConfig.Backend == cache.BackendRedis {
+		catalogCache, err := cache.Open(ctx, cacheConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to open cache:", err)
+			backend.Close(context.Background())
+			os.Exit(1)
+		}
+		defer catalogCache.Close()
+		backend.Store = cache.Wrap(backend.Store, catalogCache, cacheConfig.TTL)
+	}
+
+	if err := cmd.run(ctx, backend, os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.name, err)
+		backend.Close(context.Background())
+		os.Exit(1)
+	}
+}
+
+func createAdmin(ctx context.Context, backend *storage.Backend, args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	email := fs.String("email", "", "admin email (required)")
+	password := fs.String("password", os.Getenv("ADMIN_PASSWORD"), "admin password (default $ADMIN_PASSWORD)")
+	firstName := fs.String("first-name", "Admin", "first name")
+	lastName := fs.String("last-name", "User", "last name")
+	fs.Parse(args)
+
+	now := time.Now()
+	oid := bson.NewObjectID()
+	user := models.User{
+		ID:              oid,
+		UserID:          oid.Hex(),
+		FirstName:       *firstName,
+		LastName:        *lastName,
+		Email:           *email,
+		Password:        *password,
+		Role:            models.RoleAdmin,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		FavouriteGenres: []models.Genre{},
+	}
+	if err := validator.New().Struct(user); err != nil {
+		return err
+	}
+
+	exists, err := backend.Users.EmailExists(ctx, user.Email)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("user with email %s already exists; use promote instead", user.Email)
+	}
+
+	if user.Password, err = utils.HashPassword(user.Password); err != nil {
+		return err
+	}
+	if err := backend.Users.Create(ctx, user); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created admin %s (user_id %s)\n", user.Email, user.UserID)
+	return nil
+}
+
+func promote(ctx context.Context, backend *storage.Backend, args []string) error {
+	fs := flag.NewFlagSet("promote", flag.ExitOnError)
+	email := fs.String("email", "", "email of the user to promote (required)")
+	demote := fs.Bool("demote", false, "revert the user to USER instead")
+	fs.Parse(args)
+
+	user, err := backend.Users.FindByEmail(ctx, *email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("no user with email %q", *email)
+	}
+	if err != nil {
+		return err
+	}
+
+	role := models.RoleAdmin
+	if *demote {
+		role = models.RoleUser
+	}
+	if err := backend.Users.UpdateRole(ctx, user.UserID, role); err != nil {
+		return err
+	}
+	// Existing access tokens still carry the old role until they expire; force a fresh login.
+	if err := backend.Users.ClearRefreshTokenHash(ctx, user.UserID); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is now %s\n", user.Email, role)
+	return nil
+}
+
+func readFixture(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func seedGenres(ctx context.Context, backend *storage.Backend, args []string) error {
+	fs := flag.NewFlagSet("seed-genres", flag.ExitOnError)
+	file := fs.String("file", "cmd/admin/fixtures/genres.json", "JSON array of genres")
+	fs.Parse(args)
+
+	var genres []models.Genre
+	if err := readFixture(*file, &genres); err != nil {
+		return err
+	}
+
+	validate := validator.New()
+	var created, updated int
+	for i, g := range genres {
+		if err := validate.Struct(g); err != nil {
+			return fmt.Errorf("genre #%d: %w", i, err)
+		}
+		isNew, err := backend.Genres.Upsert(ctx, g)
+		if err != nil {
+			return err
+		}
+		if isNew {
+			created++
+		} else {
+			updated++
+		}
+	}
+
+	fmt.Printf("Genres seeded: %d created, %d updated\n", created, updated)
+	return nil
+}
+
+func seedMovies(ctx context.Context, backend *storage.Backend, args []string) error {
+	fs := flag.NewFlagSet("seed-movies", flag.ExitOnError)
+	file := fs.String("file", "cmd/admin/fixtures/movies.json", "JSON array of movies")
+	fs.Parse(args)
+
+	var movies []models.Movie
+	if err := readFixture(*file, &movies); err != nil {
+		return err
+	}
+
+	validate := validator.New()
+	for i, m := range movies {
+		if err := validate.Struct(m); err != nil {
+			return fmt.Errorf("movie #%d (%s): %w", i, m.ImdbID, err)
+		}
+	}
+
+	var created, updated int
+	for _, m := range movies {
+		isNew, err := backend.Movies.Upsert(ctx, m)
+		if err != nil {
+			return fmt.Errorf("movie %s: %w", m.ImdbID, err)
+		}
+		if isNew {
+			created++
+		} else {
+			updated++
+		}
+	}
+
+	fmt.Printf("Movies seeded: %d created, %d updated\n", created, updated)
+	return nil
+}
+
+// enrichMovies enriches one movie, or runs a single refresh pass over stale movies;
+// scheduled from cron it replaces the server's refresher on multi-instance deployments.
+func enrichMovies(ctx context.Context, backend *storage.Backend, args []string) error {
+	cfg := enrichment.LoadConfig()
+	fs := flag.NewFlagSet("enrich-movies", flag.ExitOnError)
+	imdbID := fs.String("imdb-id", "", "enrich only this movie (default: refresh stale movies)")
+	batch := fs.Int("batch", cfg.RefreshBatch, "stale movies refreshed in this run")
+	staleAfter := fs.Duration("stale-after", cfg.StaleAfter, "age at which metadata is refreshed")
+	fs.Parse(args)
+
+	if !cfg.Enabled() {
+		return errors.New("set ENRICH_API_KEY or ENRICH_API_TOKEN")
+	}
+	service := enrichment.NewService(enrichment.NewClient(cfg), backend.Store)
+
+	if *imdbID != "" {
+		preview, err := service.Apply(ctx, *imdbID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("no movie with imdb_id %s", *imdbID)
+		}
+		if err != nil {
+			return err
+		}
+		changed := strings.Join(preview.Changed, ", ")
+		if changed == "" {
+			changed = "nothing"
+		}
+		fmt.Printf("Enriched %s; changed: %s\n", *imdbID, changed)
+		if len(preview.UnmatchedGenres) > 0 {
+			fmt.Printf("Genres with no catalogue match: %s\n", strings.Join(preview.UnmatchedGenres, ", "))
+		}
+		return nil
+	}
+
+	if *batch < 1 {
+		return errors.New("-batch must be at least 1")
+	}
+	cfg.RefreshBatch, cfg.StaleAfter = *batch, *staleAfter
+	refreshed, err := enrichment.NewRefresher(service, cfg).RefreshStale(ctx)
+	fmt.Printf("Refreshed %d movies\n", refreshed)
+	return err
+}
+
+func importMovies(ctx context.Context, backend *storage.Backend, args []string) error {
+	fs := flag.NewFlagSet("import-movies", flag.ExitOnError)
+	file := fs.String("file", "", "CSV or JSON Lines file to import (required)")
+	format := fs.String("format", "", "csv or jsonl (default from the file extension)")
+	dryRun := fs.Bool("dry-run", false, "validate and report without writing")
+	batchSize := fs.Int("batch-size", importer.DefaultBatchSize, "movies written per batch")
+	fs.Parse(args)
+
+	if *file == "" {
+		return errors.New("pass -file <path>")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+	f, ok := importer.ParseFormat(*format)
+	if !ok {
+		return fmt.Errorf("unknown format %q (want csv or jsonl)", *format)
+	}
+	if *batchSize < 1 || *batchSize > importer.MaxBatchSize {
+		return fmt.Errorf("-batch-size must be between 1 and %d", importer.MaxBatchSize)
+	}
+
+	in, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	opts := importer.Options{Format: f, DryRun: *dryRun, BatchSize: *batchSize, MaxErrors: math.MaxInt}
+	report, err := importer.New(backend.Store).Run(ctx, in, opts, func(r importer.Report) {
+		fmt.Fprintf(os.Stderr, "Batch %d: %d rows read, %d valid, %d invalid\n", r.Batches, r.Rows, r.Valid, r.Invalid)
+	})
+	for _, rowErr := range report.Errors {
+		where := fmt.Sprintf("line %d", rowErr.Line)
+		if rowErr.ImdbID != "" {
+			where += " (" + rowErr.ImdbID + ")"
+		}
+		for _, fe := range rowErr.Errors {
+			fmt.Println(strings.TrimSpace(where+": "+fe.Field) + " " + fe.Message)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	verb := "imported"
+	if *dryRun {
+		verb = "would be imported (dry run)"
+	}
+	fmt.Printf("Movies %s: %d created, %d updated; %d of %d rows invalid\n",
+		verb, report.Created, report.Updated, report.Invalid, report.Rows)
+	if report.Invalid > 0 {
+		return fmt.Errorf("%d invalid rows were skipped", report.Invalid)
+	}
+	return nil
+}
+
+func migrate(ctx context.Context, backend *storage.Backend, args []string) error {
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "up":
+		ran, err := backend.Migrate(ctx)
+		for _, name := range ran {
+			fmt.Println("Applied migration", name)
+		}
+		if err == nil && len(ran) == 0 {
+			fmt.Println("No pending migrations")
+		}
+		return err
+	case "down":
+		name, err := backend.RollbackMigration(ctx)
+		if err == nil && name != "" {
+			fmt.Println("Reverted migration", name)
+		}
+		return err
+	case "status":
+		pending, err := backend.PendingMigrations(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			fmt.Println("Database is up to date")
+		}
+		for _, name := range pending {
+			fmt.Println("Pending:", name)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown action %q (want up, down or status)", action)
+}
+
+func revokeSessions(ctx context.Context, backend *storage.Backend, args []string) error {
+	fs := flag.NewFlagSet("revoke-sessions", flag.ExitOnError)
+	email := fs.String("email", "", "only revoke this user's session")
+	all := fs.Bool("all", false, "revoke every user's session")
+	fs.Parse(args)
+
+	switch {
+	case *email != "":
+		user, err := backend.Users.FindByEmail(ctx, *email)
+		if err != nil {
+			return err
+		}
+		if err := backend.Users.ClearRefreshTokenHash(ctx, user.UserID); err != nil {
+			return err
+		}
+		fmt.Println("Revoked session for", user.Email)
+	case *all:
+		n, err := backend.Users.ClearAllRefreshTokenHashes(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Revoked %d sessions\n", n)
+	default:
+		return errors.New("pass -email <address> or -all")
+	}
+
+	// Access tokens are stateless and stay valid until they expire (24h).
+	fmt.Println("Note: already-issued access tokens remain valid until they expire")
+	return nil
+}
+
+func stats(ctx context.Context, backend *storage.Backend, args []string) error {
+	movies, err := backend.Movies.List(ctx, repository.MovieFilter{})
+	if err != nil {
+		return err
+	}
+	genres, err := backend.Genres.List(ctx)
+	if err != nil {
+		return err
+	}
+	roles, err := backend.Users.CountByRole(ctx)
+	if err != nil {
+		return err
+	}
+
+	perGenre := map[string]int{}
+	perRanking := map[string]int{}
+	for _, m := range movies {
+		for _, g := range m.Genre {
+			perGenre[g.GenreName]++
+		}
+		perRanking[m.Ranking.RankingName]++
+	}
+
+	fmt.Printf("Backend: %s\n\n", backend.Name)
+	fmt.Printf("Movies: %d\n", len(movies))
+	printCounts("By genre", perGenre)
+	printCounts("By ranking", perRanking)
+	fmt.Printf("\nGenres: %d\n", len(genres))
+
+	var users int64
+	for _, n := range roles {
+		users += n
+	}
+	fmt.Printf("\nUsers: %d (%d %s, %d %s)\n", users, roles[models.RoleAdmin], models.RoleAdmin, roles[models.RoleUser], models.RoleUser)
+	return nil
+}
+
+func printCounts(title string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	fmt.Printf("  %s:\n", title)
+	for _, k := range keys {
+		label := k
+		if strings.TrimSpace(label) == "" {
+			label = "(none)"
+		}
+		fmt.Printf("    %-20s %d\n", label, counts[k])
+	}
+}
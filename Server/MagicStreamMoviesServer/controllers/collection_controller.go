@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// GetCollections lists every collection by name (public).
+func (h *Handler) GetCollections() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		collections, err := h.Collections.List(ctx)
+		if err != nil {
+			c.Error(apierror.Internal("Failed to fetch collections", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, collections)
+	}
+}
+
+// GetCollection returns a collection with its member movies in order (public).
+func (h *Handler) GetCollection() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		collectionId := c.Param("collection_id")
+		collection, err := h.Collections.FindByID(ctx, collectionId)
+		if err != nil {
+			c.Error(collectionLookupError(collectionId, err))
+			return
+		}
+
+		found, err := h.Movies.ListByImdbIDs(ctx, collection.MovieIDs)
+		if err != nil {
+			c.Error(apierror.Internal("Failed to fetch collection movies", err))
+			return
+		}
+		byID := make(map[string]models.Movie, len(found))
+		for _, m := range found {
+			byID[m.ImdbID] = m
+		}
+		movies := make([]models.Movie, 0, len(collection.MovieIDs))
+		for _, id := range collection.MovieIDs {
+			if m, ok := byID[id]; ok {
+				movies = append(movies, m)
+			}
+		}
+
+		c.JSON(http.StatusOK, CollectionResponse{Collection: collection, Movies: movies})
+	}
+}
+
+// CreateCollection adds a collection (protected, ADMIN only). collection_id is assigned
+// by the server; every movie must exist and must not belong to another collection.
+func (h *Handler) CreateCollection() gin.HandlerFunc {
+	validate := apierror.NewValidator()
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var collection models.Collection
+		if err := c.ShouldBindJSON(&collection); err != nil {
+			c.Error(apierror.InvalidBody(err))
+			return
+		}
+		collection.Name = strings.TrimSpace(collection.Name)
+		if collection.MovieIDs == nil {
+			collection.MovieIDs = []string{}
+		}
+		if err := validate.Struct(collection); err != nil {
+			c.Error(apierror.Validation(err))
+			return
+		}
+		if err := h.checkMoviesExist(ctx, "movie_ids", collection.MovieIDs); err != nil {
+			c.Error(err)
+			return
+		}
+
+		collection.CollectionID = bson.NewObjectID().Hex()
+		collection.CreatedAt = time.Now()
+		collection.UpdatedAt = collection.CreatedAt
+
+		if err := h.Collections.Create(ctx, collection); err != nil {
+			c.Error(h.collectionWriteError(ctx, collection, err))
+			return
+		}
+
+		c.JSON(http.StatusCreated, collection)
+	}
+}
+
+// UpdateCollection renames a collection, edits its overview or replaces its ordered
+// membership (protected, ADMIN only).
+func (h *Handler) UpdateCollection() gin.HandlerFunc {
+	validate := apierror.NewValidator()
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		collectionId := c.Param("collection_id")
+
+		var req CollectionUpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apierror.InvalidBody(err))
+			return
+		}
+		if req.Name != nil {
+			*req.Name = strings.TrimSpace(*req.Name)
+		}
+		if err := validate.Struct(req); err != nil {
+			c.Error(apierror.Validation(err))
+			return
+		}
+
+		collection, err := h.Collections.FindByID(ctx, collectionId)
+		if err != nil {
+			c.Error(collectionLookupError(collectionId, err))
+			return
+		}
+		if req.Name != nil {
+			collection.Name = *req.Name
+		}
+		if req.Overview != nil {
+			collection.Overview = *req.Overview
+		}
+		if req.MovieIDs != nil {
+			if err := h.checkMoviesExist(ctx, "movie_ids", req.MovieIDs); err != nil {
+				c.Error(err)
+				return
+			}
+			collection.MovieIDs = req.MovieIDs
+		}
+
+		if err := h.Collections.Update(ctx, collection); err != nil {
+			c.Error(h.collectionWriteError(ctx, collection, err))
+			return
+		}
+
+		if collection, err = h.Collections.FindByID(ctx, collectionId); err != nil {
+			c.Error(collectionLookupError(collectionId, err))
+			return
+		}
+		c.JSON(http.StatusOK, collection)
+	}
+}
+
+// DeleteCollection removes a collection; its movies are kept (protected, ADMIN only).
+func (h *Handler) DeleteCollection() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		collectionId := c.Param("collection_id")
+		if err := h.Collections.Delete(ctx, collectionId); err != nil {
+			c.Error(collectionLookupError(collectionId, err))
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// checkMoviesExist reports a field error for every imdb_id in ids with no movie.
+// field names the JSON array in validation errors.
+func (h *Handler) checkMoviesExist(ctx context.Context, field string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	movies, err := h.Movies.ListByImdbIDs(ctx, ids)
+	if err != nil {
+		return apierror.Internal("Failed to fetch movies", err)
+	}
+	exists := make(map[string]bool, len(movies))
+	for _, m := range movies {
+		exists[m.ImdbID] = true
+	}
+
+	var fields []apierror.FieldError
+	for i, id := range ids {
+		if !exists[id] {
+			fields = append(fields, apierror.FieldError{
+				Field:   fmt.Sprintf("%s[%d]", field, i),
+				Rule:    "exists",
+				Message: fmt.Sprintf("movie %q does not exist", id),
+			})
+		}
+	}
+	if len(fields) > 0 {
+		e := apierror.BadRequest(apierror.CodeValidationFailed, "One or more fields are invalid")
+		e.Fields = fields
+		return e
+	}
+	return nil
+}
+
+// collectionWriteError maps a Create or Update failure. On ErrConflict it names the
+// first movie already held by another collection.
+func (h *Handler) collectionWriteError(ctx context.Context, collection models.Collection, err error) *apierror.Error {
+	if !errors.Is(err, repository.ErrConflict) {
+		return collectionLookupError(collection.CollectionID, err)
+	}
+	for _, id := range collection.MovieIDs {
+		other, findErr := h.Collections.FindByMovie(ctx, id)
+		if findErr == nil && other.CollectionID != collection.CollectionID {
+			return apierror.Conflict(apierror.CodeMovieInCollection,
+				fmt.Sprintf("Movie %s already belongs to collection %q (%s)", id, other.Name, other.CollectionID))
+		}
+	}
+	return apierror.Conflict(apierror.CodeMovieInCollection, "A movie already belongs to another collection")
+}
+
+func collectionLookupError(collectionId string, err error) *apierror.Error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierror.NotFound(apierror.CodeCollectionNotFound, "No collection with collection_id "+collectionId)
+	}
+	return apierror.Internal("Failed to access collection", err)
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/enrichment"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+)
+
+// PreviewEnrichment fetches a movie's metadata from the external movie database and
+// shows it merged into the stored movie, without saving (protected, ADMIN only).
+// For a movie not yet in the catalogue the result is a draft for AddMovie.
+func (h *Handler) PreviewEnrichment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.Enrichment == nil {
+			c.Error(enrichmentDisabled())
+			return
+		}
+
+		imdbID := c.Param("imdb_id")
+		preview, err := h.Enrichment.Preview(c.Request.Context(), imdbID)
+		if err != nil {
+			c.Error(enrichmentError(imdbID, err))
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+	}
+}
+
+// EnrichMovie fills in a stored movie's metadata from the external movie database
+// and saves it (protected, ADMIN only). The admin review, ranking and credits are kept.
+func (h *Handler) EnrichMovie() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.Enrichment == nil {
+			c.Error(enrichmentDisabled())
+			return
+		}
+
+		imdbID := c.Param("imdb_id")
+		preview, err := h.Enrichment.Apply(c.Request.Context(), imdbID)
+		if err != nil {
+			c.Error(enrichmentError(imdbID, err))
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+	}
+}
+
+func enrichmentDisabled() *apierror.Error {
+	return apierror.New(http.StatusServiceUnavailable, apierror.CodeEnrichmentDisabled,
+		"Metadata enrichment is not configured on this server")
+}
+
+func enrichmentError(imdbID string, err error) *apierror.Error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return apierror.NotFound(apierror.CodeMovieNotFound, "No movie with imdb_id "+imdbID)
+	case errors.Is(err, enrichment.ErrNoMetadata):
+		return apierror.NotFound(apierror.CodeMetadataNotFound, "The movie database has no movie with imdb_id "+imdbID)
+	case errors.Is(err, enrichment.ErrUnavailable):
+		return apierror.New(http.StatusBadGateway, apierror.CodeEnrichmentFailed,
+			"The movie database could not be reached; try again later").Wrap(err)
+	}
+	return apierror.Internal("Failed to enrich movie", err)
+}
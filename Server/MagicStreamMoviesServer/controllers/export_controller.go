@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/exporter"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/logging"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+)
+
+// exportWriteWindow is how long the client gets to accept each exportDeadlineEvery
+// rows. The deadline rolls forward, so a large export is not cut off by the
+// server's overall write timeout while a stalled client still is.
+const (
+	exportWriteWindow   = 30 * time.Second
+	exportDeadlineEvery = 500
+)
+
+// ExportMovies streams the movies matching the GetMovies filters as CSV, JSON Lines
+// or Excel-friendly CSV, one flat record per movie (protected, ADMIN only).
+func (h *Handler) ExportMovies() gin.HandlerFunc {
+	validate := apierror.NewValidator()
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var q ExportQuery
+		if err := c.ShouldBindQuery(&q); err != nil {
+			c.Error(apierror.BadRequest(apierror.CodeInvalidParameter, "Numeric filters must be integers").Wrap(err))
+			return
+		}
+		if err := validate.Struct(q.MovieListQuery); err != nil {
+			c.Error(apierror.Validation(err))
+			return
+		}
+		if err := validate.Struct(q); err != nil {
+			c.Error(apierror.Validation(err))
+			return
+		}
+		fields, err := exporter.ParseFields(q.Fields)
+		if err != nil {
+			e := apierror.BadRequest(apierror.CodeValidationFailed, "One or more fields are invalid")
+			e.Fields = []apierror.FieldError{{Field: "fields", Rule: "oneof", Message: err.Error()}}
+			c.Error(e)
+			return
+		}
+		format := exporter.FormatCSV
+		if q.Format != "" {
+			format = exporter.Format(q.Format)
+		}
+
+		// Headers are set on the first row, so a failure before it still gets a problem response.
+		started := false
+		start := func() {
+			started = true
+			c.Header("Content-Type", format.ContentType())
+			c.Header("Content-Disposition",
+				`attachment; filename="movies-`+time.Now().UTC().Format("20060102")+"."+format.Extension()+`"`)
+			c.Status(http.StatusOK)
+		}
+
+		rc := http.NewResponseController(c.Writer)
+		out := exporter.NewWriter(c.Writer, format, fields)
+		rows := 0
+		err = h.Movies.Stream(ctx, q.filter(), func(m models.Movie) error {
+			if !started {
+				start()
+			}
+			if rows%exportDeadlineEvery == 0 {
+				rc.SetWriteDeadline(time.Now().Add(exportWriteWindow)) // best effort; unsupported writers keep the server timeout
+			}
+			rows++
+			return out.Write(m)
+		})
+		if err == nil {
+			if !started {
+				start()
+			}
+			err = out.Flush()
+		}
+		if err == nil {
+			return
+		}
+
+		if !started {
+			c.Error(apierror.Internal("Failed to export movies", err))
+			return
+		}
+		// The status line is gone; abort the connection so the client sees a
+		// truncated transfer rather than a short but apparently complete file.
+		logging.FromContext(ctx).Error("movie export aborted", "error", err, "rows", rows)
+		panic(http.ErrAbortHandler)
+	}
+}
@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+)
+
+// GetGenre returns a single genre by genre_id (public)
+func (h *Handler) GetGenre() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		genreId, ok := genreIDParam(c)
+		if !ok {
+			return
+		}
+
+		genre, err := h.Genres.FindByID(ctx, genreId)
+		if err != nil {
+			c.Error(genreLookupError(genreId, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, genre)
+	}
+}
+
+// CreateGenre adds a genre (protected, ADMIN only). Both genre_id and genre_name
+// (case-insensitively) must be unused.
+func (h *Handler) CreateGenre() gin.HandlerFunc {
+	validate := apierror.NewValidator()
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var genre models.Genre
+		if err := c.ShouldBindJSON(&genre); err != nil {
+			c.Error(apierror.InvalidBody(err))
+			return
+		}
+		genre.GenreName = strings.TrimSpace(genre.GenreName)
+		if err := validate.Struct(genre); err != nil {
+			c.Error(apierror.Validation(err))
+			return
+		}
+
+		if err := h.checkGenreNameFree(ctx, genre.GenreName, genre.GenreId); err != nil {
+			c.Error(err)
+			return
+		}
+
+		err := h.Genres.Create(ctx, genre)
+		if errors.Is(err, repository.ErrConflict) {
+			c.Error(apierror.Conflict(apierror.CodeGenreExists, fmt.Sprintf("A genre with genre_id %d already exists", genre.GenreId)))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("Failed to create genre", err))
+			return
+		}
+
+		c.JSON(http.StatusCreated, genre)
+	}
+}
+
+// RenameGenre renames a genre and rewrites the copies embedded in movies and users,
+// so recommendations keep matching (protected, ADMIN only).
+func (h *Handler) RenameGenre() gin.HandlerFunc {
+	validate := apierror.NewValidator()
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		genreId, ok := genreIDParam(c)
+		if !ok {
+			return
+		}
+
+		var req GenreRenameRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apierror.InvalidBody(err))
+			return
+		}
+		req.GenreName = strings.TrimSpace(req.GenreName)
+		if err := validate.Struct(req); err != nil {
+			c.Error(apierror.Validation(err))
+			return
+		}
+
+		if err := h.checkGenreNameFree(ctx, req.GenreName, genreId); err != nil {
+			c.Error(err)
+			return
+		}
+
+		cascade, err := repository.RenameGenre(ctx, h.store(), genreId, req.GenreName)
+		if err != nil {
+			c.Error(genreLookupError(genreId, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, GenreChangeResponse{
+			Message:       "Genre renamed",
+			Genre:         models.Genre{GenreId: genreId, GenreName: req.GenreName},
+			MoviesUpdated: cascade.Movies,
+			UsersUpdated:  cascade.Users,
+		})
+	}
+}
+
+// DeleteGenre removes a genre that no movie or user references (protected, ADMIN only).
+// Genres still in use must be merged into another genre instead.
+func (h *Handler) DeleteGenre() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		genreId, ok := genreIDParam(c)
+		if !ok {
+			return
+		}
+
+		if _, err := h.Genres.FindByID(ctx, genreId); err != nil {
+			c.Error(genreLookupError(genreId, err))
+			return
+		}
+
+		movies, err := h.Movies.CountByGenre(ctx, genreId)
+		if err != nil {
+			c.Error(apierror.Internal("Failed to check genre usage", err))
+			return
+		}
+		users, err := h.Users.CountByFavouriteGenre(ctx, genreId)
+		if err != nil {
+			c.Error(apierror.Internal("Failed to check genre usage", err))
+			return
+		}
+		if movies > 0 || users > 0 {
+			c.Error(apierror.Conflict(apierror.CodeGenreInUse, fmt.Sprintf(
+				"Genre %d is used by %d movie(s) and %d user(s); merge it into another genre instead", genreId, movies, users)))
+			return
+		}
+
+		if err := h.Genres.Delete(ctx, genreId); err != nil {
+			c.Error(genreLookupError(genreId, err))
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// MergeGenre rewrites every movie and user copy of the path genre into into_genre_id,
+// then deletes the path genre (protected, ADMIN only).
+func (h *Handler) MergeGenre() gin.HandlerFunc {
+	validate := apierror.NewValidator()
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		sourceId, ok := genreIDParam(c)
+		if !ok {
+			return
+		}
+
+		var req GenreMergeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apierror.InvalidBody(err))
+			return
+		}
+		if err := validate.Struct(req); err != nil {
+			c.Error(apierror.Validation(err))
+			return
+		}
+		if req.IntoGenreId == sourceId {
+			c.Error(apierror.BadRequest(apierror.CodeInvalidParameter, "A genre cannot be merged into itself"))
+			return
+		}
+
+		if _, err := h.Genres.FindByID(ctx, sourceId); err != nil {
+			c.Error(genreLookupError(sourceId, err))
+			return
+		}
+		target, err := h.Genres.FindByID(ctx, req.IntoGenreId)
+		if err != nil {
+			c.Error(genreLookupError(req.IntoGenreId, err))
+			return
+		}
+
+		cascade, err := repository.MergeGenres(ctx, h.store(), sourceId, target)
+		if err != nil {
+			c.Error(apierror.Internal("Failed to merge genres", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, GenreChangeResponse{
+			Message:       fmt.Sprintf("Genre %d merged", sourceId),
+			Genre:         target,
+			MoviesUpdated: cascade.Movies,
+			UsersUpdated:  cascade.Users,
+		})
+	}
+}
+
+// knownGenres checks that every submitted genre exists by genre_id and returns them
+// with their stored names, so embedded copies always match the genres collection.
+// field names the JSON array in validation errors, e.g. "genre" or "favourite_genres".
+func (h *Handler) knownGenres(ctx context.Context, field string, submitted []models.Genre) ([]models.Genre, error) {
+	all, err := h.Genres.List(ctx)
+	if err != nil {
+		return nil, apierror.Internal("Failed to fetch genres", err)
+	}
+	names := make(map[int]string, len(all))
+	for _, g := range all {
+		names[g.GenreId] = g.GenreName
+	}
+
+	var fields []apierror.FieldError
+	genres := make([]models.Genre, 0, len(submitted))
+	for i, g := range submitted {
+		name, ok := names[g.GenreId]
+		if !ok {
+			fields = append(fields, apierror.FieldError{
+				Field:   fmt.Sprintf("%s[%d].genre_id", field, i),
+				Rule:    "exists",
+				Message: fmt.Sprintf("genre %d does not exist", g.GenreId),
+			})
+			continue
+		}
+		genres = append(genres, models.Genre{GenreId: g.GenreId, GenreName: name})
+	}
+	if len(fields) > 0 {
+		e := apierror.BadRequest(apierror.CodeValidationFailed, "One or more fields are invalid")
+		e.Fields = fields
+		return nil, e
+	}
+	return genres, nil
+}
+
+// checkGenreNameFree returns a 409 genre_exists error when a genre other than
+// genreId already has name, compared case-insensitively.
+func (h *Handler) checkGenreNameFree(ctx context.Context, name string, genreId int) error {
+	genres, err := h.Genres.List(ctx)
+	if err != nil {
+		return apierror.Internal("Failed to fetch genres", err)
+	}
+	for _, g := range genres {
+		if g.GenreId != genreId && strings.EqualFold(g.GenreName, name) {
+			return apierror.Conflict(apierror.CodeGenreExists, fmt.Sprintf("Genre %d is already named %q", g.GenreId, g.GenreName))
+		}
+	}
+	return nil
+}
+
+// genreIDParam parses the :genre_id path parameter, reporting a 400 if it is not an integer.
+func genreIDParam(c *gin.Context) (int, bool) {
+	genreId, err := strconv.Atoi(c.Param("genre_id"))
+	if err != nil {
+		c.Error(apierror.BadRequest(apierror.CodeInvalidParameter, "genre_id must be an integer"))
+		return 0, false
+	}
+	return genreId, true
+}
+
+func genreLookupError(genreId int, err error) *apierror.Error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierror.NotFound(apierror.CodeGenreNotFound, fmt.Sprintf("No genre with genre_id %d", genreId))
+	}
+	return apierror.Internal("Failed to access genre", err)
+}
@@ -0,0 +1,86 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/cache"
+	controller "github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/controllers"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+)
+
+// getGenres fetches the genre list with the given request headers.
+func getGenres(api *testAPI, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/genres", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	api.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestCatalogueETag checks that a write made behind the API's back, as the admin
+// CLI or another instance would, changes the ETag of catalogue reads.
+func TestCatalogueETag(t *testing.T) {
+	api := newTestAPI(t)
+
+	rec := getGenres(api, nil)
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" {
+		t.Fatalf("GetGenres: %d, ETag %q", rec.Code, etag)
+	}
+
+	rec = getGenres(api, map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Fatalf("GetGenres with a current ETag: %d %s, want 304 and no body", rec.Code, rec.Body)
+	}
+
+	if err := api.h.Genres.Create(context.Background(), models.Genre{GenreId: 3, GenreName: "Comedy"}); err != nil {
+		t.Fatalf("Create genre: %v", err)
+	}
+	rec = getGenres(api, map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Fatalf("GetGenres after a write: %d, ETag %q, want 200 and a new ETag", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+// TestCatalogueVersion checks the validators taken from a shared cache generation,
+// with an LRU standing in for Redis and a second wrapped store for the admin CLI.
+func TestCatalogueVersion(t *testing.T) {
+	shared := cache.NewLRU(100)
+	db := repository.NewMemoryStore(action, drama)
+	h := controller.NewHandler(cache.Wrap(db, shared, time.Hour))
+	h.CatalogVersion = cache.NewVersion(shared, time.Hour)
+	api := newTestAPIFor(t, h)
+
+	rec := getGenres(api, nil)
+	etag, modified := rec.Header().Get("ETag"), rec.Header().Get("Last-Modified")
+	if rec.Code != http.StatusOK || etag == "" || modified == "" {
+		t.Fatalf("GetGenres: %d, ETag %q, Last-Modified %q", rec.Code, etag, modified)
+	}
+	if rec := getGenres(api, map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified {
+		t.Errorf("GetGenres with a current ETag: %d, want 304", rec.Code)
+	}
+	if rec := getGenres(api, map[string]string{"If-Modified-Since": modified}); rec.Code != http.StatusNotModified {
+		t.Errorf("GetGenres with a current If-Modified-Since: %d, want 304", rec.Code)
+	}
+
+	cli := cache.Wrap(db, shared, time.Hour)
+	if err := cli.Genres.Create(context.Background(), models.Genre{GenreId: 3, GenreName: "Comedy"}); err != nil {
+		t.Fatalf("Create genre: %v", err)
+	}
+	rec = getGenres(api, map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Fatalf("GetGenres after a write: %d, ETag %q, want 200 and a new ETag", rec.Code, rec.Header().Get("ETag"))
+	}
+	var genres []models.Genre
+	decode(t, rec, &genres)
+	if len(genres) != 3 {
+		t.Errorf("GetGenres after a write returned %d genres, want 3", len(genres))
+	}
+}
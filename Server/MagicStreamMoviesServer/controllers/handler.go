@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"os"
+	"strings"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/blobstore"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/enrichment"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/httpcache"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/importer"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/poster"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/signedurl"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/trailer"
+)
+
+// Handler holds the repositories the HTTP handlers depend on.
+// Build it with repository.NewMongoStore in production or repository.NewMemoryStore in tests.
+type Handler struct {
+	Movies      repository.MovieRepository
+	Users       repository.UserRepository
+	Genres      repository.GenreRepository
+	People      repository.PersonRepository
+	Collections repository.CollectionRepository
+
+	// CatalogVersion validates catalogue reads without running them; nil when no
+	// shared cache is configured, and catalogue ETags are then hashes of the body.
+	CatalogVersion httpcache.Versioner
+
+	// Imports runs bulk movie imports in the background. Close it on shutdown.
+	Imports *importer.Jobs
+	// ImportMaxBytes caps the size of an uploaded import file.
+	ImportMaxBytes int64
+
+	// Enrichment fetches movie metadata from the external movie database; nil when
+	// it is not configured, and the enrichment routes then answer 503.
+	Enrichment *enrichment.Service
+
+	// Blobs stores uploaded poster images and trailers; nil when no blob store is
+	// configured, and the poster and trailer routes then answer 503.
+	Blobs blobstore.BlobStore
+	// PosterMaxBytes and TrailerMaxBytes cap the size of uploaded posters and trailers.
+	PosterMaxBytes  int64
+	TrailerMaxBytes int64
+	// ImageBaseURL prefixes the URLs of stored images: a CDN or absolute URL, or a
+	// path resolved against PublicURL.
+	ImageBaseURL string
+	// PublicURL is the scheme and host clients reach the API at (PUBLIC_BASE_URL),
+	// used to make media URLs absolute. It is configured rather than taken from the
+	// request because poster paths are stored and Host headers are client-supplied.
+	PublicURL string
+	// MediaURLs signs the short-lived trailer links; nil when no signing key is set.
+	MediaURLs *signedurl.Signer
+}
+
+// NewHandler returns a Handler using the repositories in store.
+func NewHandler(store repository.Store) *Handler {
+	h := &Handler{
+		Movies:      store.Movies,
+		Users:       store.Users,
+		Genres:      store.Genres,
+		People:      store.People,
+		Collections: store.Collections,
+
+		ImportMaxBytes: importer.MaxUploadBytes(),
+		PosterMaxBytes: poster.MaxUploadBytes(),
+		ImageBaseURL:   poster.BaseURL(),
+		PublicURL:      strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/"),
+
+		TrailerMaxBytes: trailer.MaxUploadBytes(),
+		MediaURLs:       signedurl.New(signedurl.LoadConfig()),
+	}
+	h.Imports = importer.NewJobs(importer.New(store))
+	return h
+}
+
+// store regroups the handler's repositories for operations that span several of them.
+func (h *Handler) store() repository.Store {
+	return repository.Store{
+		Movies:      h.Movies,
+		Users:       h.Users,
+		Genres:      h.Genres,
+		People:      h.People,
+		Collections: h.Collections,
+	}
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/importer"
+)
+
+// ImportMovies starts a bulk import of the CSV or JSON Lines file sent as the request
+// body (protected, ADMIN only). Rows are upserted by imdb_id in batches once the
+// upload is complete; the 202 response is the queued job, to be polled at its Location.
+func (h *Handler) ImportMovies() gin.HandlerFunc {
+	validate := apierror.NewValidator()
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var q ImportQuery
+		if err := c.ShouldBindQuery(&q); err != nil {
+			c.Error(apierror.BadRequest(apierror.CodeInvalidParameter, "dry_run must be a boolean and batch_size an integer").Wrap(err))
+			return
+		}
+		if err := validate.Struct(q); err != nil {
+			c.Error(apierror.Validation(err))
+			return
+		}
+
+		source := q.Format
+		if source == "" {
+			source = c.ContentType()
+		}
+		format, ok := importer.ParseFormat(source)
+		if !ok {
+			c.Error(apierror.BadRequest(apierror.CodeInvalidParameter,
+				"Pass format=csv or format=jsonl, or send the file as text/csv or application/x-ndjson"))
+			return
+		}
+
+		file, err := h.spoolUpload(c, h.ImportMaxBytes, "Import files")
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		job := h.Imports.Start(ctx, file, importer.Options{Format: format, DryRun: q.DryRun, BatchSize: q.BatchSize})
+		c.Header("Location", c.Request.URL.Path+"/"+job.JobID)
+		c.JSON(http.StatusAccepted, job)
+	}
+}
+
+// GetImportJob reports the progress of an import and its per-row errors (protected, ADMIN only).
+func (h *Handler) GetImportJob() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jobId := c.Param("job_id")
+		job, ok := h.Imports.Get(jobId)
+		if !ok {
+			c.Error(apierror.NotFound(apierror.CodeImportNotFound, "No import with job_id "+jobId))
+			return
+		}
+
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+// spoolUpload copies the request body, up to maxBytes, into a temporary file so it
+// can outlive the request or be read more than once. what names the upload in the
+// 413 response. Closing the file removes it.
+//
+// The read deadline rolls forward with each read, as for trailer streams, so a
+// large upload is limited by the client's pace rather than the server's ReadTimeout.
+func (h *Handler) spoolUpload(c *gin.Context, maxBytes int64, what string) (*tempFile, error) {
+	tmp, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, apierror.Internal("Failed to store the upload", err)
+	}
+	file := &tempFile{File: tmp}
+
+	rc := http.NewResponseController(c.Writer)
+	body := rollingReader{ReadCloser: c.Request.Body, extend: rc.SetReadDeadline}
+	n, err := io.Copy(tmp, http.MaxBytesReader(c.Writer, body, maxBytes))
+	if err == nil && n == 0 {
+		err = io.EOF
+	}
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			return nil, apierror.New(http.StatusRequestEntityTooLarge, apierror.CodePayloadTooLarge,
+				what+" are limited to "+formatBytes(tooLarge.Limit)).Wrap(err)
+		case errors.Is(err, io.EOF):
+			return nil, apierror.BadRequest(apierror.CodeInvalidParameter, "Request body is empty; send the file as the body")
+		}
+		return nil, apierror.Internal("Failed to store the upload", err)
+	}
+	return file, nil
+}
+
+// tempFile is a temporary file that is deleted when closed.
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	if removeErr := os.Remove(f.Name()); err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+// formatBytes renders n as a whole number of MiB when it is one, else in bytes.
+func formatBytes(n int64) string {
+	if n >= 1<<20 && n%(1<<20) == 0 {
+		return strconv.FormatInt(n>>20, 10) + " MiB"
+	}
+	return strconv.FormatInt(n, 10) + " bytes"
+}
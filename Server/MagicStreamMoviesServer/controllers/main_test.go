@@ -0,0 +1,160 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	controller "github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/controllers"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/routes"
+)
+
+var (
+	action = models.Genre{GenreId: 1, GenreName: "Action"}
+	drama  = models.Genre{GenreId: 2, GenreName: "Drama"}
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("SECRET_KEY", "test-access-secret")
+	os.Setenv("SECRET_REFRESH_KEY", "test-refresh-secret")
+	os.Exit(m.Run())
+}
+
+// testAPI is the API routes mounted over an in-memory store.
+type testAPI struct {
+	t      *testing.T
+	h      *controller.Handler
+	router *gin.Engine
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	return newTestAPIFor(t, controller.NewHandler(repository.NewMemoryStore(action, drama)))
+}
+
+// newTestAPIFor mounts the API routes over h.
+func newTestAPIFor(t *testing.T, h *controller.Handler) *testAPI {
+	t.Helper()
+	t.Cleanup(func() { h.Imports.Close(context.Background()) })
+
+	router := gin.New()
+	router.Use(apierror.Middleware())
+	router.NoRoute(apierror.NoRoute())
+	routes.Register(router, routes.Table(h), routes.LegacySunset())
+	return &testAPI{t: t, h: h, router: router}
+}
+
+// session is one client, keeping the cookies the API sets like a browser would.
+type session struct {
+	api     *testAPI
+	cookies map[string]*http.Cookie
+}
+
+func (a *testAPI) session() *session {
+	return &session{api: a, cookies: map[string]*http.Cookie{}}
+}
+
+// do sends body, if not nil, as JSON.
+func (s *session) do(method, path string, body any) *httptest.ResponseRecorder {
+	s.api.t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			s.api.t.Fatalf("encode body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return s.send(req)
+}
+
+// send serves req with the session's cookies.
+func (s *session) send(req *http.Request) *httptest.ResponseRecorder {
+	for _, c := range s.cookies {
+		req.AddCookie(c)
+	}
+
+	rec := httptest.NewRecorder()
+	s.api.router.ServeHTTP(rec, req)
+	for _, c := range rec.Result().Cookies() {
+		if c.MaxAge < 0 {
+			delete(s.cookies, c.Name)
+		} else {
+			s.cookies[c.Name] = c
+		}
+	}
+	return rec
+}
+
+// register signs up a user with the given email and returns the session.
+func (a *testAPI) register(email string) (*session, controller.UserEnvelope) {
+	a.t.Helper()
+	s := a.session()
+	rec := s.do(http.MethodPost, "/api/v1/auth/register", map[string]any{
+		"first_name": "Test", "last_name": "User", "email": email, "password": "secret123",
+		"favourite_genres": []models.Genre{action},
+	})
+	if rec.Code != http.StatusCreated {
+		a.t.Fatalf("register %s: %d %s", email, rec.Code, rec.Body)
+	}
+	var env controller.UserEnvelope
+	decode(a.t, rec, &env)
+	return s, env
+}
+
+// admin registers a user, promotes it to ADMIN and logs it in again so the token carries the role.
+func (a *testAPI) admin(email string) *session {
+	a.t.Helper()
+	s, env := a.register(email)
+	if err := a.h.Users.UpdateRole(context.Background(), env.User.UserID, models.RoleAdmin); err != nil {
+		a.t.Fatalf("UpdateRole: %v", err)
+	}
+	if rec := s.do(http.MethodPost, "/api/v1/auth/login", models.UserLogin{Email: email, Password: "secret123"}); rec.Code != http.StatusOK {
+		a.t.Fatalf("admin login: %d %s", rec.Code, rec.Body)
+	}
+	return s
+}
+
+// problem is the problem+json body of an error response.
+type problem struct {
+	Status int                   `json:"status"`
+	Code   string                `json:"code"`
+	Detail string                `json:"detail"`
+	Errors []apierror.FieldError `json:"errors"`
+}
+
+// expectProblem checks that rec is a problem+json response with status and code.
+func expectProblem(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) problem {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, status, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, apierror.ContentType) {
+		t.Errorf("Content-Type = %q, want %q", ct, apierror.ContentType)
+	}
+	var p problem
+	decode(t, rec, &p)
+	if p.Status != status || p.Code != code {
+		t.Errorf("problem = %+v, want status %d code %q", p, status, code)
+	}
+	return p
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body, err)
+	}
+}
@@ -1,37 +1,35 @@
 package controllers
 
 import (
-	"context"
+	"errors"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
-	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/database"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
 	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
 	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/utils"
-	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
-	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
-// GetMovies returns all movies (public)
-func GetMovies(client *mongo.Client) gin.HandlerFunc {
+// GetMovies returns all movies, narrowed by the MovieListQuery filters (public)
+func (h *Handler) GetMovies() gin.HandlerFunc {
+	validate := apierror.NewValidator()
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		defer cancel()
+		ctx := c.Request.Context()
 
-		movieCollection := database.OpenCollection("movies", client)
-		var movies []models.Movie
-		cursor, err := movieCollection.Find(ctx, bson.M{})
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch movies"})
+		var q MovieListQuery
+		if err := c.ShouldBindQuery(&q); err != nil {
+			c.Error(apierror.BadRequest(apierror.CodeInvalidParameter, "Numeric filters must be integers").Wrap(err))
+			return
+		}
+		if err := validate.Struct(q); err != nil {
+			c.Error(apierror.Validation(err))
 			return
 		}
-		defer cursor.Close(ctx)
 
-		if err := cursor.All(ctx, &movies); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decode movies"})
+		movies, err := h.Movies.List(ctx, q.filter())
+		if err != nil {
+			c.Error(apierror.Internal("Failed to fetch movies", err))
 			return
 		}
 
@@ -39,81 +37,101 @@ func GetMovies(client *mongo.Client) gin.HandlerFunc {
 	}
 }
 
-// GetMovie returns a single movie by imdb_id (protected)
-func GetMovie(client *mongo.Client) gin.HandlerFunc {
+// GetMovie returns a single movie by imdb_id, with its collection position if any (protected)
+func (h *Handler) GetMovie() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		defer cancel()
+		ctx := c.Request.Context()
 
 		imdbID := c.Param("imdb_id")
 		if imdbID == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "imdb_id is required"})
+			c.Error(apierror.BadRequest(apierror.CodeInvalidParameter, "imdb_id is required"))
 			return
 		}
 
-		movieCollection := database.OpenCollection("movies", client)
-		var movie models.Movie
-		err := movieCollection.FindOne(ctx, bson.M{"imdb_id": imdbID}).Decode(&movie)
+		movie, err := h.Movies.FindByImdbID(ctx, imdbID)
 		if err != nil {
-			if err == mongo.ErrNoDocuments {
-				c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+			if errors.Is(err, repository.ErrNotFound) {
+				c.Error(apierror.NotFound(apierror.CodeMovieNotFound, "No movie with imdb_id "+imdbID))
 				return
 			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch movie"})
+			c.Error(apierror.Internal("Failed to fetch movie", err))
 			return
 		}
 
-		c.JSON(http.StatusOK, movie)
+		detail := MovieDetailResponse{Movie: movie}
+		collection, err := h.Collections.FindByMovie(ctx, imdbID)
+		switch {
+		case err == nil:
+			if ref, ok := collection.RefFor(imdbID); ok {
+				detail.Collection = &ref
+			}
+		case !errors.Is(err, repository.ErrNotFound):
+			c.Error(apierror.Internal("Failed to fetch movie collection", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, detail)
 	}
 }
 
 // AddMovie creates a new movie (protected; consider restricting to ADMIN in production)
-func AddMovie(client *mongo.Client) gin.HandlerFunc {
-	validate := validator.New()
+func (h *Handler) AddMovie() gin.HandlerFunc {
+	validate := apierror.NewValidator()
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		defer cancel()
+		ctx := c.Request.Context()
 
 		var movie models.Movie
 		if err := c.ShouldBindJSON(&movie); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+			c.Error(apierror.InvalidBody(err))
 			return
 		}
 
 		if err := validate.Struct(movie); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+			c.Error(apierror.Validation(err))
+			return
+		}
+
+		genres, err := h.knownGenres(ctx, "genre", movie.Genre)
+		if err != nil {
+			c.Error(err)
 			return
 		}
+		movie.Genre = genres
+
+		if len(movie.Credits) > 0 {
+			credits, err := h.knownCredits(ctx, "credits", movie.Credits)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			movie.Credits = credits
+			movie.Directors, movie.Cast = models.DirectorsAndCast(credits)
+		}
+		movie.FillDefaults()
 
-		movieCollection := database.OpenCollection("movies", client)
-		result, err := movieCollection.InsertOne(ctx, movie)
+		created, err := h.Movies.Create(ctx, movie)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add movie"})
+			if errors.Is(err, repository.ErrConflict) {
+				c.Error(apierror.Conflict(apierror.CodeMovieExists, "A movie with imdb_id "+movie.ImdbID+" already exists"))
+				return
+			}
+			c.Error(apierror.Internal("Failed to add movie", err))
 			return
 		}
 
-		c.JSON(http.StatusCreated, gin.H{"message": "Movie added", "id": result.InsertedID})
+		c.JSON(http.StatusCreated, MovieCreatedResponse{Message: "Movie added", ID: created.ID})
 	}
 }
 
 // GetGenres returns all genres from the genres collection (public).
 // Used by the registration form so users can select favourite genres.
-func GetGenres(client *mongo.Client) gin.HandlerFunc {
+func (h *Handler) GetGenres() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		defer cancel()
+		ctx := c.Request.Context()
 
-		genreCollection := database.OpenCollection("genres", client)
-		cursor, err := genreCollection.Find(ctx, bson.M{})
+		genres, err := h.Genres.List(ctx)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch genres"})
-			return
-		}
-		defer cursor.Close(ctx)
-
-		var genres []models.Genre
-		if err := cursor.All(ctx, &genres); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode genres"})
+			c.Error(apierror.Internal("Failed to fetch genres", err))
 			return
 		}
 
@@ -121,54 +139,26 @@ func GetGenres(client *mongo.Client) gin.HandlerFunc {
 	}
 }
 
-// getUsersFavouriteGenres returns the favourite genre names for a user.
-func getUsersFavouriteGenres(ctx context.Context, userId string, client *mongo.Client) ([]string, error) {
-	userCollection := database.OpenCollection("users", client)
-	var user struct {
-		FavouriteGenres []models.Genre `bson:"favourite_genres" json:"favourite_genres"`
-	}
-	err := userCollection.FindOne(ctx, bson.M{"user_id": userId}, options.FindOne().SetProjection(bson.M{"favourite_genres.genre_name": 1})).Decode(&user)
-	if err != nil {
-		return nil, err
-	}
-	names := make([]string, 0, len(user.FavouriteGenres))
-	for _, g := range user.FavouriteGenres {
-		names = append(names, g.GenreName)
-	}
-	return names, nil
-}
-
 // GetRecommendedMovies returns movies matching the current user's favourite genres, sorted by ranking (protected)
-func GetRecommendedMovies(client *mongo.Client) gin.HandlerFunc {
+func (h *Handler) GetRecommendedMovies() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		defer cancel()
+		ctx := c.Request.Context()
 
 		userId, err := utils.GetUserIdFromContext(c)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "User not authenticated"})
+			c.Error(apierror.Unauthorized(apierror.CodeUnauthenticated, "User not authenticated"))
 			return
 		}
 
-		genreNames, err := getUsersFavouriteGenres(ctx, userId, client)
+		genreNames, err := h.Users.FavouriteGenreNames(ctx, userId)
 		if err != nil || len(genreNames) == 0 {
 			c.JSON(http.StatusOK, []models.Movie{}) // empty list if no genres or user not found
 			return
 		}
 
-		movieCollection := database.OpenCollection("movies", client)
-		opts := options.Find().SetSort(bson.D{{Key: "ranking.ranking_value", Value: 1}}).SetLimit(10)
-		filter := bson.M{"genre.genre_name": bson.M{"$in": genreNames}}
-		cursor, err := movieCollection.Find(ctx, filter, opts)
+		movies, err := h.Movies.ListByGenreNames(ctx, genreNames, 10)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recommended movies"})
-			return
-		}
-		defer cursor.Close(ctx)
-
-		var movies []models.Movie
-		if err := cursor.All(ctx, &movies); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode movies"})
+			c.Error(apierror.Internal("Failed to fetch recommended movies", err))
 			return
 		}
 
@@ -176,40 +166,28 @@ func GetRecommendedMovies(client *mongo.Client) gin.HandlerFunc {
 	}
 }
 
-// AdminReviewUpdate updates a movie's admin_review and ranking (protected, ADMIN only).
+// AdminReviewUpdate updates a movie's admin_review and ranking (protected, ADMIN only;
+// the role is enforced by middleware.RequireAdmin).
 // Body: { "admin_review": "string", "ranking": { "ranking_value": int, "ranking_name": "string" } }.
 // ranking is optional; if omitted, ranking is set to Unrated (999).
-func AdminReviewUpdate(client *mongo.Client) gin.HandlerFunc {
+func (h *Handler) AdminReviewUpdate() gin.HandlerFunc {
+	validate := apierror.NewValidator()
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		defer cancel()
-
-		role, err := utils.GetRoleFromContext(c)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-			return
-		}
-		if role != models.RoleAdmin {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin role required"})
-			return
-		}
+		ctx := c.Request.Context()
 
 		imdbID := c.Param("imdb_id")
 		if imdbID == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "imdb_id is required"})
+			c.Error(apierror.BadRequest(apierror.CodeInvalidParameter, "imdb_id is required"))
 			return
 		}
 
-		var req struct {
-			AdminReview string          `json:"admin_review"`
-			Ranking     *models.Ranking `json:"ranking"`
-		}
+		var req ReviewUpdateRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+			c.Error(apierror.InvalidBody(err))
 			return
 		}
-		if req.AdminReview == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "admin_review is required"})
+		if err := validate.Struct(req); err != nil {
+			c.Error(apierror.Validation(err))
 			return
 		}
 
@@ -218,31 +196,20 @@ func AdminReviewUpdate(client *mongo.Client) gin.HandlerFunc {
 			ranking = *req.Ranking
 		}
 
-		movieCollection := database.OpenCollection("movies", client)
-		filter := bson.M{"imdb_id": imdbID}
-		update := bson.M{
-			"$set": bson.M{
-				"admin_review": req.AdminReview,
-				"ranking": bson.M{
-					"ranking_value": ranking.RankingValue,
-					"ranking_name":  ranking.RankingName,
-				},
-			},
-		}
-		result, err := movieCollection.UpdateOne(ctx, filter, update)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update movie"})
+		err := h.Movies.UpdateReview(ctx, imdbID, req.AdminReview, ranking)
+		if errors.Is(err, repository.ErrNotFound) {
+			c.Error(apierror.NotFound(apierror.CodeMovieNotFound, "No movie with imdb_id "+imdbID))
 			return
 		}
-		if result.MatchedCount == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+		if err != nil {
+			c.Error(apierror.Internal("Failed to update movie", err))
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message":      "Review updated",
-			"admin_review": req.AdminReview,
-			"ranking":      ranking,
+		c.JSON(http.StatusOK, ReviewUpdatedResponse{
+			Message:     "Review updated",
+			AdminReview: req.AdminReview,
+			Ranking:     ranking,
 		})
 	}
 }
@@ -0,0 +1,116 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	controller "github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/controllers"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+)
+
+func validMovie(imdbID string) models.Movie {
+	return models.Movie{
+		ImdbID:      imdbID,
+		Title:       "The Test Movie",
+		PosterPath:  "https://example.com/poster.jpg",
+		YouTubeID:   "dQw4w9WgXcQ",
+		Genre:       []models.Genre{action},
+		AdminReview: "Worth it",
+		Ranking:     models.Ranking{RankingValue: 1, RankingName: "Excellent"},
+	}
+}
+
+func TestAddMovie(t *testing.T) {
+	api := newTestAPI(t)
+	s, _ := api.register("writer@example.com")
+
+	expectProblem(t, api.session().do(http.MethodPost, "/api/v1/movies", validMovie("tt0000001")),
+		http.StatusUnauthorized, apierror.CodeTokenMissing)
+
+	rec := s.do(http.MethodPost, "/api/v1/movies", validMovie("tt0000001"))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("AddMovie: %d %s", rec.Code, rec.Body)
+	}
+	var created controller.MovieCreatedResponse
+	decode(t, rec, &created)
+	if created.ID.IsZero() {
+		t.Errorf("AddMovie returned no id: %s", rec.Body)
+	}
+
+	rec = s.do(http.MethodGet, "/api/v1/movies/tt0000001", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GetMovie: %d %s", rec.Code, rec.Body)
+	}
+	var got controller.MovieDetailResponse
+	decode(t, rec, &got)
+	if got.Title != "The Test Movie" || len(got.Genre) != 1 || got.Genre[0] != action {
+		t.Errorf("GetMovie = %+v", got.Movie)
+	}
+
+	expectProblem(t, s.do(http.MethodPost, "/api/v1/movies", validMovie("tt0000001")),
+		http.StatusConflict, apierror.CodeMovieExists)
+}
+
+func TestAddMovieValidation(t *testing.T) {
+	api := newTestAPI(t)
+	s, _ := api.register("writer@example.com")
+
+	invalid := validMovie("tt0000001")
+	invalid.Title = "X"
+	invalid.PosterPath = "not a url"
+	p := expectProblem(t, s.do(http.MethodPost, "/api/v1/movies", invalid), http.StatusBadRequest, apierror.CodeValidationFailed)
+	rules := map[string]string{}
+	for _, f := range p.Errors {
+		rules[f.Field] = f.Rule
+	}
+	if rules["title"] != "min" || rules["poster_path"] != "url" {
+		t.Errorf("field errors = %+v, want title/min and poster_path/url", p.Errors)
+	}
+
+	unknownGenre := validMovie("tt0000001")
+	unknownGenre.Genre = []models.Genre{{GenreId: 99, GenreName: "Western"}}
+	expectProblem(t, s.do(http.MethodPost, "/api/v1/movies", unknownGenre), http.StatusBadRequest, apierror.CodeValidationFailed)
+
+	rec := s.do(http.MethodPost, "/api/v1/movies", "not an object")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("non-object body: %d, want 400", rec.Code)
+	}
+}
+
+func TestMovieNotFound(t *testing.T) {
+	api := newTestAPI(t)
+	s, _ := api.register("reader@example.com")
+
+	p := expectProblem(t, s.do(http.MethodGet, "/api/v1/movies/tt9999999", nil), http.StatusNotFound, apierror.CodeMovieNotFound)
+	if p.Detail == "" {
+		t.Error("404 problem has no detail")
+	}
+	expectProblem(t, s.do(http.MethodGet, "/api/v1/no-such-route", nil), http.StatusNotFound, apierror.CodeRouteNotFound)
+}
+
+func TestAdminOnlyRoutes(t *testing.T) {
+	api := newTestAPI(t)
+	user, _ := api.register("reader@example.com")
+	admin := api.admin("admin@example.com")
+
+	if rec := user.do(http.MethodPost, "/api/v1/movies", validMovie("tt0000001")); rec.Code != http.StatusCreated {
+		t.Fatalf("AddMovie: %d %s", rec.Code, rec.Body)
+	}
+	review := controller.ReviewUpdateRequest{AdminReview: "Updated review"}
+
+	expectProblem(t, user.do(http.MethodPatch, "/api/v1/movies/tt0000001/review", review), http.StatusForbidden, apierror.CodeForbidden)
+	expectProblem(t, user.do(http.MethodPost, "/api/v1/genres", models.Genre{GenreId: 3, GenreName: "Comedy"}),
+		http.StatusForbidden, apierror.CodeForbidden)
+
+	if rec := admin.do(http.MethodPatch, "/api/v1/movies/tt0000001/review", review); rec.Code != http.StatusOK {
+		t.Errorf("admin review update: %d %s", rec.Code, rec.Body)
+	}
+	expectProblem(t, admin.do(http.MethodPatch, "/api/v1/movies/tt9999999/review", review), http.StatusNotFound, apierror.CodeMovieNotFound)
+
+	if rec := admin.do(http.MethodPost, "/api/v1/genres", models.Genre{GenreId: 3, GenreName: "Comedy"}); rec.Code != http.StatusCreated {
+		t.Fatalf("admin CreateGenre: %d %s", rec.Code, rec.Body)
+	}
+	expectProblem(t, admin.do(http.MethodPost, "/api/v1/genres", models.Genre{GenreId: 3, GenreName: "Comedy again"}),
+		http.StatusConflict, apierror.CodeGenreExists)
+}
@@ -0,0 +1,304 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// defaultPeopleLimit applies when SearchPeople is called without limit.
+const defaultPeopleLimit = 20
+
+// SearchPeople lists people whose name contains q, case-insensitively, ordered by name (public).
+func (h *Handler) SearchPeople() gin.HandlerFunc {
+	validate := apierror.NewValidator()
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var query PeopleQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.Error(apierror.BadRequest(apierror.CodeInvalidParameter, "limit must be an integer").Wrap(err))
+			return
+		}
+		if err := validate.Struct(query); err != nil {
+			c.Error(apierror.Validation(err))
+			return
+		}
+		if query.Limit == 0 {
+			query.Limit = defaultPeopleLimit
+		}
+
+		people, err := h.People.Search(ctx, strings.TrimSpace(query.Q), query.Limit)
+		if err != nil {
+			c.Error(apierror.Internal("Failed to search people", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, people)
+	}
+}
+
+// GetPerson returns a person with their filmography, built from the credits
+// embedded in movies (public).
+func (h *Handler) GetPerson() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		personId := c.Param("person_id")
+		person, err := h.People.FindByID(ctx, personId)
+		if err != nil {
+			c.Error(personLookupError(personId, err))
+			return
+		}
+
+		movies, err := h.Movies.ListByPerson(ctx, personId)
+		if err != nil {
+			c.Error(apierror.Internal("Failed to fetch filmography", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, PersonResponse{Person: person, Filmography: filmography(personId, movies)})
+	}
+}
+
+// CreatePerson adds a person (protected, ADMIN only). person_id is assigned by the server.
+func (h *Handler) CreatePerson() gin.HandlerFunc {
+	validate := apierror.NewValidator()
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var person models.Person
+		if err := c.ShouldBindJSON(&person); err != nil {
+			c.Error(apierror.InvalidBody(err))
+			return
+		}
+		person.Name = strings.TrimSpace(person.Name)
+		if err := validate.Struct(person); err != nil {
+			c.Error(apierror.Validation(err))
+			return
+		}
+
+		person.PersonID = bson.NewObjectID().Hex()
+		person.CreatedAt = time.Now()
+		person.UpdatedAt = person.CreatedAt
+
+		if err := h.People.Create(ctx, person); err != nil {
+			c.Error(apierror.Internal("Failed to create person", err))
+			return
+		}
+
+		c.JSON(http.StatusCreated, person)
+	}
+}
+
+// UpdatePerson changes a person's details (protected, ADMIN only). A new name is
+// also written into the credits of every movie they appear in.
+func (h *Handler) UpdatePerson() gin.HandlerFunc {
+	validate := apierror.NewValidator()
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		personId := c.Param("person_id")
+
+		var req PersonUpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apierror.InvalidBody(err))
+			return
+		}
+		if req.Name != nil {
+			*req.Name = strings.TrimSpace(*req.Name)
+		}
+		if err := validate.Struct(req); err != nil {
+			c.Error(apierror.Validation(err))
+			return
+		}
+
+		person, err := h.People.FindByID(ctx, personId)
+		if err != nil {
+			c.Error(personLookupError(personId, err))
+			return
+		}
+		if req.Name != nil {
+			person.Name = *req.Name
+		}
+		if req.BirthDate != nil {
+			person.BirthDate = *req.BirthDate
+		}
+		if req.Biography != nil {
+			person.Biography = *req.Biography
+		}
+
+		updated, err := repository.UpdatePerson(ctx, h.store(), person)
+		if err != nil {
+			c.Error(personLookupError(personId, err))
+			return
+		}
+
+		if person, err = h.People.FindByID(ctx, personId); err != nil {
+			c.Error(personLookupError(personId, err))
+			return
+		}
+		c.JSON(http.StatusOK, PersonUpdatedResponse{Message: "Person updated", Person: person, MoviesUpdated: updated})
+	}
+}
+
+// DeletePerson removes a person with no movie credits (protected, ADMIN only).
+func (h *Handler) DeletePerson() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		personId := c.Param("person_id")
+		if _, err := h.People.FindByID(ctx, personId); err != nil {
+			c.Error(personLookupError(personId, err))
+			return
+		}
+
+		movies, err := h.Movies.ListByPerson(ctx, personId)
+		if err != nil {
+			c.Error(apierror.Internal("Failed to check person usage", err))
+			return
+		}
+		if len(movies) > 0 {
+			c.Error(apierror.Conflict(apierror.CodePersonInUse, fmt.Sprintf(
+				"Person %s is credited on %d movie(s); remove those credits first", personId, len(movies))))
+			return
+		}
+
+		if err := h.People.Delete(ctx, personId); err != nil {
+			c.Error(personLookupError(personId, err))
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// UpdateMovieCredits replaces a movie's credits (protected, ADMIN only). Directors
+// and cast are rebuilt from the credits.
+func (h *Handler) UpdateMovieCredits() gin.HandlerFunc {
+	validate := apierror.NewValidator()
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		imdbID := c.Param("imdb_id")
+
+		var req CreditsUpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apierror.InvalidBody(err))
+			return
+		}
+		if err := validate.Struct(req); err != nil {
+			c.Error(apierror.Validation(err))
+			return
+		}
+
+		credits, err := h.knownCredits(ctx, "credits", req.Credits)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		err = h.Movies.UpdateCredits(ctx, imdbID, credits)
+		if errors.Is(err, repository.ErrNotFound) {
+			c.Error(apierror.NotFound(apierror.CodeMovieNotFound, "No movie with imdb_id "+imdbID))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("Failed to update credits", err))
+			return
+		}
+
+		movie, err := h.Movies.FindByImdbID(ctx, imdbID)
+		if err != nil {
+			c.Error(apierror.Internal("Failed to fetch movie", err))
+			return
+		}
+		c.JSON(http.StatusOK, movie)
+	}
+}
+
+// knownCredits checks that every credited person exists and returns the credits
+// with their stored names, sorted by billing order. field names the JSON array in
+// validation errors.
+func (h *Handler) knownCredits(ctx context.Context, field string, submitted []models.Credit) ([]models.Credit, error) {
+	ids := make([]string, 0, len(submitted))
+	for _, credit := range submitted {
+		ids = append(ids, credit.PersonID)
+	}
+	people, err := h.People.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, apierror.Internal("Failed to fetch people", err)
+	}
+	names := make(map[string]string, len(people))
+	for _, p := range people {
+		names[p.PersonID] = p.Name
+	}
+
+	var fields []apierror.FieldError
+	credits := make([]models.Credit, 0, len(submitted))
+	for i, credit := range submitted {
+		name, ok := names[credit.PersonID]
+		if !ok {
+			fields = append(fields, apierror.FieldError{
+				Field:   fmt.Sprintf("%s[%d].person_id", field, i),
+				Rule:    "exists",
+				Message: fmt.Sprintf("person %q does not exist", credit.PersonID),
+			})
+			continue
+		}
+		credit.Name = name
+		credits = append(credits, credit)
+	}
+	if len(fields) > 0 {
+		e := apierror.BadRequest(apierror.CodeValidationFailed, "One or more fields are invalid")
+		e.Fields = fields
+		return nil, e
+	}
+	models.SortCredits(credits)
+	return credits, nil
+}
+
+// filmography lists personId's credits across movies, newest release first.
+// Movies without a release date sort last.
+func filmography(personId string, movies []models.Movie) []FilmographyEntry {
+	entries := []FilmographyEntry{}
+	for _, m := range movies {
+		for _, credit := range m.Credits {
+			if credit.PersonID != personId {
+				continue
+			}
+			entries = append(entries, FilmographyEntry{
+				ImdbID:      m.ImdbID,
+				Title:       m.Title,
+				ReleaseDate: m.ReleaseDate,
+				PosterPath:  m.PosterPath,
+				Role:        credit.Role,
+				Character:   credit.Character,
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].ReleaseDate != entries[j].ReleaseDate {
+			return entries[i].ReleaseDate > entries[j].ReleaseDate
+		}
+		return entries[i].Title < entries[j].Title
+	})
+	return entries
+}
+
+func personLookupError(personId string, err error) *apierror.Error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierror.NotFound(apierror.CodePersonNotFound, "No person with person_id "+personId)
+	}
+	return apierror.Internal("Failed to access person", err)
+}
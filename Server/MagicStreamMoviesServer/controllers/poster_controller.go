@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/blobstore"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/httpcache"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/poster"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+)
+
+// multipartOverhead is allowed on top of PosterMaxBytes for the multipart framing.
+const multipartOverhead = 64 << 10
+
+// UploadPoster stores the image sent as the "file" field of a multipart form, with
+// its thumbnails and WebP variants (protected, ADMIN only). The response lists the
+// URL of every variant; use poster_path as a movie's poster_path. The URLs are
+// relative when ImageBaseURL is a path and PublicURL is not set.
+func (h *Handler) UploadPoster() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.Blobs == nil {
+			c.Error(storageDisabled())
+			return
+		}
+
+		p, err := h.storePoster(c)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusCreated, h.posterResponse("", p))
+	}
+}
+
+// UpdateMoviePoster uploads a poster as UploadPoster does and sets it as the movie's
+// poster_path (protected, ADMIN only). poster_path must be absolute, so it needs an
+// absolute ImageBaseURL or PublicURL.
+func (h *Handler) UpdateMoviePoster() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if h.Blobs == nil {
+			c.Error(storageDisabled())
+			return
+		}
+		if _, ok := h.absoluteURL(h.ImageBaseURL); !ok {
+			c.Error(apierror.New(http.StatusServiceUnavailable, apierror.CodePublicURLUnset,
+				"Set PUBLIC_BASE_URL, or an absolute IMAGE_BASE_URL, to set movie posters"))
+			return
+		}
+
+		// Check the movie first so a typo does not leave an orphaned upload behind.
+		imdbID := c.Param("imdb_id")
+		if _, err := h.Movies.FindByImdbID(ctx, imdbID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.Error(apierror.NotFound(apierror.CodeMovieNotFound, "No movie with imdb_id "+imdbID))
+				return
+			}
+			c.Error(apierror.Internal("Failed to fetch movie", err))
+			return
+		}
+
+		p, err := h.storePoster(c)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		resp := h.posterResponse(imdbID, p)
+		if err := h.Movies.UpdatePoster(ctx, imdbID, resp.PosterPath); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.Error(apierror.NotFound(apierror.CodeMovieNotFound, "No movie with imdb_id "+imdbID))
+				return
+			}
+			c.Error(apierror.Internal("Failed to update poster", err))
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// GetPosterImage serves one stored poster variant (public). Variants never change,
+// so they are cacheable for a year; range and conditional requests are supported.
+func (h *Handler) GetPosterImage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		posterID, file := c.Param("poster_id"), c.Param("file")
+		// A missing image may be uploaded later, so errors must not be cached as the image would be.
+		c.Header("Cache-Control", httpcache.NoStore)
+		if h.Blobs == nil {
+			c.Error(storageDisabled())
+			return
+		}
+		if !poster.ValidFile(posterID, file) {
+			c.Error(apierror.NotFound(apierror.CodeImageNotFound, "No poster image "+posterID+"/"+file))
+			return
+		}
+
+		obj, err := h.Blobs.Get(c.Request.Context(), poster.Key(posterID, file))
+		if err != nil {
+			if errors.Is(err, blobstore.ErrNotFound) {
+				c.Error(apierror.NotFound(apierror.CodeImageNotFound, "No poster image "+posterID+"/"+file))
+				return
+			}
+			c.Error(apierror.Internal("Failed to read image", err))
+			return
+		}
+		defer obj.Close()
+
+		c.Header("Cache-Control", httpcache.PublicImmutable)
+		c.Header("Content-Type", obj.ContentType)
+		c.Header("ETag", `"`+posterID+"-"+file+`"`)
+		http.ServeContent(c.Writer, c.Request, file, obj.ModTime, obj)
+	}
+}
+
+// storePoster reads, processes and stores the uploaded poster.
+func (h *Handler) storePoster(c *gin.Context) (poster.Poster, error) {
+	data, err := h.readPosterUpload(c)
+	if err != nil {
+		return poster.Poster{}, err
+	}
+
+	p, err := poster.Process(data)
+	if err != nil {
+		var imageErr *poster.ImageError
+		switch {
+		case errors.Is(err, poster.ErrUnsupportedType):
+			return poster.Poster{}, apierror.New(http.StatusUnsupportedMediaType, apierror.CodeUnsupportedMedia,
+				"Posters must be JPEG, PNG, GIF or WebP images").Wrap(err)
+		case errors.As(err, &imageErr):
+			return poster.Poster{}, fileError("image", imageErr.Reason)
+		}
+		return poster.Poster{}, apierror.Internal("Failed to process image", err)
+	}
+
+	if err := poster.Save(c.Request.Context(), h.Blobs, p); err != nil {
+		return poster.Poster{}, apierror.Internal("Failed to store image", err)
+	}
+	return p, nil
+}
+
+// readPosterUpload returns the contents of the "file" field of a multipart/form-data
+// request, rejecting files over PosterMaxBytes. Other fields are ignored.
+func (h *Handler) readPosterUpload(c *gin.Context) ([]byte, error) {
+	tooLarge := apierror.New(http.StatusRequestEntityTooLarge, apierror.CodePayloadTooLarge,
+		"Posters are limited to "+formatBytes(h.PosterMaxBytes))
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.PosterMaxBytes+multipartOverhead)
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, apierror.BadRequest(apierror.CodeInvalidParameter,
+			`Send the poster as multipart/form-data with the image in a field named "file"`).Wrap(err)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, fileError("required", "is required")
+		}
+		if err != nil {
+			var maxBytes *http.MaxBytesError
+			if errors.As(err, &maxBytes) {
+				return nil, tooLarge.Wrap(err)
+			}
+			return nil, apierror.BadRequest(apierror.CodeInvalidParameter, "Request body is not a valid multipart form").Wrap(err)
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, h.PosterMaxBytes+1))
+		if err != nil {
+			var maxBytes *http.MaxBytesError
+			if errors.As(err, &maxBytes) {
+				return nil, tooLarge.Wrap(err)
+			}
+			return nil, apierror.BadRequest(apierror.CodeInvalidParameter, "Request body is not a valid multipart form").Wrap(err)
+		}
+		if int64(len(data)) > h.PosterMaxBytes {
+			return nil, tooLarge
+		}
+		if len(data) == 0 {
+			return nil, fileError("required", "is empty")
+		}
+		return data, nil
+	}
+}
+
+// posterResponse lists the URLs of p's variants.
+func (h *Handler) posterResponse(imdbID string, p poster.Poster) PosterResponse {
+	base, _ := h.absoluteURL(h.ImageBaseURL)
+
+	resp := PosterResponse{ImdbID: imdbID, PosterID: p.ID, Images: make([]PosterImage, 0, len(p.Images))}
+	for _, img := range p.Images {
+		url := base + "/" + poster.Key(p.ID, img.File)
+		if img.File == p.Images[0].File {
+			resp.PosterPath = url
+		}
+		resp.Images = append(resp.Images, PosterImage{Image: img, URL: url})
+	}
+	return resp
+}
+
+// absoluteURL resolves a path against PublicURL. It returns other URLs unchanged,
+// and reports false, returning the path as is, when PublicURL is not set.
+func (h *Handler) absoluteURL(path string) (string, bool) {
+	if !strings.HasPrefix(path, "/") {
+		return path, true
+	}
+	if h.PublicURL == "" {
+		return path, false
+	}
+	return h.PublicURL + path, true
+}
+
+// fileError reports a problem with the uploaded file like a field validation error.
+func fileError(rule, message string) *apierror.Error {
+	e := apierror.BadRequest(apierror.CodeValidationFailed, "One or more fields are invalid")
+	e.Fields = []apierror.FieldError{{Field: "file", Rule: rule, Message: message}}
+	return e
+}
+
+func storageDisabled() *apierror.Error {
+	return apierror.New(http.StatusServiceUnavailable, apierror.CodeStorageDisabled,
+		"Media storage is not configured on this server")
+}
@@ -0,0 +1,73 @@
+package controllers_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/blobstore"
+	controller "github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/controllers"
+)
+
+// posterUpload is a PUT of a small PNG as the "file" field of a multipart form.
+func posterUpload(t *testing.T, path string) *http.Request {
+	t.Helper()
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 200, 300))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "poster.png")
+	part.Write(img.Bytes())
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPut, path, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+// TestMoviePosterURL checks that the stored poster_path comes from PUBLIC_BASE_URL,
+// never from the Host or X-Forwarded-Proto headers of the request.
+func TestMoviePosterURL(t *testing.T) {
+	api := newTestAPI(t)
+	blobs, err := blobstore.NewFilesystem(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFilesystem: %v", err)
+	}
+	api.h.Blobs = blobs
+	s := api.admin("admin@example.com")
+	if rec := s.do(http.MethodPost, "/api/v1/movies", validMovie("tt0000001")); rec.Code != http.StatusCreated {
+		t.Fatalf("AddMovie: %d %s", rec.Code, rec.Body)
+	}
+
+	expectProblem(t, s.send(posterUpload(t, "/api/v1/movies/tt0000001/poster")),
+		http.StatusServiceUnavailable, apierror.CodePublicURLUnset)
+
+	api.h.PublicURL = "https://api.example.com"
+	req := posterUpload(t, "/api/v1/movies/tt0000001/poster")
+	req.Host = "attacker.example"
+	req.Header.Set("X-Forwarded-Proto", "http")
+	rec := s.send(req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("UpdateMoviePoster: %d %s", rec.Code, rec.Body)
+	}
+	var resp controller.PosterResponse
+	decode(t, rec, &resp)
+	if !strings.HasPrefix(resp.PosterPath, "https://api.example.com/api/v1/images/posters/") {
+		t.Errorf("poster_path = %q, want it under PUBLIC_BASE_URL", resp.PosterPath)
+	}
+
+	rec = s.do(http.MethodGet, "/api/v1/movies/tt0000001", nil)
+	var got controller.MovieDetailResponse
+	decode(t, rec, &got)
+	if got.PosterPath != resp.PosterPath {
+		t.Errorf("stored poster_path = %q, want %q", got.PosterPath, resp.PosterPath)
+	}
+}
@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/poster"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Request and response bodies that are not plain models.
+// They are named so the OpenAPI document can describe them.
+
+// MessageResponse acknowledges an action that returns no resource.
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// UserEnvelope is returned by register, login and profile.
+type UserEnvelope struct {
+	Message string              `json:"message"`
+	User    models.UserResponse `json:"user"`
+}
+
+// MovieCreatedResponse is returned by AddMovie.
+type MovieCreatedResponse struct {
+	Message string        `json:"message"`
+	ID      bson.ObjectID `json:"id"`
+}
+
+// MovieDetailResponse is returned by GetMovie: the movie plus, when it belongs to
+// a collection, where it sits in it.
+type MovieDetailResponse struct {
+	models.Movie
+	Collection *models.CollectionRef `json:"collection,omitempty"`
+}
+
+// MovieListQuery holds the GetMovies filters, bound from the query string.
+// year is shorthand for year_from=year_to=year.
+type MovieListQuery struct {
+	Genre         string `form:"genre" validate:"max=100"`
+	Year          int    `form:"year" validate:"omitempty,gte=1870,lte=2200"`
+	YearFrom      int    `form:"year_from" validate:"omitempty,gte=1870,lte=2200"`
+	YearTo        int    `form:"year_to" validate:"omitempty,gte=1870,lte=2200,gtefield=YearFrom"`
+	Language      string `form:"language" validate:"omitempty,len=2,lowercase,alpha"`
+	Certification string `form:"certification" validate:"max=16"`
+	Director      string `form:"director" validate:"max=200"`
+	Cast          string `form:"cast" validate:"max=200"`
+	RuntimeMin    int    `form:"runtime_min" validate:"omitempty,gte=1,lte=1000"`
+	RuntimeMax    int    `form:"runtime_max" validate:"omitempty,gte=1,lte=1000,gtefield=RuntimeMin"`
+}
+
+// filter converts the query into a repository filter.
+func (q MovieListQuery) filter() repository.MovieFilter {
+	filter := repository.MovieFilter{
+		Genre:         q.Genre,
+		YearFrom:      q.YearFrom,
+		YearTo:        q.YearTo,
+		Language:      q.Language,
+		Certification: q.Certification,
+		Director:      q.Director,
+		CastMember:    q.Cast,
+		RuntimeMin:    q.RuntimeMin,
+		RuntimeMax:    q.RuntimeMax,
+	}
+	if q.Year != 0 {
+		filter.YearFrom, filter.YearTo = q.Year, q.Year
+	}
+	return filter
+}
+
+// ExportQuery holds the ExportMovies options: the GetMovies filters plus the output
+// format and a comma-separated field selection (default: every field).
+type ExportQuery struct {
+	MovieListQuery `validate:"-"` // validated separately so errors name the query parameters
+
+	Format string `form:"format" validate:"omitempty,oneof=csv jsonl excel"`
+	Fields string `form:"fields" validate:"max=1000"`
+}
+
+// ImportQuery holds the ImportMovies options, bound from the query string. format
+// may be omitted when the Content-Type is text/csv or application/x-ndjson.
+type ImportQuery struct {
+	Format    string `form:"format" validate:"omitempty,oneof=csv jsonl"`
+	DryRun    bool   `form:"dry_run"`
+	BatchSize int    `form:"batch_size" validate:"omitempty,gte=1,lte=5000"`
+}
+
+// SignedMediaQuery documents the query string of a link from GetTrailerLink.
+// StreamTrailer checks it with signedurl.Signer.Verify rather than binding it.
+type SignedMediaQuery struct {
+	Expires   int64  `form:"expires" validate:"required"`
+	Signature string `form:"signature" validate:"required"`
+}
+
+// ReviewUpdateRequest is the AdminReviewUpdate body.
+// Ranking is optional; if omitted, ranking is set to Unrated (999).
+type ReviewUpdateRequest struct {
+	AdminReview string          `json:"admin_review" validate:"required"`
+	Ranking     *models.Ranking `json:"ranking" validate:"-"`
+}
+
+// ReviewUpdatedResponse echoes the stored review and ranking.
+type ReviewUpdatedResponse struct {
+	Message     string         `json:"message"`
+	AdminReview string         `json:"admin_review"`
+	Ranking     models.Ranking `json:"ranking"`
+}
+
+// GenreRenameRequest is the RenameGenre body.
+type GenreRenameRequest struct {
+	GenreName string `json:"genre_name" validate:"required,min=2,max=100"`
+}
+
+// GenreMergeRequest is the MergeGenre body: the genre in the path is merged into IntoGenreId.
+type GenreMergeRequest struct {
+	IntoGenreId int `json:"into_genre_id" validate:"required"`
+}
+
+// GenreChangeResponse is returned by RenameGenre and MergeGenre, with the number of
+// movies and users whose embedded genre copies were rewritten.
+type GenreChangeResponse struct {
+	Message       string       `json:"message"`
+	Genre         models.Genre `json:"genre"`
+	MoviesUpdated int64        `json:"movies_updated"`
+	UsersUpdated  int64        `json:"users_updated"`
+}
+
+// PeopleQuery holds the SearchPeople parameters, bound from the query string.
+// An empty q lists everyone by name.
+type PeopleQuery struct {
+	Q     string `form:"q" validate:"max=200"`
+	Limit int    `form:"limit" validate:"omitempty,gte=1,lte=100"`
+}
+
+// PersonUpdateRequest is the UpdatePerson body; omitted fields are left unchanged.
+type PersonUpdateRequest struct {
+	Name      *string `json:"name" validate:"omitempty,min=1,max=200"`
+	BirthDate *string `json:"birth_date" validate:"omitempty,datetime=2006-01-02"`
+	Biography *string `json:"biography" validate:"omitempty,max=10000"`
+}
+
+// PersonUpdatedResponse is returned by UpdatePerson, with the number of movies
+// whose credit copies of the name were rewritten.
+type PersonUpdatedResponse struct {
+	Message       string        `json:"message"`
+	Person        models.Person `json:"person"`
+	MoviesUpdated int64         `json:"movies_updated"`
+}
+
+// FilmographyEntry is one credit of a person: a person credited twice on a movie,
+// e.g. as director and actor, has two entries.
+type FilmographyEntry struct {
+	ImdbID      string `json:"imdb_id"`
+	Title       string `json:"title"`
+	ReleaseDate string `json:"release_date"`
+	PosterPath  string `json:"poster_path"`
+	Role        string `json:"role"`
+	Character   string `json:"character"`
+}
+
+// PersonResponse is returned by GetPerson: the person and their filmography,
+// newest release first.
+type PersonResponse struct {
+	Person      models.Person      `json:"person"`
+	Filmography []FilmographyEntry `json:"filmography"`
+}
+
+// CreditsUpdateRequest is the UpdateMovieCredits body. It replaces every credit of
+// the movie; names are copied from the people collection.
+type CreditsUpdateRequest struct {
+	Credits []models.Credit `json:"credits" validate:"max=500,dive"`
+}
+
+// CollectionUpdateRequest is the UpdateCollection body; omitted fields are left
+// unchanged. movie_ids, when present, replaces the whole ordered membership.
+type CollectionUpdateRequest struct {
+	Name     *string  `json:"name" validate:"omitempty,min=2,max=200"`
+	Overview *string  `json:"overview" validate:"omitempty,max=5000"`
+	MovieIDs []string `json:"movie_ids" validate:"max=100,unique,dive,required"`
+}
+
+// CollectionResponse is returned by GetCollection: the collection and its member
+// movies in collection order.
+type CollectionResponse struct {
+	Collection models.Collection `json:"collection"`
+	Movies     []models.Movie    `json:"movies"`
+}
+
+// PosterResponse is returned by UploadPoster and UpdateMoviePoster. poster_path is
+// the URL of the original image; the other variants are listed in images.
+type PosterResponse struct {
+	ImdbID     string        `json:"imdb_id,omitempty"`
+	PosterID   string        `json:"poster_id"`
+	PosterPath string        `json:"poster_path"`
+	Images     []PosterImage `json:"images"`
+}
+
+// PosterImage is one stored variant of a poster and the URL it is served at.
+type PosterImage struct {
+	poster.Image
+	URL string `json:"url"`
+}
+
+// TrailerResponse describes a movie's self-hosted trailer; UploadTrailer returns it.
+type TrailerResponse struct {
+	ImdbID      string `json:"imdb_id"`
+	ContentType string `json:"content_type"`
+	Bytes       int64  `json:"bytes"`
+}
+
+// TrailerLinkResponse is returned by GetTrailerLink: a signed link to stream the
+// trailer, valid until expires_at.
+type TrailerLinkResponse struct {
+	TrailerResponse
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
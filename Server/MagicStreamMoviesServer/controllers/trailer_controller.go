@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/httpcache"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/signedurl"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/trailer"
+)
+
+// mediaIOWindow is how long a client gets for each read of an upload or trailer
+// stream. The deadline rolls forward, so a large file is not cut off by the
+// server's read and write timeouts while a stalled client still is.
+const mediaIOWindow = 30 * time.Second
+
+// UploadTrailer stores the MP4 or WebM video sent as the request body as the movie's
+// trailer, replacing any previous one (protected, ADMIN only). The format is sniffed
+// from the content.
+func (h *Handler) UploadTrailer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if h.Blobs == nil {
+			c.Error(storageDisabled())
+			return
+		}
+
+		imdbID := c.Param("imdb_id")
+		if _, err := h.Movies.FindByImdbID(ctx, imdbID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.Error(apierror.NotFound(apierror.CodeMovieNotFound, "No movie with imdb_id "+imdbID))
+				return
+			}
+			c.Error(apierror.Internal("Failed to fetch movie", err))
+			return
+		}
+
+		file, err := h.spoolUpload(c, h.TrailerMaxBytes, "Trailers")
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		defer file.Close()
+
+		head := make([]byte, 512)
+		n, err := file.ReadAt(head, 0)
+		if err != nil && !errors.Is(err, io.EOF) {
+			c.Error(apierror.Internal("Failed to read the upload", err))
+			return
+		}
+		contentType := http.DetectContentType(head[:n])
+		if !trailer.Supported(contentType) {
+			c.Error(apierror.New(http.StatusUnsupportedMediaType, apierror.CodeUnsupportedMedia,
+				"Trailers must be MP4 or WebM videos"))
+			return
+		}
+		info, err := file.Stat()
+		if err != nil {
+			c.Error(apierror.Internal("Failed to read the upload", err))
+			return
+		}
+
+		if err := trailer.Save(ctx, h.Blobs, imdbID, file, info.Size(), contentType); err != nil {
+			c.Error(apierror.Internal("Failed to store trailer", err))
+			return
+		}
+		c.JSON(http.StatusOK, TrailerResponse{ImdbID: imdbID, ContentType: contentType, Bytes: info.Size()})
+	}
+}
+
+// DeleteTrailer removes the movie's self-hosted trailer (protected, ADMIN only).
+// Links already issued stop working.
+func (h *Handler) DeleteTrailer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.Blobs == nil {
+			c.Error(storageDisabled())
+			return
+		}
+
+		imdbID := c.Param("imdb_id")
+		if err := trailer.Delete(c.Request.Context(), h.Blobs, imdbID); err != nil {
+			c.Error(trailerError(imdbID, err))
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetTrailerLink issues a short-lived signed link to the movie's self-hosted
+// trailer (protected). The link needs no token, so it can be used as a <video> src.
+// It is relative to the API when PublicURL is not set.
+func (h *Handler) GetTrailerLink() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.Blobs == nil || h.MediaURLs == nil {
+			c.Error(storageDisabled())
+			return
+		}
+
+		imdbID := c.Param("imdb_id")
+		t, err := trailer.Open(c.Request.Context(), h.Blobs, imdbID)
+		if err != nil {
+			c.Error(trailerError(imdbID, err))
+			return
+		}
+		t.Close()
+
+		query, expires := h.MediaURLs.Sign(trailer.Resource(imdbID))
+		link, _ := h.absoluteURL(trailer.StreamPath + url.PathEscape(imdbID) + "?" + query.Encode())
+		c.JSON(http.StatusOK, TrailerLinkResponse{
+			TrailerResponse: TrailerResponse{ImdbID: imdbID, ContentType: t.ContentType, Bytes: t.Size},
+			URL:             link,
+			ExpiresAt:       expires.UTC(),
+		})
+	}
+}
+
+// StreamTrailer serves a trailer to the holder of a link from GetTrailerLink
+// (public, signature checked). Range requests let players seek without
+// downloading the whole file.
+func (h *Handler) StreamTrailer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		c.Header("Cache-Control", httpcache.NoStore)
+		if h.Blobs == nil || h.MediaURLs == nil {
+			c.Error(storageDisabled())
+			return
+		}
+
+		imdbID := c.Param("imdb_id")
+		expires, err := h.MediaURLs.Verify(trailer.Resource(imdbID), c.Request.URL.Query())
+		if err != nil {
+			if errors.Is(err, signedurl.ErrExpired) {
+				c.Error(apierror.Forbidden(apierror.CodeURLExpired, "This trailer link has expired; request a new one"))
+				return
+			}
+			c.Error(apierror.Forbidden(apierror.CodeURLInvalid, "This trailer link is not valid"))
+			return
+		}
+
+		t, err := trailer.Open(ctx, h.Blobs, imdbID)
+		if err != nil {
+			c.Error(trailerError(imdbID, err))
+			return
+		}
+		defer t.Close()
+
+		// The browser may keep the video only as long as the link would have let it fetch it again.
+		maxAge := int(time.Until(expires).Seconds())
+		c.Header("Cache-Control", "private, max-age="+strconv.Itoa(max(maxAge, 0)))
+		c.Header("Content-Type", t.ContentType)
+		c.Header("ETag", `"`+strconv.FormatInt(t.ModTime.UnixNano(), 36)+"-"+strconv.FormatInt(t.Size, 36)+`"`)
+
+		rc := http.NewResponseController(c.Writer)
+		content := rollingReadSeeker{rollingReader{ReadCloser: t, extend: rc.SetWriteDeadline}, t}
+		http.ServeContent(c.Writer, c.Request, "", t.ModTime, content)
+	}
+}
+
+func trailerError(imdbID string, err error) *apierror.Error {
+	if errors.Is(err, trailer.ErrNotFound) {
+		return apierror.NotFound(apierror.CodeTrailerNotFound, "Movie "+imdbID+" has no trailer")
+	}
+	return apierror.Internal("Failed to read trailer", err)
+}
+
+// rollingReader moves a connection deadline mediaIOWindow ahead before each Read.
+type rollingReader struct {
+	io.ReadCloser
+	extend func(time.Time) error
+}
+
+func (r rollingReader) Read(p []byte) (int, error) {
+	r.extend(time.Now().Add(mediaIOWindow)) // best effort; unsupported writers keep the server timeout
+	return r.ReadCloser.Read(p)
+}
+
+// rollingReadSeeker is a seekable rollingReader, for http.ServeContent.
+type rollingReadSeeker struct {
+	rollingReader
+	io.Seeker
+}
@@ -1,43 +1,33 @@
 package controllers
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
-	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/database"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/logging"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/metrics"
 	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
 	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/utils"
 	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
-	"golang.org/x/crypto/bcrypt"
 )
 
-// HashPassword hashes a plain text password using bcrypt
-func HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
-	}
-	return string(hashedPassword), nil
-}
-
 // RegisterUser creates a new user account
-func RegisterUser(client *mongo.Client) gin.HandlerFunc {
+func (h *Handler) RegisterUser() gin.HandlerFunc {
+	validate := apierror.NewValidator()
 	return func(c *gin.Context) {
-		// The original code tried to create a new context with a timeout using "ctx",
-		// but "ctx" was not defined yet. We fix this by starting with context.Background().
-		// This provides a root context suitable for starting new values.
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		defer cancel()
+		// Per-operation deadlines are applied by the repositories (repository.WithTimeouts);
+		// the request context carries the trace span and cancels work when the client disconnects.
+		ctx := c.Request.Context()
 
 		var user models.User
 
 		// 1. Bind JSON request to User struct
 		if err := c.ShouldBindJSON(&user); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+			c.Error(apierror.InvalidBody(err))
 			return
 		}
 
@@ -45,28 +35,34 @@ func RegisterUser(client *mongo.Client) gin.HandlerFunc {
 		user.Role = models.RoleUser
 
 		// 3. Validate input
-		validate := validator.New()
 		if err := validate.Struct(user); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+			c.Error(apierror.Validation(err))
+			return
+		}
+
+		// Favourite genres must exist; their stored names replace the submitted ones
+		favourites, err := h.knownGenres(ctx, "favourite_genres", user.FavouriteGenres)
+		if err != nil {
+			c.Error(err)
 			return
 		}
+		user.FavouriteGenres = favourites
 
 		// 3. Check if email already exists
-		userCollection := database.OpenCollection("users", client)
-		count, err := userCollection.CountDocuments(ctx, bson.D{{Key: "email", Value: user.Email}})
+		exists, err := h.Users.EmailExists(ctx, user.Email)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing user"})
+			c.Error(apierror.Internal("Failed to check existing user", err))
 			return
 		}
-		if count > 0 {
-			c.JSON(http.StatusConflict, gin.H{"error": "User with this email already exists"})
+		if exists {
+			c.Error(apierror.Conflict(apierror.CodeEmailTaken, "User with this email already exists"))
 			return
 		}
 
 		// 4. Hash password
-		hashedPassword, err := HashPassword(user.Password)
+		hashedPassword, err := utils.HashPassword(user.Password)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to hash password"})
+			c.Error(apierror.Internal("Unable to hash password", err))
 			return
 		}
 
@@ -80,20 +76,19 @@ func RegisterUser(client *mongo.Client) gin.HandlerFunc {
 		// Note: Role already set to USER before validation (prevents self-assignment to ADMIN)
 
 		// 6. Insert user into database (no plain-text tokens stored)
-		_, err = userCollection.InsertOne(ctx, user)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		if err := h.Users.Create(ctx, user); err != nil {
+			c.Error(apierror.Internal("Failed to create user", err))
 			return
 		}
 
 		// 7. Generate tokens and store hashed refresh token only
 		accessToken, refreshToken, err := utils.GenerateAllTokens(user)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to generate tokens"})
+			c.Error(apierror.Internal("Unable to generate tokens", err))
 			return
 		}
-		if err := utils.UpdateAllTokens(user.UserID, accessToken, refreshToken, client); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tokens"})
+		if err := utils.UpdateAllTokens(ctx, user.UserID, accessToken, refreshToken, h.Users); err != nil {
+			c.Error(apierror.Internal("Failed to update tokens", err))
 			return
 		}
 
@@ -129,62 +124,72 @@ func RegisterUser(client *mongo.Client) gin.HandlerFunc {
 			FavouriteGenres: user.FavouriteGenres,
 		}
 
-		c.JSON(http.StatusCreated, gin.H{
-			"message": "User registered successfully",
-			"user":    userResponse,
+		c.JSON(http.StatusCreated, UserEnvelope{
+			Message: "User registered successfully",
+			User:    userResponse,
 		})
 	}
 }
 
-func LoginUser(client *mongo.Client) gin.HandlerFunc {
+func (h *Handler) LoginUser() gin.HandlerFunc {
+	validate := apierror.NewValidator()
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		defer cancel()
+		ctx := c.Request.Context()
 
 		var userLogin models.UserLogin
 
 		// 1. Bind JSON request to userLogin struct
 		if err := c.ShouldBindJSON(&userLogin); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+			c.Error(apierror.InvalidBody(err))
 			return
 		}
 
 		// 2. Validate input
-		validate := validator.New()
 		if err := validate.Struct(userLogin); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+			c.Error(apierror.Validation(err))
 			return
 		}
 
 		// 3. Find user by email
-		userCollection := database.OpenCollection("users", client)
-		var foundUser models.User
-		err := userCollection.FindOne(ctx, bson.M{"email": userLogin.Email}).Decode(&foundUser)
+		foundUser, err := h.Users.FindByEmail(ctx, userLogin.Email)
 		if err != nil {
-			if err == mongo.ErrNoDocuments {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+			if errors.Is(err, repository.ErrNotFound) {
+				metrics.RecordLogin(false)
+				c.Error(apierror.Unauthorized(apierror.CodeInvalidCredentials, "Invalid email or password"))
 			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query user"})
+				c.Error(apierror.Internal("Failed to query user", err))
 			}
 			return
 		}
 
-		// 4. Verify password
-		if err := bcrypt.CompareHashAndPassword([]byte(foundUser.Password), []byte(userLogin.Password)); err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		// 4. Verify password (algorithm is taken from the stored hash prefix)
+		ok, err := utils.VerifyPassword(foundUser.Password, userLogin.Password)
+		if err != nil || !ok {
+			metrics.RecordLogin(false)
+			c.Error(apierror.Unauthorized(apierror.CodeInvalidCredentials, "Invalid email or password"))
 			return
 		}
 
+		// 4b. Transparently upgrade hashes made with an outdated algorithm or cost.
+		// Failure here must not block the login; the upgrade is retried next time.
+		if utils.PasswordNeedsRehash(foundUser.Password) {
+			if newHash, err := utils.HashPassword(userLogin.Password); err == nil {
+				if err := h.Users.UpdatePassword(ctx, foundUser.UserID, newHash); err != nil {
+					logging.FromContext(ctx).Warn("failed to upgrade password hash", "error", err)
+				}
+			}
+		}
+
 		// 5. Generate tokens
 		accessToken, refreshToken, err := utils.GenerateAllTokens(foundUser)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to generate tokens"})
+			c.Error(apierror.Internal("Unable to generate tokens", err))
 			return
 		}
 
 		// 6. Update tokens in database (hashed refresh token)
-		if err := utils.UpdateAllTokens(foundUser.UserID, accessToken, refreshToken, client); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tokens"})
+		if err := utils.UpdateAllTokens(ctx, foundUser.UserID, accessToken, refreshToken, h.Users); err != nil {
+			c.Error(apierror.Internal("Failed to update tokens", err))
 			return
 		}
 
@@ -219,16 +224,17 @@ func LoginUser(client *mongo.Client) gin.HandlerFunc {
 			FavouriteGenres: foundUser.FavouriteGenres,
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "User logged in successfully",
-			"user":    userResponse,
+		metrics.RecordLogin(true)
+		c.JSON(http.StatusOK, UserEnvelope{
+			Message: "User logged in successfully",
+			User:    userResponse,
 		})
 	}
 }
 
 // Logout revokes the refresh token and clears cookies.
 // Works with valid access token (userId from context) or refresh token cookie only (e.g. when access token expired).
-func Logout(client *mongo.Client) gin.HandlerFunc {
+func (h *Handler) Logout() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var userId string
 
@@ -241,21 +247,22 @@ func Logout(client *mongo.Client) gin.HandlerFunc {
 			if err != nil {
 				// Invalid refresh token; still clear cookies
 				clearAuthCookies(c)
-				c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+				c.JSON(http.StatusOK, MessageResponse{Message: "Logged out successfully"})
 				return
 			}
 			userId = claims.UserId
 		}
 
 		if userId != "" {
-			if err := utils.RevokeRefreshToken(userId, client); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+			if err := utils.RevokeRefreshToken(c.Request.Context(), userId, h.Users); err != nil {
+				c.Error(apierror.Internal("Failed to logout", err))
 				return
 			}
+			metrics.RecordRevocation()
 		}
 
 		clearAuthCookies(c)
-		c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+		c.JSON(http.StatusOK, MessageResponse{Message: "Logged out successfully"})
 	}
 }
 
@@ -266,50 +273,50 @@ func clearAuthCookies(c *gin.Context) {
 }
 
 // RefreshToken generates new access token using refresh token
-func RefreshToken(client *mongo.Client) gin.HandlerFunc {
+func (h *Handler) RefreshToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		defer cancel()
+		ctx := c.Request.Context()
 
 		// Get refresh token from cookie
 		refreshToken, err := c.Cookie("refresh_token")
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "No refresh token provided"})
+			metrics.RecordRefresh(false)
+			c.Error(apierror.Unauthorized(apierror.CodeTokenMissing, "No refresh token provided"))
 			return
 		}
 
 		// Validate refresh token signature
 		claims, err := utils.ValidateRefreshToken(refreshToken)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+			metrics.RecordRefresh(false)
+			c.Error(tokenError(err, "Refresh token"))
 			return
 		}
 
 		// Verify refresh token exists in database (not revoked)
-		if err := utils.ValidateRefreshTokenFromDB(claims.UserId, refreshToken, client); err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token has been revoked"})
+		if err := utils.ValidateRefreshTokenFromDB(ctx, claims.UserId, refreshToken, h.Users); err != nil {
+			metrics.RecordRefresh(false)
+			c.Error(apierror.Unauthorized(apierror.CodeTokenRevoked, "Refresh token has been revoked").Wrap(err))
 			return
 		}
 
 		// Get user from database
-		userCollection := database.OpenCollection("users", client)
-		var user models.User
-		err = userCollection.FindOne(ctx, bson.M{"user_id": claims.UserId}).Decode(&user)
+		user, err := h.Users.FindByID(ctx, claims.UserId)
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			c.Error(userLookupError(err))
 			return
 		}
 
 		// Generate new tokens
 		newAccessToken, newRefreshToken, err := utils.GenerateAllTokens(user)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate tokens"})
+			c.Error(apierror.Internal("Failed to generate tokens", err))
 			return
 		}
 
 		// Update tokens in database (invalidates old refresh token)
-		if err := utils.UpdateAllTokens(user.UserID, newAccessToken, newRefreshToken, client); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tokens"})
+		if err := utils.UpdateAllTokens(ctx, user.UserID, newAccessToken, newRefreshToken, h.Users); err != nil {
+			c.Error(apierror.Internal("Failed to update tokens", err))
 			return
 		}
 
@@ -334,36 +341,34 @@ func RefreshToken(client *mongo.Client) gin.HandlerFunc {
 			true,
 		)
 
-		c.JSON(http.StatusOK, gin.H{"message": "Token refreshed successfully"})
+		metrics.RecordRefresh(true)
+		c.JSON(http.StatusOK, MessageResponse{Message: "Token refreshed successfully"})
 	}
 }
 
 // GetProfile returns the current user's profile (protected route example)
-func GetProfile(client *mongo.Client) gin.HandlerFunc {
+func (h *Handler) GetProfile() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		defer cancel()
+		ctx := c.Request.Context()
 
 		// Get userId from context (set by AuthMiddleware)
 		userId, err := utils.GetUserIdFromContext(c)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Error(apierror.Unauthorized(apierror.CodeUnauthenticated, "User not authenticated"))
 			return
 		}
 
 		// Get role from context
 		role, err := utils.GetRoleFromContext(c)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Role not found"})
+			c.Error(apierror.Unauthorized(apierror.CodeUnauthenticated, "Role not found"))
 			return
 		}
 
 		// Fetch user from database
-		userCollection := database.OpenCollection("users", client)
-		var user models.User
-		err = userCollection.FindOne(ctx, bson.M{"user_id": userId}).Decode(&user)
+		user, err := h.Users.FindByID(ctx, userId)
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			c.Error(userLookupError(err))
 			return
 		}
 
@@ -377,9 +382,26 @@ func GetProfile(client *mongo.Client) gin.HandlerFunc {
 			FavouriteGenres: user.FavouriteGenres,
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Profile retrieved successfully",
-			"user":    userResponse,
+		c.JSON(http.StatusOK, UserEnvelope{
+			Message: "Profile retrieved successfully",
+			User:    userResponse,
 		})
 	}
 }
+
+// userLookupError maps a failed FindByID to user_not_found or a 500.
+func userLookupError(err error) *apierror.Error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierror.NotFound(apierror.CodeUserNotFound, "User not found")
+	}
+	return apierror.Internal("Failed to fetch user", err)
+}
+
+// tokenError distinguishes expired tokens (the client should re-authenticate)
+// from malformed or tampered ones.
+func tokenError(err error, kind string) *apierror.Error {
+	if errors.Is(err, utils.ErrTokenExpired) {
+		return apierror.Unauthorized(apierror.CodeTokenExpired, kind+" has expired").Wrap(err)
+	}
+	return apierror.Unauthorized(apierror.CodeTokenInvalid, kind+" is invalid").Wrap(err)
+}
@@ -0,0 +1,77 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+)
+
+func TestRegisterLoginRefresh(t *testing.T) {
+	api := newTestAPI(t)
+	s, env := api.register("reader@example.com")
+	if env.User.Role != models.RoleUser || env.User.Email != "reader@example.com" {
+		t.Errorf("registered user = %+v", env.User)
+	}
+	if s.cookies["access_token"] == nil || s.cookies["refresh_token"] == nil {
+		t.Fatalf("register set cookies %v, want access_token and refresh_token", s.cookies)
+	}
+
+	if rec := s.do(http.MethodGet, "/api/v1/me", nil); rec.Code != http.StatusOK {
+		t.Fatalf("GET /me after register: %d %s", rec.Code, rec.Body)
+	}
+
+	anon := api.session()
+	expectProblem(t, anon.do(http.MethodPost, "/api/v1/auth/login",
+		models.UserLogin{Email: "reader@example.com", Password: "wrong-password"}),
+		http.StatusUnauthorized, apierror.CodeInvalidCredentials)
+	expectProblem(t, anon.do(http.MethodPost, "/api/v1/auth/login",
+		models.UserLogin{Email: "nobody@example.com", Password: "secret123"}),
+		http.StatusUnauthorized, apierror.CodeInvalidCredentials)
+
+	rec := anon.do(http.MethodPost, "/api/v1/auth/login", models.UserLogin{Email: "reader@example.com", Password: "secret123"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login: %d %s", rec.Code, rec.Body)
+	}
+	oldRefresh := anon.cookies["refresh_token"].Value
+
+	if rec := anon.do(http.MethodPost, "/api/v1/auth/refresh", nil); rec.Code != http.StatusOK {
+		t.Fatalf("refresh: %d %s", rec.Code, rec.Body)
+	}
+	if rec := anon.do(http.MethodGet, "/api/v1/me", nil); rec.Code != http.StatusOK {
+		t.Fatalf("GET /me after refresh: %d %s", rec.Code, rec.Body)
+	}
+
+	// Logging out revokes the refresh token, even if a client kept a copy.
+	if rec := anon.do(http.MethodPost, "/api/v1/auth/logout", nil); rec.Code != http.StatusOK {
+		t.Fatalf("logout: %d %s", rec.Code, rec.Body)
+	}
+	replay := api.session()
+	replay.cookies["refresh_token"] = &http.Cookie{Name: "refresh_token", Value: oldRefresh}
+	expectProblem(t, replay.do(http.MethodPost, "/api/v1/auth/refresh", nil), http.StatusUnauthorized, apierror.CodeTokenRevoked)
+	expectProblem(t, api.session().do(http.MethodPost, "/api/v1/auth/refresh", nil),
+		http.StatusUnauthorized, apierror.CodeTokenMissing)
+}
+
+func TestRegisterConflictAndValidation(t *testing.T) {
+	api := newTestAPI(t)
+	api.register("taken@example.com")
+
+	expectProblem(t, api.session().do(http.MethodPost, "/api/v1/auth/register", map[string]any{
+		"first_name": "Other", "last_name": "User", "email": "taken@example.com", "password": "secret123",
+		"favourite_genres": []models.Genre{action},
+	}), http.StatusConflict, apierror.CodeEmailTaken)
+
+	p := expectProblem(t, api.session().do(http.MethodPost, "/api/v1/auth/register", map[string]any{
+		"first_name": "X", "last_name": "User", "email": "not-an-email", "password": "secret123",
+		"favourite_genres": []models.Genre{action},
+	}), http.StatusBadRequest, apierror.CodeValidationFailed)
+	fields := map[string]bool{}
+	for _, f := range p.Errors {
+		fields[f.Field] = true
+	}
+	if !fields["first_name"] || !fields["email"] {
+		t.Errorf("field errors = %+v, want first_name and email", p.Errors)
+	}
+}
@@ -1,28 +1,26 @@
 package database
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"os"
 
-	"github.com/joho/godotenv"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/metrics"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/tracing"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 func DBInstance() *mongo.Client {
-	err := godotenv.Load(".env")
-	if err != nil {
-		log.Println("Warn: unable to find .env")
-	}
 	MongoDb := os.Getenv("MONGODB_URI")
 
 	if MongoDb == "" {
 		log.Fatal("MONGODB_URI not set !")
 	}
-	fmt.Println("MongoDB URI", MongoDb)
 
-	clientOptions := options.Client().ApplyURI(MongoDb)
+	monitor := chainMonitors(metrics.MongoCommandMonitor(), tracing.MongoCommandMonitor())
+	clientOptions := options.Client().ApplyURI(MongoDb).SetMonitor(monitor)
 
 	client, err := mongo.Connect(clientOptions)
 
@@ -34,14 +32,7 @@ func DBInstance() *mongo.Client {
 }
 
 func OpenCollection(collectionName string, client *mongo.Client) *mongo.Collection {
-
-	err := godotenv.Load(".env")
-	if err != nil {
-		log.Println("Warning: unable to find .env file")
-	}
-
 	databaseName := os.Getenv("DATABASE_NAME")
-	fmt.Println("DATABASE_NAME: ", databaseName)
 
 	collection := client.Database(databaseName).Collection(collectionName)
 
@@ -50,3 +41,30 @@ func OpenCollection(collectionName string, client *mongo.Client) *mongo.Collecti
 	}
 	return collection
 }
+
+// OpenDatabase returns the application database named by DATABASE_NAME.
+func OpenDatabase(client *mongo.Client) *mongo.Database {
+	return client.Database(os.Getenv("DATABASE_NAME"))
+}
+
+// chainMonitors fans driver command events out to several monitors,
+// since the client accepts only one.
+func chainMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			for _, m := range monitors {
+				m.Started(ctx, e)
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			for _, m := range monitors {
+				m.Succeeded(ctx, e)
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			for _, m := range monitors {
+				m.Failed(ctx, e)
+			}
+		},
+	}
+}
@@ -0,0 +1,256 @@
+package enrichment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+)
+
+// ErrNoMetadata is returned when the movie database does not know the imdb_id.
+var ErrNoMetadata = errors.New("movie database has no such movie")
+
+// ErrUnavailable wraps every other Lookup failure: the movie database could not be
+// reached, refused the request or sent something unreadable.
+var ErrUnavailable = errors.New("movie database unavailable")
+
+// Metadata is what the movie database reports about one movie.
+type Metadata struct {
+	Title            string
+	PosterURL        string
+	YouTubeID        string // the official YouTube trailer, if any
+	ReleaseDate      string
+	RuntimeMinutes   int
+	Synopsis         string
+	OriginalLanguage string
+	Certification    string
+	Genres           []string // genre names, matched to catalogue genres by the Service
+	Directors        []string
+	Cast             []models.CastMember // billing order
+}
+
+// Client looks movies up in a TMDB-compatible API.
+type Client struct {
+	cfg     Config
+	http    *http.Client
+	limiter *limiter
+}
+
+// NewClient returns a Client for cfg.
+func NewClient(cfg Config) *Client {
+	return &Client{
+		cfg:     cfg,
+		http:    &http.Client{Timeout: cfg.Timeout},
+		limiter: &limiter{interval: time.Duration(float64(time.Second) / cfg.RatePerSecond)},
+	}
+}
+
+// tmdbMovie is the subset of /movie/{id}?append_to_response=credits,videos,release_dates that is mapped.
+type tmdbMovie struct {
+	Title            string `json:"title"`
+	Overview         string `json:"overview"`
+	ReleaseDate      string `json:"release_date"`
+	Runtime          int    `json:"runtime"`
+	OriginalLanguage string `json:"original_language"`
+	PosterPath       string `json:"poster_path"`
+	Genres           []struct {
+		Name string `json:"name"`
+	} `json:"genres"`
+	Credits struct {
+		Cast []struct {
+			Name      string `json:"name"`
+			Character string `json:"character"`
+		} `json:"cast"`
+		Crew []struct {
+			Name string `json:"name"`
+			Job  string `json:"job"`
+		} `json:"crew"`
+	} `json:"credits"`
+	Videos struct {
+		Results []struct {
+			Key      string `json:"key"`
+			Site     string `json:"site"`
+			Type     string `json:"type"`
+			Official bool   `json:"official"`
+		} `json:"results"`
+	} `json:"videos"`
+	ReleaseDates struct {
+		Results []struct {
+			Country      string `json:"iso_3166_1"`
+			ReleaseDates []struct {
+				Certification string `json:"certification"`
+			} `json:"release_dates"`
+		} `json:"results"`
+	} `json:"release_dates"`
+}
+
+// Lookup finds the movie by imdb_id and fetches its details, credits, videos and
+// release dates: two requests, each waiting its turn under the rate limit. Any
+// error other than ErrNoMetadata wraps ErrUnavailable.
+func (c *Client) Lookup(ctx context.Context, imdbID string) (Metadata, error) {
+	md, err := c.lookup(ctx, imdbID)
+	if err != nil && !errors.Is(err, ErrNoMetadata) {
+		err = fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	return md, err
+}
+
+func (c *Client) lookup(ctx context.Context, imdbID string) (Metadata, error) {
+	var found struct {
+		MovieResults []struct {
+			ID int `json:"id"`
+		} `json:"movie_results"`
+	}
+	err := c.get(ctx, "/find/"+url.PathEscape(imdbID), url.Values{"external_source": {"imdb_id"}}, &found)
+	if err != nil {
+		return Metadata{}, err
+	}
+	if len(found.MovieResults) == 0 {
+		return Metadata{}, ErrNoMetadata
+	}
+
+	// Only a 404 here means the movie is unknown; on /find it means a wrong base URL.
+	var movie tmdbMovie
+	err = c.get(ctx, "/movie/"+strconv.Itoa(found.MovieResults[0].ID),
+		url.Values{"append_to_response": {"credits,videos,release_dates"}}, &movie)
+	var status *statusError
+	if errors.As(err, &status) && status.code == http.StatusNotFound {
+		return Metadata{}, ErrNoMetadata
+	}
+	if err != nil {
+		return Metadata{}, err
+	}
+	return c.metadata(movie), nil
+}
+
+func (c *Client) metadata(movie tmdbMovie) Metadata {
+	md := Metadata{
+		Title:            movie.Title,
+		ReleaseDate:      movie.ReleaseDate,
+		RuntimeMinutes:   movie.Runtime,
+		Synopsis:         movie.Overview,
+		OriginalLanguage: movie.OriginalLanguage,
+	}
+	if movie.PosterPath != "" {
+		md.PosterURL = c.cfg.ImageBaseURL + movie.PosterPath
+	}
+	for _, g := range movie.Genres {
+		md.Genres = append(md.Genres, g.Name)
+	}
+	for _, crew := range movie.Credits.Crew {
+		if crew.Job == "Director" {
+			md.Directors = append(md.Directors, crew.Name)
+		}
+	}
+	for _, cast := range movie.Credits.Cast {
+		md.Cast = append(md.Cast, models.CastMember{Name: cast.Name, Character: cast.Character})
+	}
+
+	// Prefer an official trailer, then any trailer.
+	for _, official := range []bool{true, false} {
+		for _, v := range movie.Videos.Results {
+			if md.YouTubeID == "" && v.Site == "YouTube" && v.Type == "Trailer" && (v.Official || !official) {
+				md.YouTubeID = v.Key
+			}
+		}
+	}
+
+	for _, r := range movie.ReleaseDates.Results {
+		if r.Country != c.cfg.Region {
+			continue
+		}
+		for _, d := range r.ReleaseDates {
+			if d.Certification != "" {
+				md.Certification = d.Certification
+				break
+			}
+		}
+	}
+	return md
+}
+
+// get fetches path under the base URL and decodes the JSON response into out.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	if err := c.limiter.wait(ctx); err != nil {
+		return err
+	}
+	if c.cfg.APIKey != "" {
+		query.Set("api_key", c.cfg.APIKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.BaseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.cfg.APIToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return &statusError{code: resp.StatusCode, status: resp.Status, path: path}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding movie database response for %s: %w", path, err)
+	}
+	return nil
+}
+
+// statusError is a non-200 response from the movie database.
+type statusError struct {
+	code   int
+	status string
+	path   string
+}
+
+func (e *statusError) Error() string {
+	return "movie database returned " + e.status + " for " + e.path
+}
+
+// limiter spaces requests at least interval apart across all callers.
+type limiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the caller's slot comes up or ctx is done. A cancelled caller
+// still uses up its slot.
+func (l *limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
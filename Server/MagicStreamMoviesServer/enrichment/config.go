@@ -0,0 +1,81 @@
+package enrichment
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config points the client at the movie database and tunes the refresher.
+type Config struct {
+	BaseURL      string
+	ImageBaseURL string
+	APIKey       string
+	APIToken     string
+	Region       string
+	Timeout      time.Duration
+	// RatePerSecond caps requests to the movie database, shared by previews and refreshes.
+	RatePerSecond float64
+
+	// RefreshInterval is how often stale movies are refreshed; 0 disables the refresher.
+	RefreshInterval time.Duration
+	StaleAfter      time.Duration
+	RefreshBatch    int
+}
+
+// LoadConfig reads the enrichment configuration from the environment:
+//
+//	ENRICH_API_KEY           TMDB v3 API key, sent as the api_key parameter
+//	ENRICH_API_TOKEN         TMDB v4 read access token, sent as a bearer token;
+//	                         enrichment is off unless one of the two is set
+//	ENRICH_API_URL           API base URL (default https://api.themoviedb.org/3)
+//	ENRICH_IMAGE_URL         prefix for poster paths (default https://image.tmdb.org/t/p/w500)
+//	ENRICH_REGION            country whose certification is used (default US)
+//	ENRICH_TIMEOUT           per-request timeout (default 10s)
+//	ENRICH_RATE              requests per second (default 4)
+//	ENRICH_REFRESH_INTERVAL  time between refresh passes, 0 to disable (default 1h)
+//	ENRICH_STALE_AFTER       age at which metadata is refreshed (default 720h)
+//	ENRICH_REFRESH_BATCH     movies refreshed per pass (default 100)
+func LoadConfig() Config {
+	cfg := Config{
+		BaseURL:         strings.TrimRight(envOr("ENRICH_API_URL", "https://api.themoviedb.org/3"), "/"),
+		ImageBaseURL:    strings.TrimRight(envOr("ENRICH_IMAGE_URL", "https://image.tmdb.org/t/p/w500"), "/"),
+		APIKey:          os.Getenv("ENRICH_API_KEY"),
+		APIToken:        os.Getenv("ENRICH_API_TOKEN"),
+		Region:          strings.ToUpper(envOr("ENRICH_REGION", "US")),
+		Timeout:         10 * time.Second,
+		RatePerSecond:   4,
+		RefreshInterval: time.Hour,
+		StaleAfter:      30 * 24 * time.Hour,
+		RefreshBatch:    100,
+	}
+	if d, err := time.ParseDuration(os.Getenv("ENRICH_TIMEOUT")); err == nil && d > 0 {
+		cfg.Timeout = d
+	}
+	if r, err := strconv.ParseFloat(os.Getenv("ENRICH_RATE"), 64); err == nil && r > 0 {
+		cfg.RatePerSecond = r
+	}
+	if d, err := time.ParseDuration(os.Getenv("ENRICH_REFRESH_INTERVAL")); err == nil && d >= 0 {
+		cfg.RefreshInterval = d
+	}
+	if d, err := time.ParseDuration(os.Getenv("ENRICH_STALE_AFTER")); err == nil && d > 0 {
+		cfg.StaleAfter = d
+	}
+	if n, err := strconv.Atoi(os.Getenv("ENRICH_REFRESH_BATCH")); err == nil && n > 0 {
+		cfg.RefreshBatch = n
+	}
+	return cfg
+}
+
+// Enabled reports whether credentials for the movie database are configured.
+func (c Config) Enabled() bool {
+	return c.APIKey != "" || c.APIToken != ""
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,207 @@
+// Package enrichment fills in movie metadata from an external movie database
+// (TMDB or an API shaped like it), given only an imdb_id. Admins preview the result
+// before saving it, and a background Refresher keeps enriched movies up to date.
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+)
+
+// Limits mirrored from the models.Movie validation tags, so enriched movies stay valid.
+const (
+	maxTitle     = 500
+	maxSynopsis  = 5000
+	maxCertLen   = 16
+	maxRuntime   = 1000
+	maxDirectors = 20
+	// maxCast keeps the top-billed performers only; full casts run to hundreds.
+	maxCast = 20
+)
+
+// Preview is a movie as enrichment would save it.
+type Preview struct {
+	ImdbID string `json:"imdb_id"`
+	// Exists reports whether the movie is in the catalogue. If not, Movie is a draft
+	// for AddMovie that still needs admin_review and ranking.
+	Exists bool         `json:"exists"`
+	Movie  models.Movie `json:"movie"`
+	// Changed lists the fields that differ from the stored movie.
+	Changed []string `json:"changed"`
+	// UnmatchedGenres are the movie database's genres with no catalogue genre of the same name.
+	UnmatchedGenres []string `json:"unmatched_genres"`
+}
+
+// Service merges movie database metadata into catalogue movies.
+type Service struct {
+	client *Client
+	store  repository.Store
+}
+
+// NewService returns a Service looking movies up with client.
+func NewService(client *Client, store repository.Store) *Service {
+	return &Service{client: client, store: store}
+}
+
+// Preview fetches the metadata for imdbID and merges it into the stored movie, or
+// into an empty draft if there is none, without saving anything.
+func (s *Service) Preview(ctx context.Context, imdbID string) (Preview, error) {
+	existing, err := s.store.Movies.FindByImdbID(ctx, imdbID)
+	exists := err == nil
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return Preview{}, err
+	}
+	if !exists {
+		existing = models.Movie{ImdbID: imdbID}
+	}
+	return s.preview(ctx, existing, exists)
+}
+
+// Apply enriches the stored movie with imdbID and saves it. It returns
+// repository.ErrNotFound, before contacting the movie database, when the movie is
+// not in the catalogue.
+func (s *Service) Apply(ctx context.Context, imdbID string) (Preview, error) {
+	existing, err := s.store.Movies.FindByImdbID(ctx, imdbID)
+	if err != nil {
+		return Preview{}, err
+	}
+	p, err := s.preview(ctx, existing, true)
+	if err != nil {
+		return Preview{}, err
+	}
+	if err := s.store.Movies.UpdateMetadata(ctx, imdbID, p.Movie); err != nil {
+		return Preview{}, err
+	}
+	return p, nil
+}
+
+func (s *Service) preview(ctx context.Context, existing models.Movie, exists bool) (Preview, error) {
+	md, err := s.client.Lookup(ctx, existing.ImdbID)
+	if err != nil {
+		return Preview{}, err
+	}
+	genres, err := s.store.Genres.List(ctx)
+	if err != nil {
+		return Preview{}, err
+	}
+
+	movie, unmatched := merge(existing, md, genres, time.Now().UTC().Truncate(time.Second))
+	return Preview{
+		ImdbID:          existing.ImdbID,
+		Exists:          exists,
+		Movie:           movie,
+		Changed:         changedFields(existing, movie),
+		UnmatchedGenres: unmatched,
+	}, nil
+}
+
+// merge overlays md on movie. Values the movie database leaves empty keep the stored
+// ones. Genres are only filled in when the movie has none, as admins curate them,
+// and directors and cast are left alone when credits link them to people.
+func merge(movie models.Movie, md Metadata, catalogue []models.Genre, now time.Time) (models.Movie, []string) {
+	movie.FillDefaults()
+	setText := func(dst *string, value string, limit int) {
+		if value = strings.TrimSpace(value); value != "" {
+			*dst = truncate(value, limit)
+		}
+	}
+	setText(&movie.Title, md.Title, maxTitle)
+	setText(&movie.PosterPath, md.PosterURL, 0)
+	setText(&movie.YouTubeID, md.YouTubeID, 0)
+	setText(&movie.Synopsis, md.Synopsis, maxSynopsis)
+	if _, err := time.Parse(time.DateOnly, md.ReleaseDate); err == nil {
+		movie.ReleaseDate = md.ReleaseDate
+	}
+	if md.RuntimeMinutes > 0 && md.RuntimeMinutes <= maxRuntime {
+		movie.RuntimeMinutes = md.RuntimeMinutes
+	}
+	if isLanguageCode(md.OriginalLanguage) {
+		movie.OriginalLanguage = md.OriginalLanguage
+	}
+	if len(md.Certification) <= maxCertLen {
+		setText(&movie.Certification, md.Certification, 0)
+	}
+
+	var matched []models.Genre
+	unmatched := []string{}
+	for _, name := range md.Genres {
+		i := indexGenre(catalogue, name)
+		if i < 0 {
+			unmatched = append(unmatched, name)
+		} else if indexGenre(matched, name) < 0 {
+			matched = append(matched, catalogue[i])
+		}
+	}
+	if len(movie.Genre) == 0 && len(matched) > 0 {
+		movie.Genre = matched
+	}
+
+	if len(movie.Credits) == 0 {
+		if len(md.Directors) > 0 {
+			movie.Directors = md.Directors[:min(len(md.Directors), maxDirectors)]
+		}
+		if len(md.Cast) > 0 {
+			movie.Cast = md.Cast[:min(len(md.Cast), maxCast)]
+		}
+	}
+
+	movie.EnrichedAt = &now
+	return movie, unmatched
+}
+
+// changedFields names, by their JSON keys, the enriched fields that differ between before and after.
+func changedFields(before, after models.Movie) []string {
+	before.FillDefaults()
+	fields := []struct {
+		name      string
+		old, next any
+	}{
+		{"title", before.Title, after.Title},
+		{"poster_path", before.PosterPath, after.PosterPath},
+		{"youtube_id", before.YouTubeID, after.YouTubeID},
+		{"genre", before.Genre, after.Genre},
+		{"release_date", before.ReleaseDate, after.ReleaseDate},
+		{"runtime_minutes", before.RuntimeMinutes, after.RuntimeMinutes},
+		{"synopsis", before.Synopsis, after.Synopsis},
+		{"original_language", before.OriginalLanguage, after.OriginalLanguage},
+		{"certification", before.Certification, after.Certification},
+		{"directors", before.Directors, after.Directors},
+		{"cast", before.Cast, after.Cast},
+	}
+	changed := []string{}
+	for _, f := range fields {
+		if !reflect.DeepEqual(f.old, f.next) {
+			changed = append(changed, f.name)
+		}
+	}
+	return changed
+}
+
+func indexGenre(genres []models.Genre, name string) int {
+	for i, g := range genres {
+		if strings.EqualFold(g.GenreName, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isLanguageCode reports whether s is a two-letter lowercase ISO 639-1 code.
+func isLanguageCode(s string) bool {
+	return len(s) == 2 && s[0] >= 'a' && s[0] <= 'z' && s[1] >= 'a' && s[1] <= 'z'
+}
+
+// truncate shortens s to at most limit runes; a limit of 0 means no limit.
+func truncate(s string, limit int) string {
+	if limit == 0 || utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
@@ -0,0 +1,120 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/logging"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+)
+
+// Refresher periodically re-enriches movies whose metadata is missing or stale.
+// Every instance running one refreshes independently, so enable it on one only.
+type Refresher struct {
+	service    *Service
+	interval   time.Duration
+	staleAfter time.Duration
+	batch      int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRefresher returns a Refresher using the schedule in cfg.
+func NewRefresher(service *Service, cfg Config) *Refresher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Refresher{
+		service:    service,
+		interval:   cfg.RefreshInterval,
+		staleAfter: cfg.StaleAfter,
+		batch:      cfg.RefreshBatch,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start runs a pass now and then every interval until Close. ctx only supplies the logger.
+func (r *Refresher) Start(ctx context.Context) {
+	ctx = logging.WithLogger(r.ctx, logging.FromContext(ctx).With("component", "enrichment_refresher"))
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			r.pass(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (r *Refresher) pass(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	refreshed, err := r.RefreshStale(ctx)
+	switch {
+	case errors.Is(err, context.Canceled):
+	case err != nil:
+		logger.Error("metadata refresh failed", "error", err, "refreshed", refreshed)
+	case refreshed > 0:
+		logger.Info("metadata refreshed", "movies", refreshed)
+	}
+}
+
+// RefreshStale enriches up to one batch of movies that were never enriched or were
+// enriched longer than StaleAfter ago, and returns how many it saved. Movies the
+// movie database does not know are stamped as enriched unchanged, so they wait a
+// full StaleAfter before being tried again. Any other failure ends the pass, on
+// the assumption that the movie database is unavailable.
+func (r *Refresher) RefreshStale(ctx context.Context) (int, error) {
+	movies, err := r.service.store.Movies.ListStale(ctx, time.Now().Add(-r.staleAfter), r.batch)
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	for _, movie := range movies {
+		p, err := r.service.preview(ctx, movie, true)
+		if errors.Is(err, ErrNoMetadata) {
+			now := time.Now().UTC().Truncate(time.Second)
+			p.Movie = movie
+			p.Movie.EnrichedAt = &now
+			err = nil
+		}
+		if err != nil {
+			return refreshed, err
+		}
+		err = r.service.store.Movies.UpdateMetadata(ctx, movie.ImdbID, p.Movie)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue // deleted since it was listed
+		}
+		if err != nil {
+			return refreshed, err
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+// Close stops the refresher and waits until the current pass ends or ctx is done.
+// Movies saved before the stop are kept.
+func (r *Refresher) Close(ctx context.Context) error {
+	r.cancel()
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,238 @@
+// Package exporter writes movies as flat records, one per movie, with genres,
+// rankings, directors and cast flattened into columns. Its CSV columns are the ones
+// the importer reads, so an export can be edited and imported again.
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+)
+
+// Format is the layout of an export.
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+	// FormatExcel is CSV that spreadsheet applications open cleanly: a UTF-8 byte
+	// order mark, CRLF line endings, and text cells that look like formulas
+	// prefixed with an apostrophe so they are not evaluated.
+	FormatExcel Format = "excel"
+)
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	if f == FormatJSONL {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Extension returns the file name extension for the format, without the dot.
+func (f Format) Extension() string {
+	if f == FormatJSONL {
+		return "jsonl"
+	}
+	return "csv"
+}
+
+// listSeparator joins list values in CSV cells, as the importer expects.
+const listSeparator = "|"
+
+// Fields lists every exportable field in the default column order.
+var Fields = []string{
+	"imdb_id", "title", "poster_path", "youtube_id", "genre", "genre_names",
+	"admin_review", "ranking_value", "ranking_name",
+	"release_date", "runtime_minutes", "synopsis", "original_language", "certification",
+	"directors", "cast",
+}
+
+// fields extracts each field from a movie. Lists are returned as slices and
+// formatted per output format.
+var fields = map[string]func(m models.Movie) any{
+	"imdb_id":     func(m models.Movie) any { return m.ImdbID },
+	"title":       func(m models.Movie) any { return m.Title },
+	"poster_path": func(m models.Movie) any { return m.PosterPath },
+	"youtube_id":  func(m models.Movie) any { return m.YouTubeID },
+	"genre": func(m models.Movie) any {
+		ids := make([]int, len(m.Genre))
+		for i, g := range m.Genre {
+			ids[i] = g.GenreId
+		}
+		return ids
+	},
+	"genre_names": func(m models.Movie) any {
+		names := make([]string, len(m.Genre))
+		for i, g := range m.Genre {
+			names[i] = g.GenreName
+		}
+		return names
+	},
+	"admin_review":      func(m models.Movie) any { return m.AdminReview },
+	"ranking_value":     func(m models.Movie) any { return m.Ranking.RankingValue },
+	"ranking_name":      func(m models.Movie) any { return m.Ranking.RankingName },
+	"release_date":      func(m models.Movie) any { return m.ReleaseDate },
+	"runtime_minutes":   func(m models.Movie) any { return m.RuntimeMinutes },
+	"synopsis":          func(m models.Movie) any { return m.Synopsis },
+	"original_language": func(m models.Movie) any { return m.OriginalLanguage },
+	"certification":     func(m models.Movie) any { return m.Certification },
+	"directors":         func(m models.Movie) any { return nonNil(m.Directors) },
+	"cast":              func(m models.Movie) any { return nonNil(m.Cast) },
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// ParseFields parses a comma-separated field selection, keeping the given order
+// and dropping repeats. An empty selection means every field.
+func ParseFields(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return Fields, nil
+	}
+	var selected []string
+	seen := map[string]bool{}
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := fields[name]; !ok {
+			return nil, fmt.Errorf("unknown field %q; choose from %s", name, strings.Join(Fields, ", "))
+		}
+		if !seen[name] {
+			seen[name] = true
+			selected = append(selected, name)
+		}
+	}
+	return selected, nil
+}
+
+// Writer writes movies in one format. Call Flush after the last movie; for CSV it
+// also writes the header row if no movie was written.
+type Writer struct {
+	format Format
+	fields []string
+
+	csv    *csv.Writer
+	jsonl  *bufio.Writer
+	header bool
+}
+
+// NewWriter returns a Writer of the selected fields, which must come from ParseFields.
+func NewWriter(w io.Writer, format Format, selected []string) *Writer {
+	out := &Writer{format: format, fields: selected}
+	if format == FormatJSONL {
+		out.jsonl = bufio.NewWriter(w)
+		return out
+	}
+	out.csv = csv.NewWriter(w)
+	out.csv.UseCRLF = format == FormatExcel
+	return out
+}
+
+// Write writes one movie.
+func (w *Writer) Write(m models.Movie) error {
+	if w.jsonl != nil {
+		return w.writeJSON(m)
+	}
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(w.fields))
+	for i, name := range w.fields {
+		record[i] = w.cell(fields[name](m))
+	}
+	return w.csv.Write(record)
+}
+
+// writeJSON writes the movie as one object with its fields in selection order.
+func (w *Writer) writeJSON(m models.Movie) error {
+	w.jsonl.WriteByte('{')
+	for i, name := range w.fields {
+		if i > 0 {
+			w.jsonl.WriteByte(',')
+		}
+		value, err := json.Marshal(fields[name](m))
+		if err != nil {
+			return err
+		}
+		w.jsonl.WriteString(strconv.Quote(name))
+		w.jsonl.WriteByte(':')
+		w.jsonl.Write(value)
+	}
+	_, err := w.jsonl.WriteString("}\n") // bufio errors are sticky, so this reports any above
+	return err
+}
+
+func (w *Writer) writeHeader() error {
+	if w.header {
+		return nil
+	}
+	w.header = true
+	header := w.fields
+	if w.format == FormatExcel {
+		header = append([]string{"\ufeff" + header[0]}, header[1:]...) // byte order mark
+	}
+	return w.csv.Write(header)
+}
+
+// cell formats a field value for CSV. Zero numbers are left blank, as the importer
+// reads a blank number as "unknown".
+func (w *Writer) cell(value any) string {
+	switch v := value.(type) {
+	case int:
+		if v == 0 {
+			return ""
+		}
+		return strconv.Itoa(v)
+	case []int:
+		items := make([]string, len(v))
+		for i, n := range v {
+			items[i] = strconv.Itoa(n)
+		}
+		return strings.Join(items, listSeparator)
+	case []string:
+		return w.text(strings.Join(v, listSeparator))
+	case []models.CastMember:
+		items := make([]string, len(v))
+		for i, c := range v {
+			items[i] = c.Name
+			if c.Character != "" {
+				items[i] += ":" + c.Character
+			}
+		}
+		return w.text(strings.Join(items, listSeparator))
+	case string:
+		return w.text(v)
+	}
+	return fmt.Sprint(value)
+}
+
+// text guards against spreadsheet formula injection in the Excel format.
+func (w *Writer) text(s string) string {
+	if w.format == FormatExcel && s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// Flush writes out anything buffered.
+func (w *Writer) Flush() error {
+	if w.jsonl != nil {
+		return w.jsonl.Flush()
+	}
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
@@ -0,0 +1,111 @@
+// Package health serves liveness (/healthz) and readiness (/readyz) probes.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/logging"
+)
+
+// CheckFunc reports an unhealthy dependency by returning an error.
+type CheckFunc func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+// CheckResult is the outcome of one dependency check in the readiness response.
+// /readyz is unauthenticated, so a failure is only reported as "unavailable";
+// the error itself is logged.
+type CheckResult struct {
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Checker runs the registered readiness checks.
+type Checker struct {
+	timeout      time.Duration
+	checks       []namedCheck
+	shuttingDown atomic.Bool
+}
+
+// NewChecker returns a Checker whose checks each get at most timeout to respond.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a dependency check under name. Call before serving traffic.
+func (c *Checker) Add(name string, check CheckFunc) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// SetShuttingDown makes readiness fail so load balancers stop routing new requests
+// while in-flight ones drain.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Liveness reports that the process is up and serving HTTP. It never touches dependencies.
+func (c *Checker) Liveness() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+}
+
+// Readiness runs every check concurrently and returns 503 if any fails or the server is shutting down.
+func (c *Checker) Readiness() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if c.shuttingDown.Load() {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting_down"})
+			return
+		}
+
+		results := c.run(ctx.Request.Context())
+
+		status, code := "ready", http.StatusOK
+		for _, r := range results {
+			if r.Status != "ok" {
+				status, code = "not_ready", http.StatusServiceUnavailable
+			}
+		}
+
+		ctx.JSON(code, gin.H{"status": status, "checks": results})
+	}
+}
+
+func (c *Checker) run(parent context.Context) map[string]CheckResult {
+	results := make(map[string]CheckResult, len(c.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(parent, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := nc.check(ctx)
+			result := CheckResult{Status: "ok", DurationMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status = "unavailable"
+				logging.FromContext(parent).Warn("readiness check failed", "check", nc.name, "error", err)
+			}
+
+			mu.Lock()
+			results[nc.name] = result
+			mu.Unlock()
+		}(nc)
+	}
+
+	wg.Wait()
+	return results
+}
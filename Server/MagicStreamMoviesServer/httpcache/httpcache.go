@@ -0,0 +1,160 @@
+// Package httpcache adds HTTP validators (ETag, Last-Modified), conditional
+// request handling and Cache-Control headers to read-mostly routes.
+package httpcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Versioner reports the version of a set of resources, such as the movie
+// catalogue. The version must be shared by every process that writes them, or
+// a write made elsewhere would leave clients with a stale 304.
+type Versioner interface {
+	// Version returns a tag that changes whenever the resources do and the time
+	// they last changed. ok is false when no version is available right now.
+	Version(ctx context.Context) (tag string, modified time.Time, ok bool)
+}
+
+// Policy describes how a route may be cached.
+type Policy struct {
+	// CacheControl is sent verbatim, e.g. "public, max-age=60".
+	CacheControl string
+	// Validate adds an ETag and answers conditional GETs with 304 Not Modified.
+	Validate bool
+	// Version, when set and available, supplies the ETag and Last-Modified, so a
+	// conditional GET is answered before the handler runs. Otherwise the ETag is
+	// a hash of the response body: the handler still runs, and 304 saves only
+	// the transfer.
+	Version Versioner
+}
+
+// Common policies.
+const (
+	// Public catalogue data: shared caches may serve it briefly, then revalidate.
+	PublicShort = "public, max-age=60, stale-while-revalidate=30"
+	// Public data that changes rarely, such as the genre list.
+	PublicLong = "public, max-age=300, stale-while-revalidate=60"
+	// Per-user or authenticated data: only the browser may store it, and must revalidate.
+	PrivateRevalidate = "private, no-cache"
+	// Responses that must never be stored (auth, tokens).
+	NoStore = "no-store"
+	// Content-addressed files, such as poster images, that never change once stored.
+	PublicImmutable = "public, max-age=31536000, immutable"
+)
+
+// Middleware applies p. Install it after authentication so unauthenticated
+// clients cannot probe versions of protected resources.
+func Middleware(p Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if p.CacheControl != "" {
+			c.Header("Cache-Control", p.CacheControl)
+		}
+		if !p.Validate || (c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead) {
+			c.Next()
+			return
+		}
+
+		if p.Version != nil {
+			if tag, modified, ok := p.Version.Version(c.Request.Context()); ok {
+				etag := `W/"` + tag + `"`
+				modified = modified.Truncate(time.Second)
+				c.Header("ETag", etag)
+				c.Header("Last-Modified", modified.UTC().Format(http.TimeFormat))
+				if notModified(c.Request, etag, modified) {
+					c.AbortWithStatus(http.StatusNotModified)
+					return
+				}
+				c.Next()
+				return
+			}
+		}
+
+		original := c.Writer
+		bw := &bufferWriter{ResponseWriter: original}
+		c.Writer = bw
+		c.Next()
+		c.Writer = original
+
+		// Errors are rendered later by apierror.Middleware and carry no validator.
+		if len(bw.body) == 0 {
+			return
+		}
+		if original.Status() == http.StatusOK {
+			etag := bodyETag(bw.body)
+			c.Header("ETag", etag)
+			if inm := c.GetHeader("If-None-Match"); inm != "" && etagMatches(inm, etag) {
+				original.WriteHeader(http.StatusNotModified)
+				original.WriteHeaderNow()
+				return
+			}
+		}
+		original.Write(bw.body)
+	}
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since only
+// when no entity tags were sent (RFC 9110 section 13.2.2).
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		return err == nil && !modified.After(t)
+	}
+	return false
+}
+
+// bodyETag is the weak entity tag for body. Weak, because the same body is served in
+// identity, gzip and brotli encodings.
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches uses the weak comparison If-None-Match requires.
+func etagMatches(header, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferWriter holds the body back until it has been hashed.
+type bufferWriter struct {
+	gin.ResponseWriter
+	body []byte
+}
+
+func (w *bufferWriter) Write(b []byte) (int, error) {
+	w.body = append(w.body, b...)
+	return len(b), nil
+}
+
+func (w *bufferWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports buffered bytes too, like the compression writer does.
+func (w *bufferWriter) Written() bool {
+	return len(w.body) > 0 || w.ResponseWriter.Written()
+}
+
+// Flush is a no-op: the body cannot be sent before its ETag is known.
+func (w *bufferWriter) Flush() {}
+
+// Unwrap lets http.ResponseController reach the connection.
+func (w *bufferWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,146 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+)
+
+// listSeparator separates the values of the list columns: genre, directors and cast.
+const listSeparator = "|"
+
+// csvColumns maps each accepted CSV header to the Movie field it sets. Columns may
+// appear in any order and may be omitted; imdb_id is mandatory. Lists are written as
+// "1|4" (genre ids), "Jane Doe|John Roe" (directors) and "Name:Character|..." (cast).
+// Credits are not supported in CSV; import them as JSON Lines.
+var csvColumns = map[string]func(m *models.Movie, value string) error{
+	"imdb_id":     func(m *models.Movie, v string) error { m.ImdbID = v; return nil },
+	"title":       func(m *models.Movie, v string) error { m.Title = v; return nil },
+	"poster_path": func(m *models.Movie, v string) error { m.PosterPath = v; return nil },
+	"youtube_id":  func(m *models.Movie, v string) error { m.YouTubeID = v; return nil },
+	"genre": func(m *models.Movie, v string) error {
+		for _, s := range splitList(v) {
+			id, err := strconv.Atoi(s)
+			if err != nil {
+				return errors.New("must be genre ids separated by " + listSeparator)
+			}
+			m.Genre = append(m.Genre, models.Genre{GenreId: id})
+		}
+		return nil
+	},
+	"admin_review": func(m *models.Movie, v string) error { m.AdminReview = v; return nil },
+	"ranking_value": func(m *models.Movie, v string) error {
+		return parseInt(v, &m.Ranking.RankingValue)
+	},
+	"ranking_name": func(m *models.Movie, v string) error { m.Ranking.RankingName = v; return nil },
+	"release_date": func(m *models.Movie, v string) error { m.ReleaseDate = v; return nil },
+	"runtime_minutes": func(m *models.Movie, v string) error {
+		return parseInt(v, &m.RuntimeMinutes)
+	},
+	"synopsis":          func(m *models.Movie, v string) error { m.Synopsis = v; return nil },
+	"original_language": func(m *models.Movie, v string) error { m.OriginalLanguage = v; return nil },
+	"certification":     func(m *models.Movie, v string) error { m.Certification = v; return nil },
+	"genre_names":       func(m *models.Movie, v string) error { return nil }, // exported for reading; genres are matched by id
+	"directors":         func(m *models.Movie, v string) error { m.Directors = splitList(v); return nil },
+	"cast": func(m *models.Movie, v string) error {
+		for _, s := range splitList(v) {
+			name, character, _ := strings.Cut(s, ":")
+			m.Cast = append(m.Cast, models.CastMember{Name: strings.TrimSpace(name), Character: strings.TrimSpace(character)})
+		}
+		return nil
+	},
+}
+
+func parseInt(v string, dst *int) error {
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return errors.New("must be an integer")
+	}
+	*dst = n
+	return nil
+}
+
+// splitList splits a list column, dropping empty items; "" yields nil.
+func splitList(v string) []string {
+	var items []string
+	for _, s := range strings.Split(v, listSeparator) {
+		if s = strings.TrimSpace(s); s != "" {
+			items = append(items, s)
+		}
+	}
+	return items
+}
+
+// fieldPath names a column the way validation errors name the Movie field it sets.
+func fieldPath(column string) string {
+	if strings.HasPrefix(column, "ranking_") {
+		return "ranking." + column
+	}
+	return column
+}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns []string
+}
+
+// newCSVReader reads and checks the header row.
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("CSV file is empty; the first row must name the columns")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := csvColumns[name]; !ok {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("CSV column %q appears twice", name)
+		}
+		seen[name] = true
+		header[i] = name
+	}
+	if !seen["imdb_id"] {
+		return nil, errors.New("CSV header must include imdb_id")
+	}
+	return &csvReader{r: cr, columns: header}, nil
+}
+
+func (c *csvReader) next() (row, error) {
+	record, err := c.r.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) && errors.Is(err, csv.ErrFieldCount) {
+		return row{line: parseErr.StartLine, unreadable: true, errors: []apierror.FieldError{{
+			Rule:    "columns",
+			Message: fmt.Sprintf("has %d columns, want %d", len(record), len(c.columns)),
+		}}}, nil
+	}
+	if err != nil {
+		return row{}, err
+	}
+
+	line, _ := c.r.FieldPos(0)
+	r := row{line: line}
+	for i, value := range record {
+		if err := csvColumns[c.columns[i]](&r.movie, strings.TrimSpace(value)); err != nil {
+			r.errors = append(r.errors, apierror.FieldError{Field: fieldPath(c.columns[i]), Rule: "type", Message: err.Error()})
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,352 @@
+// Package importer bulk-loads movies from CSV or JSON Lines files. Every row is
+// validated like the AddMovie body, and valid rows are upserted by imdb_id in batches.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Format is the layout of an import file.
+type Format string
+
+const (
+	// FormatCSV is a header row followed by one movie per record; see csvColumns.
+	FormatCSV Format = "csv"
+	// FormatJSONL is one movie JSON object per line, shaped like the AddMovie body.
+	FormatJSONL Format = "jsonl"
+)
+
+// ParseFormat accepts a format name ("csv", "jsonl") or a media type such as
+// text/csv or application/x-ndjson.
+func ParseFormat(s string) (Format, bool) {
+	if mediaType, _, err := mime.ParseMediaType(s); err == nil {
+		s = mediaType
+	}
+	switch strings.ToLower(s) {
+	case "csv", "text/csv":
+		return FormatCSV, true
+	case "jsonl", "ndjson", "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+		return FormatJSONL, true
+	}
+	return "", false
+}
+
+// Batch size bounds and the default cap on reported row errors.
+const (
+	DefaultBatchSize = 500
+	MaxBatchSize     = 5000
+	DefaultMaxErrors = 1000
+)
+
+// DefaultMaxUploadBytes is the largest import file the API accepts unless
+// IMPORT_MAX_BYTES says otherwise.
+const DefaultMaxUploadBytes = 64 << 20
+
+// MaxUploadBytes returns IMPORT_MAX_BYTES, or DefaultMaxUploadBytes when it is unset or invalid.
+func MaxUploadBytes() int64 {
+	n, err := strconv.ParseInt(os.Getenv("IMPORT_MAX_BYTES"), 10, 64)
+	if err != nil || n <= 0 {
+		return DefaultMaxUploadBytes
+	}
+	return n
+}
+
+// Options control one import.
+type Options struct {
+	Format Format
+	// DryRun validates every row and reports what would be created or updated
+	// without writing anything.
+	DryRun bool
+	// BatchSize is how many valid rows are written per UpsertMany call
+	// (DefaultBatchSize when zero).
+	BatchSize int
+	// MaxErrors caps Report.Errors (DefaultMaxErrors when zero); further invalid
+	// rows are only counted.
+	MaxErrors int
+}
+
+// Report summarises an import, or the part of it processed so far.
+type Report struct {
+	Rows    int `json:"rows"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+	// Created and Updated count upserted rows; in a dry run, the rows that would be.
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Batches int `json:"batches"`
+
+	Errors          []RowError `json:"errors"`
+	ErrorsTruncated bool       `json:"errors_truncated,omitempty"`
+}
+
+// RowError lists why one row was rejected. Line is the 1-based line of the row in
+// the file, so the header of a CSV file is line 1.
+type RowError struct {
+	Line   int                   `json:"line"`
+	ImdbID string                `json:"imdb_id,omitempty"`
+	Errors []apierror.FieldError `json:"errors"`
+}
+
+// Importer validates and writes import files against a store.
+type Importer struct {
+	store    repository.Store
+	validate *validator.Validate
+}
+
+// New returns an Importer writing to store.
+func New(store repository.Store) *Importer {
+	return &Importer{store: store, validate: apierror.NewValidator()}
+}
+
+// row is one decoded movie and the problems found with it so far. An unreadable
+// row could not be decoded at all, so its movie is not worth validating.
+type row struct {
+	line       int
+	movie      models.Movie
+	errors     []apierror.FieldError
+	unreadable bool
+}
+
+// rowReader yields rows until io.EOF. Other errors mean the file cannot be read further.
+type rowReader interface {
+	next() (row, error)
+}
+
+// Run imports every row of r. progress, if not nil, is called with the report so
+// far after each batch. An error (unreadable file, storage failure, cancelled ctx)
+// stops the import; batches already written stay written, and since rows are
+// upserted the same file can simply be imported again.
+func (im *Importer) Run(ctx context.Context, r io.Reader, opts Options, progress func(Report)) (Report, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.MaxErrors <= 0 {
+		opts.MaxErrors = DefaultMaxErrors
+	}
+	report := Report{Errors: []RowError{}}
+
+	var rows rowReader
+	switch opts.Format {
+	case FormatCSV:
+		csvRows, err := newCSVReader(r)
+		if err != nil {
+			return report, err
+		}
+		rows = csvRows
+	case FormatJSONL:
+		rows = newJSONLReader(r)
+	default:
+		return report, fmt.Errorf("unsupported import format %q", opts.Format)
+	}
+
+	genres, err := im.store.Genres.List(ctx)
+	if err != nil {
+		return report, fmt.Errorf("fetch genres: %w", err)
+	}
+	genreNames := make(map[int]string, len(genres))
+	for _, g := range genres {
+		genreNames[g.GenreId] = g.GenreName
+	}
+
+	reject := func(r row) {
+		report.Invalid++
+		if len(report.Errors) >= opts.MaxErrors {
+			report.ErrorsTruncated = true
+			return
+		}
+		report.Errors = append(report.Errors, RowError{Line: r.line, ImdbID: r.movie.ImdbID, Errors: r.errors})
+	}
+
+	seen := map[string]int{} // imdb_id -> line of the row that claimed it
+	batch := make([]row, 0, opts.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := im.writeBatch(ctx, batch, opts.DryRun, &report, reject); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		// Credit errors are found a batch late; keep the list in file order. Sorting a
+		// copy leaves the slices already handed to progress untouched.
+		report.Errors = slices.Clone(report.Errors)
+		slices.SortStableFunc(report.Errors, func(a, b RowError) int { return a.Line - b.Line })
+		if progress != nil {
+			progress(report)
+		}
+		return nil
+	}
+
+	for {
+		r, err := rows.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+		report.Rows++
+
+		if !r.unreadable {
+			r.errors = mergeFieldErrors(r.errors, im.check(&r.movie, genreNames))
+		}
+		if first, ok := seen[r.movie.ImdbID]; ok && len(r.errors) == 0 {
+			r.errors = append(r.errors, apierror.FieldError{
+				Field:   "imdb_id",
+				Rule:    "unique",
+				Message: fmt.Sprintf("duplicates the movie on line %d", first),
+			})
+		}
+		if len(r.errors) > 0 {
+			reject(r)
+			continue
+		}
+
+		seen[r.movie.ImdbID] = r.line
+		batch = append(batch, r)
+		if len(batch) == opts.BatchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// check validates movie against the models.Movie tags and replaces its genre
+// names with the stored ones, returning every problem found.
+func (im *Importer) check(movie *models.Movie, genreNames map[int]string) []apierror.FieldError {
+	movie.ID = bson.ObjectID{} // stores assign IDs; an existing movie keeps its own
+
+	var fields []apierror.FieldError
+	unknown := map[string]bool{} // "genre[i]." prefixes whose other errors are noise
+	for i, g := range movie.Genre {
+		name, ok := genreNames[g.GenreId]
+		if !ok {
+			fields = append(fields, apierror.FieldError{
+				Field:   fmt.Sprintf("genre[%d].genre_id", i),
+				Rule:    "exists",
+				Message: fmt.Sprintf("genre %d does not exist", g.GenreId),
+			})
+			unknown[fmt.Sprintf("genre[%d].", i)] = true
+			continue
+		}
+		movie.Genre[i].GenreName = name
+	}
+
+	if err := im.validate.Struct(movie); err != nil {
+		for _, fe := range apierror.Validation(err).Fields {
+			if prefix, _, ok := strings.Cut(fe.Field, "]."); ok && unknown[prefix+"]."] {
+				continue
+			}
+			fields = append(fields, fe)
+		}
+	}
+	return fields
+}
+
+// mergeFieldErrors appends the errors in more for fields not already reported in
+// errs, so a value that failed to decode is not also reported as missing.
+func mergeFieldErrors(errs, more []apierror.FieldError) []apierror.FieldError {
+	reported := make(map[string]bool, len(errs))
+	for _, e := range errs {
+		reported[e.Field] = true
+	}
+	for _, e := range more {
+		if !reported[e.Field] {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// writeBatch resolves the credits of the rows in batch, rejects rows naming unknown
+// people, and upserts the rest (or, in a dry run, counts which already exist).
+func (im *Importer) writeBatch(ctx context.Context, batch []row, dryRun bool, report *Report, reject func(row)) error {
+	var personIDs []string
+	for _, r := range batch {
+		for _, credit := range r.movie.Credits {
+			personIDs = append(personIDs, credit.PersonID)
+		}
+	}
+	names := map[string]string{}
+	if len(personIDs) > 0 {
+		people, err := im.store.People.FindByIDs(ctx, personIDs)
+		if err != nil {
+			return fmt.Errorf("fetch people: %w", err)
+		}
+		for _, p := range people {
+			names[p.PersonID] = p.Name
+		}
+	}
+
+	movies := make([]models.Movie, 0, len(batch))
+	for _, r := range batch {
+		for i, credit := range r.movie.Credits {
+			name, ok := names[credit.PersonID]
+			if !ok {
+				r.errors = append(r.errors, apierror.FieldError{
+					Field:   fmt.Sprintf("credits[%d].person_id", i),
+					Rule:    "exists",
+					Message: fmt.Sprintf("person %q does not exist", credit.PersonID),
+				})
+				continue
+			}
+			r.movie.Credits[i].Name = name
+		}
+		if len(r.errors) > 0 {
+			reject(r)
+			continue
+		}
+
+		if len(r.movie.Credits) > 0 {
+			models.SortCredits(r.movie.Credits)
+			r.movie.Directors, r.movie.Cast = models.DirectorsAndCast(r.movie.Credits)
+		}
+		r.movie.FillDefaults()
+		movies = append(movies, r.movie)
+	}
+	report.Batches++
+	report.Valid += len(movies)
+	if len(movies) == 0 {
+		return nil
+	}
+
+	var created int
+	if dryRun {
+		imdbIDs := make([]string, len(movies))
+		for i, m := range movies {
+			imdbIDs[i] = m.ImdbID
+		}
+		existing, err := im.store.Movies.ListByImdbIDs(ctx, imdbIDs)
+		if err != nil {
+			return fmt.Errorf("fetch existing movies: %w", err)
+		}
+		created = len(movies) - len(existing)
+	} else {
+		var err error
+		if created, err = im.store.Movies.UpsertMany(ctx, movies); err != nil {
+			return fmt.Errorf("write batch %d: %w", report.Batches, err)
+		}
+	}
+	report.Created += created
+	report.Updated += len(movies) - created
+	return nil
+}
@@ -0,0 +1,160 @@
+package importer
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/logging"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Status is the lifecycle state of a Job.
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Job is a background import as reported by the status endpoint.
+type Job struct {
+	JobID      string     `json:"job_id"`
+	Status     Status     `json:"status"`
+	Format     Format     `json:"format"`
+	DryRun     bool       `json:"dry_run"`
+	BatchSize  int        `json:"batch_size"`
+	Report     Report     `json:"report"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// keepJobs is how many jobs Jobs remembers; the oldest are forgotten first.
+const keepJobs = 100
+
+// Jobs runs imports in the background and keeps the most recent ones for status
+// queries. Jobs live in memory: a restart forgets them, and each server instance
+// only knows the imports it ran.
+type Jobs struct {
+	importer *Importer
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu    sync.Mutex
+	jobs  map[string]*Job
+	order []string // job IDs, oldest first
+}
+
+// NewJobs returns a Jobs running imports with importer.
+func NewJobs(importer *Importer) *Jobs {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Jobs{importer: importer, ctx: ctx, cancel: cancel, jobs: map[string]*Job{}}
+}
+
+// Start imports file in the background and returns the queued job. file is closed
+// when the import ends. ctx only supplies the logger; the job outlives the request.
+func (j *Jobs) Start(ctx context.Context, file io.ReadCloser, opts Options) Job {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	job := &Job{
+		JobID:     bson.NewObjectID().Hex(),
+		Status:    StatusQueued,
+		Format:    opts.Format,
+		DryRun:    opts.DryRun,
+		BatchSize: opts.BatchSize,
+		Report:    Report{Errors: []RowError{}},
+		CreatedAt: time.Now(),
+	}
+	logger := logging.FromContext(ctx).With("job_id", job.JobID)
+
+	j.mu.Lock()
+	j.jobs[job.JobID] = job
+	j.order = append(j.order, job.JobID)
+	if len(j.order) > keepJobs {
+		delete(j.jobs, j.order[0])
+		j.order = j.order[1:]
+	}
+	snapshot := *job
+	j.mu.Unlock()
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		defer file.Close()
+		j.run(logging.WithLogger(j.ctx, logger), job, file, opts)
+	}()
+	return snapshot
+}
+
+func (j *Jobs) run(ctx context.Context, job *Job, file io.Reader, opts Options) {
+	j.update(job, func() {
+		now := time.Now()
+		job.Status = StatusRunning
+		job.StartedAt = &now
+	})
+
+	report, err := j.importer.Run(ctx, file, opts, func(report Report) {
+		j.update(job, func() { job.Report = report })
+	})
+
+	j.update(job, func() {
+		now := time.Now()
+		job.Report = report
+		job.FinishedAt = &now
+		job.Status = StatusSucceeded
+		if err != nil {
+			job.Status = StatusFailed
+			job.Error = err.Error()
+		}
+	})
+
+	logger := logging.FromContext(ctx)
+	if err != nil {
+		logger.Error("movie import failed", "error", err, "rows", report.Rows, "created", report.Created, "updated", report.Updated)
+		return
+	}
+	logger.Info("movie import finished", "dry_run", opts.DryRun, "rows", report.Rows,
+		"invalid", report.Invalid, "created", report.Created, "updated", report.Updated)
+}
+
+func (j *Jobs) update(job *Job, fn func()) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	fn()
+}
+
+// Get returns a copy of the job with jobID.
+func (j *Jobs) Get(jobID string) (Job, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[jobID]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// Close cancels running imports and waits until they stop or ctx is done. Batches
+// written before the cancellation are kept.
+func (j *Jobs) Close(ctx context.Context) error {
+	j.cancel()
+	done := make(chan struct{})
+	go func() {
+		j.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
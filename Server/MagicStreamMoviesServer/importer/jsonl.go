@@ -0,0 +1,58 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+)
+
+// maxLineBytes bounds one JSON Lines record; a longer line aborts the import.
+const maxLineBytes = 1 << 20
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newJSONLReader(r io.Reader) *jsonlReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	return &jsonlReader{scanner: scanner}
+}
+
+// next skips blank lines and decodes the next movie.
+func (j *jsonlReader) next() (row, error) {
+	for j.scanner.Scan() {
+		j.line++
+		data := bytes.TrimSpace(j.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		r := row{line: j.line}
+		if err := json.Unmarshal(data, &r.movie); err != nil {
+			// A type mismatch names its field and leaves the rest decoded; a syntax error
+			// leaves nothing worth validating.
+			if e := apierror.InvalidBody(err); len(e.Fields) > 0 {
+				r.errors = e.Fields
+			} else {
+				r.errors = []apierror.FieldError{{Rule: "json", Message: "is not a valid JSON object"}}
+				r.unreadable = true
+			}
+		}
+		return r, nil
+	}
+
+	if err := j.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return row{}, fmt.Errorf("line %d is longer than %d bytes", j.line+1, maxLineBytes)
+		}
+		return row{}, err
+	}
+	return row{}, io.EOF
+}
@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader is read from incoming requests and echoed on every response.
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID accepts caller-supplied ids that are safe to log and echo back.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// RequestID honours a well-formed X-Request-ID header or generates one, echoes it in
+// the response, and stores a logger carrying request_id, method and route in the
+// request context. Handlers retrieve it with FromContext(c.Request.Context()).
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+		c.Set("requestId", id)
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		logger := slog.Default().With(
+			slog.String("request_id", id),
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+		)
+		c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), logger))
+
+		c.Next()
+	}
+}
+
+// AddAttrs enriches the request logger, e.g. with user_id once AuthMiddleware has run.
+func AddAttrs(c *gin.Context, args ...any) {
+	logger := FromContext(c.Request.Context()).With(args...)
+	c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), logger))
+}
+
+// AccessLog writes one structured line per request, replacing gin's text logger.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+
+		FromContext(c.Request.Context()).LogAttrs(c.Request.Context(), level, "request completed", attrs...)
+	}
+}
+
+// Recovery logs panics through the request logger instead of gin's plain-text dump,
+// which would include cookies. The panic is attached with c.Error so the error
+// middleware registered before Recovery renders the 500 response.
+// http.ErrAbortHandler is re-raised: handlers use it to make net/http cut off a
+// response that failed after it started streaming.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		if recovered == http.ErrAbortHandler {
+			panic(recovered)
+		}
+		FromContext(c.Request.Context()).Error("panic recovered", "panic", fmt.Sprint(recovered))
+		c.Error(fmt.Errorf("panic: %v", recovered))
+		c.Status(http.StatusInternalServerError) // not written yet, so the problem body can follow
+		c.Abort()
+	})
+}
@@ -2,82 +2,217 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/blobstore"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/cache"
 	controller "github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/controllers"
-	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/database"
-	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/middleware"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/enrichment"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/health"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/logging"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/openapi"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/server"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/storage"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/tracing"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/utils"
 )
 
 func main() {
+	migrateCommand := flag.String("migrate", "", "run database migrations and exit: up, down or status (exits 1 if any are pending)")
+	checkOpenAPI := flag.Bool("check-openapi", false, "exit non-zero if the OpenAPI document and registered routes differ")
+	flag.Parse()
 
-	var client = database.DBInstance()
+	// Failures below set exitCode and return, so the deferred cleanups still run
+	// before the process exits non-zero. Registered first, this defer runs last.
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
 
-	// Verify database connection
-	if err := client.Ping(context.Background(), nil); err != nil {
-		fmt.Println("Failed to reach MongoDB server:", err)
+	// .env is loaded before the logger so APP_ENV / LOG_LEVEL / LOG_FORMAT from it apply
+	envFound := storage.LoadEnv()
+	logging.Setup()
+	if !envFound {
+		slog.Debug("no .env file found, using process environment")
+	}
+	if logging.IsProduction() {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	if *checkOpenAPI {
+		os.Exit(runOpenAPICheck())
+	}
+
+	// Password hashing settings are read once; a bad value stops startup instead of
+	// silently hashing with other parameters
+	passwordConfig, err := utils.LoadPasswordHashConfig()
+	if err != nil {
+		slog.Error("invalid password hashing configuration", "error", err)
+		exitCode = 1
 		return
 	}
-	fmt.Println("Successfully connected to MongoDB!")
+	utils.SetPasswordHashConfig(passwordConfig)
 
+	// Tracing is configured by OTEL_TRACES_EXPORTER; buffered spans are flushed on exit
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		slog.Error("failed to initialise tracing", "error", err)
+		exitCode = 1
+		return
+	}
 	defer func() {
-		if err := client.Disconnect(context.Background()); err != nil {
-			fmt.Println("Error disconnecting from MongoDB:", err)
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Error("failed to flush traces", "error", err)
 		}
 	}()
 
-	router := gin.Default()
-	config := cors.Config{
-		AllowOrigins:     []string{"http://localhost:5173"},
-		AllowMethods:     []string{"GET", "POST", "PATCH", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
+	// Open the configured storage backend (MongoDB unless STORAGE_BACKEND says otherwise)
+	backend, err := storage.Open(context.Background())
+	if err != nil {
+		slog.Error("failed to open storage backend", "error", err)
+		exitCode = 1
+		return
+	}
+	slog.Info("connected to storage backend", "backend", backend.Name)
+
+	defer func() {
+		if err := backend.Close(context.Background()); err != nil {
+			slog.Error("failed to close storage backend", "error", err)
+		}
+	}()
+
+	if *migrateCommand != "" {
+		if err := runMigrateCommand(context.Background(), backend, *migrateCommand); err != nil {
+			slog.Error("migration failed", "error", err)
+			exitCode = 1
+		}
+		return
+	}
+
+	if err := runStartupMigrations(context.Background(), backend); err != nil {
+		slog.Error("startup migrations failed", "error", err)
+		exitCode = 1
+		return
+	}
+
+	// Catalogue reads go through the cache (CACHE_BACKEND); catalogue writes invalidate it
+	cacheConfig := cache.LoadConfig()
+	catalogCache, err := cache.Open(context.Background(), cacheConfig)
+	if err != nil {
+		slog.Error("failed to open cache", "backend", cacheConfig.Backend, "error", err)
+		exitCode = 1
+		return
+	}
+	if catalogCache != nil {
+		slog.Info("catalogue cache enabled", "backend", cacheConfig.Backend, "ttl", cacheConfig.TTL)
+		defer func() {
+			if err := catalogCache.Close(); err != nil {
+				slog.Error("failed to close cache", "error", err)
+			}
+		}()
+	}
+
+	// Handlers pass their request context; each repository call adds its own deadline
+	// (DB_READ_TIMEOUT / DB_WRITE_TIMEOUT) on top of it. Cache hits skip the database entirely.
+	store := cache.Wrap(repository.WithTimeouts(backend.Store, repository.LoadTimeouts()), catalogCache, cacheConfig.TTL)
+	h := controller.NewHandler(store)
+	if cacheConfig.Backend == cache.BackendRedis {
+		// Redis is shared with other instances and the admin CLI, so its generation can
+		// validate catalogue reads before they run
+		h.CatalogVersion = cache.NewVersion(catalogCache, cacheConfig.TTL)
 	}
-	router.Use(cors.New(config))
 
-	router.GET("/hello", func(ctx *gin.Context) {
-		ctx.String(200, "Welcome to MagicStream !")
+	// Uploaded posters are kept in BLOB_BACKEND: a local directory or an S3-compatible bucket
+	blobConfig := blobstore.LoadConfig()
+	h.Blobs, err = blobstore.Open(context.Background(), blobConfig)
+	if err != nil {
+		slog.Error("failed to open blob store", "backend", blobConfig.Backend, "error", err)
+		exitCode = 1
+		return
+	}
+	slog.Info("blob store ready", "backend", blobConfig.Backend)
+
+	// Metadata enrichment is on when ENRICH_API_KEY or ENRICH_API_TOKEN is set
+	enrichConfig := enrichment.LoadConfig()
+	if enrichConfig.Enabled() {
+		h.Enrichment = enrichment.NewService(enrichment.NewClient(enrichConfig), store)
+		slog.Info("metadata enrichment enabled", "api", enrichConfig.BaseURL, "refresh_interval", enrichConfig.RefreshInterval)
+	}
+
+	// Probes: /healthz = process alive, /readyz = dependencies usable
+	checker := health.NewChecker(2 * time.Second)
+	checker.Add("database", backend.Ping)
+	checker.Add("jwt_keys", func(context.Context) error { return utils.JWTKeysLoaded() })
+	if redis, ok := catalogCache.(*cache.Redis); ok {
+		checker.Add("cache", redis.Ping)
+	}
+	checker.Add("migrations", func(ctx context.Context) error {
+		pending, err := backend.PendingMigrations(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("pending migrations: %v", pending)
+		}
+		return nil
 	})
 
-	// Public routes (no authentication required)
-	router.POST("/register", controller.RegisterUser(client))
-	router.POST("/login", controller.LoginUser(client))
-	router.POST("/refresh", controller.RefreshToken(client))
-	router.POST("/logout", controller.Logout(client)) // Public so logout works when access token expired (uses refresh cookie)
-	router.GET("/movies", controller.GetMovies(client))
-	router.GET("/genres", controller.GetGenres(client))
-
-	// Protected routes (require authentication)
-	protected := router.Group("/")
-	protected.Use(middleware.AuthMiddleware())
-	{
-		protected.GET("/profile", controller.GetProfile(client))
-		protected.GET("/movie/:imdb_id", controller.GetMovie(client))
-		protected.POST("/addmovie", controller.AddMovie(client))
-		protected.GET("/recommendedmovies", controller.GetRecommendedMovies(client))
-		protected.PATCH("/updatereview/:imdb_id", controller.AdminReviewUpdate(client))
-	}
-
-	fmt.Println("🚀 Server starting on http://localhost:8080")
-	fmt.Println("📚 API Endpoints:")
-	fmt.Println("  Public:")
-	fmt.Println("    POST   /register  - User registration")
-	fmt.Println("    POST   /login     - User login")
-	fmt.Println("    POST   /refresh   - Refresh access token")
-	fmt.Println("    POST   /logout    - Logout (uses refresh cookie if access token expired)")
-	fmt.Println("    GET    /movies    - Get all movies")
-	fmt.Println("    GET    /genres    - Get all genres")
-	fmt.Println("  Protected (require authentication):")
-	fmt.Println("    GET    /profile                  - Get user profile")
-	fmt.Println("    GET    /movie/:imdb_id           - Get single movie")
-	fmt.Println("    POST   /addmovie                 - Add movie")
-	fmt.Println("    GET    /recommendedmovies        - Get recommended movies")
-	fmt.Println("    PATCH  /updatereview/:imdb_id    - Update movie review (admin only)")
-
-	if err := router.Run("localhost:8080"); err != nil {
-		fmt.Println("failed to start server", err)
+	router, doc := newRouter(h, checker)
+	for _, d := range openapi.Drift(doc, router.Routes()) {
+		slog.Warn("OpenAPI document out of sync with routes", "drift", d)
+	}
+
+	serverConfig := server.LoadConfig()
+
+	// Imports still running at shutdown are cancelled; the batches they wrote are kept
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), serverConfig.ShutdownTimeout)
+		defer cancel()
+		if err := h.Imports.Close(ctx); err != nil {
+			slog.Error("failed to stop running imports", "error", err)
+		}
+	}()
+
+	// Stale metadata is refreshed in the background (ENRICH_REFRESH_INTERVAL, 0 disables)
+	if h.Enrichment != nil && enrichConfig.RefreshInterval > 0 {
+		refresher := enrichment.NewRefresher(h.Enrichment, enrichConfig)
+		refresher.Start(context.Background())
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), serverConfig.ShutdownTimeout)
+			defer cancel()
+			if err := refresher.Close(ctx); err != nil {
+				slog.Error("failed to stop the metadata refresher", "error", err)
+			}
+		}()
+	}
+	scheme := "http"
+	if serverConfig.TLSEnabled() {
+		scheme = "https"
+	}
+
+	slog.Info("server starting", "url", scheme+"://"+serverConfig.Addr)
+	for _, route := range router.Routes() {
+		slog.Debug("route registered", "method", route.Method, "path", route.Path)
+	}
+
+	// Stop on SIGINT/SIGTERM: drain in-flight requests, then the deferred Close disconnects storage
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := server.Run(ctx, router, serverConfig, checker.SetShuttingDown); err != nil {
+		slog.Error("server error", "error", err)
+		exitCode = 1
+		return
 	}
+	slog.Info("server stopped gracefully")
 }
@@ -0,0 +1,133 @@
+// Package metrics exposes Prometheus metrics for HTTP traffic, authentication, caching and MongoDB.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every collector served on /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route template and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_logins_total",
+		Help: "Login attempts by result (success, failure).",
+	}, []string{"result"})
+
+	refreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_token_refreshes_total",
+		Help: "Refresh token exchanges by result (success, failure).",
+	}, []string{"result"})
+
+	revocations = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "auth_token_revocations_total",
+		Help: "Refresh tokens revoked by logout.",
+	})
+
+	tokenValidationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_token_validation_failures_total",
+		Help: "Access tokens rejected by AuthMiddleware, by reason (missing, empty, expired, invalid).",
+	}, []string{"reason"})
+
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_lookups_total",
+		Help: "Catalogue cache lookups by lookup name and result (hit, miss, error).",
+	}, []string{"lookup", "result"})
+
+	mongoDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongodb_command_duration_seconds",
+		Help:    "MongoDB command latency by command, collection and outcome.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"command", "collection", "status"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration,
+		logins, refreshes, revocations, tokenValidationFailures,
+		cacheLookups,
+		mongoDuration,
+	)
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler() gin.HandlerFunc {
+	h := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	return gin.WrapH(h)
+}
+
+// Middleware records request count and latency per route template (e.g. "/movie/:imdb_id"),
+// so path parameters do not explode label cardinality.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+func result(ok bool) string {
+	if ok {
+		return "success"
+	}
+	return "failure"
+}
+
+// RecordLogin counts a login attempt.
+func RecordLogin(ok bool) {
+	logins.WithLabelValues(result(ok)).Inc()
+}
+
+// RecordRefresh counts a refresh token exchange.
+func RecordRefresh(ok bool) {
+	refreshes.WithLabelValues(result(ok)).Inc()
+}
+
+// RecordRevocation counts a revoked refresh token.
+func RecordRevocation() {
+	revocations.Inc()
+}
+
+// RecordTokenValidationFailure counts an access token rejected by AuthMiddleware.
+func RecordTokenValidationFailure(reason string) {
+	tokenValidationFailures.WithLabelValues(reason).Inc()
+}
+
+// Cache lookup results.
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error"
+)
+
+// RecordCacheLookup counts a catalogue cache lookup.
+func RecordCacheLookup(lookup, result string) {
+	cacheLookups.WithLabelValues(lookup, result).Inc()
+}
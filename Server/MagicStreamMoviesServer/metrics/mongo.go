@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// commandKey identifies an in-flight command; request ids are only unique per connection.
+type commandKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// MongoCommandMonitor returns a driver CommandMonitor that records command timings.
+// Attach it with options.Client().SetMonitor.
+func MongoCommandMonitor() *event.CommandMonitor {
+	var inflight sync.Map // commandKey -> collection name
+
+	finish := func(connectionID string, requestID int64, command, status string, duration time.Duration) {
+		key := commandKey{connectionID, requestID}
+		v, _ := inflight.LoadAndDelete(key)
+		collection, _ := v.(string)
+		mongoDuration.WithLabelValues(command, collection, status).Observe(duration.Seconds())
+	}
+
+	return &event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			// For CRUD commands the first element holds the collection name, e.g. {find: "movies"}
+			collection := ""
+			if elem, err := e.Command.IndexErr(0); err == nil {
+				if name, ok := elem.Value().StringValueOK(); ok {
+					collection = name
+				}
+			}
+			inflight.Store(commandKey{e.ConnectionID, e.RequestID}, collection)
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			finish(e.ConnectionID, e.RequestID, e.CommandName, "success", e.Duration)
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			finish(e.ConnectionID, e.RequestID, e.CommandName, "failure", e.Duration)
+		},
+	}
+}
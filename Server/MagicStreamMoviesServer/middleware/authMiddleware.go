@@ -1,36 +1,49 @@
 package middleware
 
 import (
-	"net/http"
+	"errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/logging"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/metrics"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
 	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/utils"
 )
 
 // AuthMiddleware validates JWT access tokens and sets user info in context.
 // It extracts the token from cookies, validates it, and stores userId and role in Gin context.
-// If validation fails, it aborts the request with 401 Unauthorized.
+// If validation fails, it aborts the request with a 401 problem (token_missing, token_expired or token_invalid).
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Extract token from cookie
 		token, err := utils.GetAccessToken(c)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "No token provided"})
+			metrics.RecordTokenValidationFailure("missing")
+			c.Error(apierror.Unauthorized(apierror.CodeTokenMissing, "No token provided"))
 			c.Abort()
 			return
 		}
 
 		// Check if token is empty
 		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token is empty"})
+			metrics.RecordTokenValidationFailure("empty")
+			c.Error(apierror.Unauthorized(apierror.CodeTokenMissing, "Token is empty"))
 			c.Abort()
 			return
 		}
 
 		// Validate token signature and expiration
 		claims, err := utils.ValidateAccessToken(token)
+		if errors.Is(err, utils.ErrTokenExpired) {
+			metrics.RecordTokenValidationFailure("expired")
+			c.Error(apierror.Unauthorized(apierror.CodeTokenExpired, "Access token has expired"))
+			c.Abort()
+			return
+		}
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			metrics.RecordTokenValidationFailure("invalid")
+			c.Error(apierror.Unauthorized(apierror.CodeTokenInvalid, "Access token is invalid").Wrap(err))
 			c.Abort()
 			return
 		}
@@ -43,7 +56,29 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("userId", userId)
 		c.Set("role", role) // Use "role" to match GetRoleFromContext
 
+		// Request logs from here on carry the authenticated user
+		logging.AddAttrs(c, "user_id", userId)
+
 		// Continue to next handler
 		c.Next()
 	}
 }
+
+// RequireAdmin aborts with 403 forbidden unless AuthMiddleware authenticated an ADMIN.
+// It must run after AuthMiddleware.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, err := utils.GetRoleFromContext(c)
+		if err != nil {
+			c.Error(apierror.Unauthorized(apierror.CodeUnauthenticated, "User not authenticated"))
+			c.Abort()
+			return
+		}
+		if role != models.RoleAdmin {
+			c.Error(apierror.Forbidden(apierror.CodeForbidden, "Admin role required"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultCompressMinSize is the smallest body worth compressing; below it the
+// encoding overhead outweighs the savings.
+const defaultCompressMinSize = 1024
+
+// Compress encodes responses with brotli or gzip, as negotiated by Accept-Encoding,
+// when the body is JSON or text and at least COMPRESS_MIN_BYTES (default 1024) long.
+// The body is buffered up to that size before deciding, so small responses are
+// sent untouched.
+func Compress() gin.HandlerFunc {
+	minSize := defaultCompressMinSize
+	if n, err := strconv.Atoi(os.Getenv("COMPRESS_MIN_BYTES")); err == nil && n >= 0 {
+		minSize = n
+	}
+
+	return func(c *gin.Context) {
+		// Caches must key on Accept-Encoding even when this response goes out uncompressed.
+		c.Header("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		cw := &compressWriter{ResponseWriter: original, encoding: encoding, minSize: minSize}
+		c.Writer = cw
+		defer func() {
+			cw.finish()
+			c.Writer = original
+		}()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding picks br, then gzip, honouring q=0 exclusions.
+func negotiateEncoding(header string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		accepted[strings.ToLower(name)] = q > 0
+	}
+
+	switch {
+	case accepted["br"]:
+		return "br"
+	case accepted["gzip"], accepted["*"]:
+		return "gzip"
+	}
+	return ""
+}
+
+func compressible(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(mediaType)
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/json" ||
+		strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/javascript"
+}
+
+var (
+	gzipPool   = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+	brotliPool = sync.Pool{New: func() any { return brotli.NewWriterLevel(io.Discard, 4) }}
+)
+
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// compressWriter buffers the start of the body until it knows whether the
+// response is large and compressible enough, then streams through an encoder.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+
+	buf     []byte
+	decided bool
+	enc     encoder
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.enc != nil {
+			return w.enc.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= w.minSize {
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports buffered bytes too, so error middleware does not render a
+// second body over a response that is merely still buffered.
+func (w *compressWriter) Written() bool {
+	return w.decided || len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.enc != nil {
+		w.enc.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to extend write deadlines.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.decided = true
+	return w.ResponseWriter.Hijack()
+}
+
+// decide picks identity or the negotiated encoding and writes out the buffer.
+func (w *compressWriter) decide() error {
+	w.decided = true
+
+	h := w.Header()
+	if compressible(h.Get("Content-Type")) {
+		status := w.Status()
+		if len(w.buf) >= w.minSize && h.Get("Content-Encoding") == "" &&
+			status != http.StatusNoContent && status != http.StatusNotModified {
+			h.Set("Content-Encoding", w.encoding)
+			h.Del("Content-Length")
+
+			if w.encoding == "br" {
+				w.enc = brotliPool.Get().(*brotli.Writer)
+			} else {
+				w.enc = gzipPool.Get().(*gzip.Writer)
+			}
+			w.enc.Reset(w.ResponseWriter)
+		}
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if w.enc != nil {
+		_, err := w.enc.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// finish flushes anything still buffered and returns the encoder to its pool.
+func (w *compressWriter) finish() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.enc == nil {
+		return
+	}
+	w.enc.Close()
+	w.enc.Reset(io.Discard)
+	switch enc := w.enc.(type) {
+	case *brotli.Writer:
+		brotliPool.Put(enc)
+	case *gzip.Writer:
+		gzipPool.Put(enc)
+	}
+	w.enc = nil
+}
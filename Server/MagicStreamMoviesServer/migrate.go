@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/storage"
+)
+
+// runStartupMigrations applies or checks migrations according to MIGRATE_ON_START:
+// "up" (default) applies pending migrations, "check" refuses to start while any are pending,
+// and "off" skips the step entirely.
+func runStartupMigrations(ctx context.Context, backend *storage.Backend) error {
+	mode := strings.ToLower(os.Getenv("MIGRATE_ON_START"))
+
+	switch mode {
+	case "off":
+		return nil
+	case "check":
+		pending, err := backend.PendingMigrations(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("pending migrations %v; run with -migrate up", pending)
+		}
+		return nil
+	case "", "up":
+		ran, err := backend.Migrate(ctx)
+		for _, name := range ran {
+			slog.Info("applied migration", "migration", name)
+		}
+		return err
+	}
+
+	return fmt.Errorf("unknown MIGRATE_ON_START %q (want up, check or off)", mode)
+}
+
+// runMigrateCommand implements the -migrate flag: up, down or status. status fails
+// while migrations are pending, so it can gate a deploy.
+func runMigrateCommand(ctx context.Context, backend *storage.Backend, command string) error {
+	switch command {
+	case "up":
+		ran, err := backend.Migrate(ctx)
+		for _, name := range ran {
+			slog.Info("applied migration", "migration", name)
+		}
+		if err == nil && len(ran) == 0 {
+			slog.Info("no pending migrations")
+		}
+		return err
+	case "down":
+		name, err := backend.RollbackMigration(ctx)
+		if err == nil {
+			if name == "" {
+				slog.Info("no applied migrations to revert")
+			} else {
+				slog.Info("reverted migration", "migration", name)
+			}
+		}
+		return err
+	case "status":
+		pending, err := backend.PendingMigrations(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			slog.Info("database is up to date")
+			return nil
+		}
+		return fmt.Errorf("pending migrations %v; run with -migrate up", pending)
+	}
+
+	return fmt.Errorf("unknown -migrate command %q (want up, down or status)", command)
+}
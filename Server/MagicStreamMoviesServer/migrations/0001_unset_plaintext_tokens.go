@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Older builds stored access and refresh tokens in plain text on the user document.
+// UpdateAllTokens used to $unset them on every login; this does it once for all users.
+// The tokens cannot be restored, so Down is a no-op.
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "unset_plaintext_tokens",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			filter := bson.M{"$or": bson.A{
+				bson.M{"token": bson.M{"$exists": true}},
+				bson.M{"refresh_token": bson.M{"$exists": true}},
+			}}
+			update := bson.M{"$unset": bson.M{"token": "", "refresh_token": ""}}
+			_, err := db.Collection("users").UpdateMany(ctx, filter, update)
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type indexDefinition struct {
+	collection string
+	name       string
+	keys       bson.D
+	unique     bool
+}
+
+// indexes backs the lookups the handlers run on every request:
+// GetMovie/AdminReviewUpdate by imdb_id, GetRecommendedMovies by genre and ranking,
+// login by email and every token/profile lookup by user_id.
+var indexes = []indexDefinition{
+	{collection: "movies", name: "imdb_id_unique", keys: bson.D{{Key: "imdb_id", Value: 1}}, unique: true},
+	{collection: "movies", name: "genre_name", keys: bson.D{{Key: "genre.genre_name", Value: 1}}},
+	{collection: "movies", name: "ranking_value", keys: bson.D{{Key: "ranking.ranking_value", Value: 1}}},
+	{collection: "users", name: "email_unique", keys: bson.D{{Key: "email", Value: 1}}, unique: true},
+	{collection: "users", name: "user_id_unique", keys: bson.D{{Key: "user_id", Value: 1}}, unique: true},
+}
+
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "create_indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			for _, idx := range indexes {
+				opts := options.Index().SetName(idx.name)
+				if idx.unique {
+					opts.SetUnique(true)
+				}
+				_, err := db.Collection(idx.collection).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: idx.keys, Options: opts})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			for _, idx := range indexes {
+				err := db.Collection(idx.collection).Indexes().DropOne(ctx, idx.name)
+				if err != nil && !isIndexNotFound(err) {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// isIndexNotFound reports the server's IndexNotFound error (code 27), so Down can be re-run.
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && cmdErr.Code == 27
+}
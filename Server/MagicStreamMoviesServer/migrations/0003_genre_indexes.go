@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// genreIndexes make genre_id a real key for the genre admin API: unique in the
+// genres collection, and indexed where movies and users embed genre copies so
+// renames, merges and in-use checks do not scan whole collections.
+var genreIndexes = []indexDefinition{
+	{collection: "genres", name: "genre_id_unique", keys: bson.D{{Key: "genre_id", Value: 1}}, unique: true},
+	{collection: "movies", name: "genre_id", keys: bson.D{{Key: "genre.genre_id", Value: 1}}},
+	{collection: "users", name: "favourite_genre_id", keys: bson.D{{Key: "favourite_genres.genre_id", Value: 1}}},
+}
+
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "create_genre_indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			for _, idx := range genreIndexes {
+				opts := options.Index().SetName(idx.name)
+				if idx.unique {
+					opts.SetUnique(true)
+				}
+				_, err := db.Collection(idx.collection).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: idx.keys, Options: opts})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			for _, idx := range genreIndexes {
+				err := db.Collection(idx.collection).Indexes().DropOne(ctx, idx.name)
+				if err != nil && !isIndexNotFound(err) {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// metadataIndexes back the GetMovies filters on the descriptive metadata fields.
+// Existing movies need no rewrite: models.Movie decodes missing fields as unknown.
+var metadataIndexes = []indexDefinition{
+	{collection: "movies", name: "release_date", keys: bson.D{{Key: "release_date", Value: 1}}},
+	{collection: "movies", name: "directors", keys: bson.D{{Key: "directors", Value: 1}}},
+	{collection: "movies", name: "cast_name", keys: bson.D{{Key: "cast.name", Value: 1}}},
+}
+
+func init() {
+	register(Migration{
+		Version: 4,
+		Name:    "create_movie_metadata_indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			for _, idx := range metadataIndexes {
+				opts := options.Index().SetName(idx.name)
+				_, err := db.Collection(idx.collection).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: idx.keys, Options: opts})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			for _, idx := range metadataIndexes {
+				err := db.Collection(idx.collection).Indexes().DropOne(ctx, idx.name)
+				if err != nil && !isIndexNotFound(err) {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
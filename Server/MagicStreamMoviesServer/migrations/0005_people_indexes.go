@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// peopleIndexes make person_id the key of the people collection and back the
+// filmography and rename lookups, which find movies by their embedded credits.
+var peopleIndexes = []indexDefinition{
+	{collection: "people", name: "person_id_unique", keys: bson.D{{Key: "person_id", Value: 1}}, unique: true},
+	{collection: "people", name: "name", keys: bson.D{{Key: "name", Value: 1}}},
+	{collection: "movies", name: "credits_person_id", keys: bson.D{{Key: "credits.person_id", Value: 1}}},
+}
+
+func init() {
+	register(Migration{
+		Version: 5,
+		Name:    "create_people_indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			for _, idx := range peopleIndexes {
+				opts := options.Index().SetName(idx.name)
+				if idx.unique {
+					opts.SetUnique(true)
+				}
+				_, err := db.Collection(idx.collection).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: idx.keys, Options: opts})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			for _, idx := range peopleIndexes {
+				err := db.Collection(idx.collection).Indexes().DropOne(ctx, idx.name)
+				if err != nil && !isIndexNotFound(err) {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// collectionIndexes key the collections collection by collection_id and make
+// movie_ids unique across documents, so a movie belongs to at most one collection
+// and GetMovie finds it with an index lookup. The partial filter skips documents
+// with no members, which a plain unique multikey index would treat as duplicates.
+var collectionIndexes = []struct {
+	indexDefinition
+	partial bson.D
+}{
+	{indexDefinition: indexDefinition{collection: "collections", name: "collection_id_unique", keys: bson.D{{Key: "collection_id", Value: 1}}, unique: true}},
+	{
+		indexDefinition: indexDefinition{collection: "collections", name: "movie_ids_unique", keys: bson.D{{Key: "movie_ids", Value: 1}}, unique: true},
+		partial:         bson.D{{Key: "movie_ids", Value: bson.D{{Key: "$type", Value: "string"}}}},
+	},
+}
+
+func init() {
+	register(Migration{
+		Version: 6,
+		Name:    "create_collection_indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			for _, idx := range collectionIndexes {
+				opts := options.Index().SetName(idx.name)
+				if idx.unique {
+					opts.SetUnique(true)
+				}
+				if idx.partial != nil {
+					opts.SetPartialFilterExpression(idx.partial)
+				}
+				_, err := db.Collection(idx.collection).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: idx.keys, Options: opts})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			for _, idx := range collectionIndexes {
+				err := db.Collection(idx.collection).Indexes().DropOne(ctx, idx.name)
+				if err != nil && !isIndexNotFound(err) {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// enrichedAtIndex lets the metadata refresher find never-enriched and stale movies
+// without a collection scan. Missing enriched_at values are indexed as null.
+var enrichedAtIndex = indexDefinition{collection: "movies", name: "enriched_at", keys: bson.D{{Key: "enriched_at", Value: 1}}}
+
+func init() {
+	register(Migration{
+		Version: 7,
+		Name:    "create_enriched_at_index",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			opts := options.Index().SetName(enrichedAtIndex.name)
+			_, err := db.Collection(enrichedAtIndex.collection).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: enrichedAtIndex.keys, Options: opts})
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			err := db.Collection(enrichedAtIndex.collection).Indexes().DropOne(ctx, enrichedAtIndex.name)
+			if err != nil && !isIndexNotFound(err) {
+				return err
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,171 @@
+// Package migrations applies versioned schema and data migrations to MongoDB.
+// Applied steps are recorded in the "migrations" collection so each runs once.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const collectionName = "migrations"
+
+// Migration is a single reversible step. Up and Down must be safe to re-run.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+	Down    func(ctx context.Context, db *mongo.Database) error
+}
+
+// Status is a migration together with when it was applied (nil if pending).
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type record struct {
+	Version   int       `bson:"version"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+var registry []Migration
+
+// register adds a migration to the set applied by Up. Called from init in each migration file.
+func register(m Migration) {
+	registry = append(registry, m)
+	sort.Slice(registry, func(i, j int) bool { return registry[i].Version < registry[j].Version })
+}
+
+// All returns every known migration ordered by version.
+func All() []Migration {
+	return append([]Migration(nil), registry...)
+}
+
+// Migrator runs migrations against one database.
+type Migrator struct {
+	db *mongo.Database
+}
+
+// New returns a Migrator for db.
+func New(db *mongo.Database) *Migrator {
+	return &Migrator{db: db}
+}
+
+func (m *Migrator) collection() *mongo.Collection {
+	return m.db.Collection(collectionName)
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]record, error) {
+	cursor, err := m.collection().Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	applied := make(map[int]record, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+// Status lists every migration with its applied time.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(registry))
+	for _, mig := range registry {
+		s := Status{Migration: mig}
+		if r, ok := applied[mig.Version]; ok {
+			at := r.AppliedAt
+			s.AppliedAt = &at
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// Pending returns the migrations that have not been applied yet.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending = append(pending, s.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies all pending migrations in version order and returns the ones it ran.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	// A unique version index makes a concurrent second runner fail instead of double-recording.
+	_, err := m.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to index %s collection: %w", collectionName, err)
+	}
+
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, mig := range pending {
+		if err := mig.Up(ctx, m.db); err != nil {
+			return ran, fmt.Errorf("migration %d (%s) failed: %w", mig.Version, mig.Name, err)
+		}
+		_, err := m.collection().InsertOne(ctx, record{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now()})
+		if err != nil {
+			return ran, fmt.Errorf("failed to record migration %d: %w", mig.Version, err)
+		}
+		ran = append(ran, mig)
+	}
+	return ran, nil
+}
+
+// Down reverts the most recently applied migration and returns it.
+// It returns nil, nil when nothing has been applied.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(registry) - 1; i >= 0; i-- {
+		mig := registry[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if mig.Down == nil {
+			return nil, fmt.Errorf("migration %d (%s) cannot be reverted", mig.Version, mig.Name)
+		}
+		if err := mig.Down(ctx, m.db); err != nil {
+			return nil, fmt.Errorf("reverting migration %d (%s) failed: %w", mig.Version, mig.Name, err)
+		}
+		if _, err := m.collection().DeleteOne(ctx, bson.M{"version": mig.Version}); err != nil {
+			return nil, err
+		}
+		return &mig, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Collection groups movies that belong together, such as a franchise or a trilogy.
+// MovieIDs holds imdb_ids in viewing order; a movie belongs to at most one collection.
+type Collection struct {
+	CollectionID string    `bson:"collection_id" json:"collection_id"`
+	Name         string    `bson:"name" json:"name" validate:"required,min=2,max=200"`
+	Overview     string    `bson:"overview" json:"overview" validate:"max=5000"`
+	MovieIDs     []string  `bson:"movie_ids" json:"movie_ids" validate:"max=100,unique,dive,required"`
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// CollectionRef places a movie within its collection, e.g. part 2 of 3.
+type CollectionRef struct {
+	CollectionID string `json:"collection_id"`
+	Name         string `json:"name"`
+	Position     int    `json:"position"` // 1-based
+	Total        int    `json:"total"`
+}
+
+// RefFor returns where imdbID sits in the collection, or false if it is not a member.
+func (c Collection) RefFor(imdbID string) (CollectionRef, bool) {
+	for i, id := range c.MovieIDs {
+		if id == imdbID {
+			return CollectionRef{CollectionID: c.CollectionID, Name: c.Name, Position: i + 1, Total: len(c.MovieIDs)}, true
+		}
+	}
+	return CollectionRef{}, false
+}
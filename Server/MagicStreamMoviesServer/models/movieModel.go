@@ -1,6 +1,9 @@
 package models
 
 import (
+	"strconv"
+	"time"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -23,4 +26,64 @@ type Movie struct {
 	Genre       []Genre       `bson:"genre" json:"genre" validate:"required,dive"`
 	AdminReview string        `bson:"admin_review" json:"admin_review" validate:"required"`
 	Ranking     Ranking       `bson:"ranking" json:"ranking" validate:"required"`
+
+	// Descriptive metadata. All optional: movies stored before these fields existed
+	// decode with zero values, and a zero value means "unknown".
+	ReleaseDate      string       `bson:"release_date" json:"release_date" validate:"omitempty,datetime=2006-01-02"`
+	RuntimeMinutes   int          `bson:"runtime_minutes" json:"runtime_minutes" validate:"omitempty,gte=1,lte=1000"`
+	Synopsis         string       `bson:"synopsis" json:"synopsis" validate:"max=5000"`
+	OriginalLanguage string       `bson:"original_language" json:"original_language" validate:"omitempty,len=2,lowercase,alpha"` // ISO 639-1
+	Certification    string       `bson:"certification" json:"certification" validate:"max=16"`                                  // e.g. "PG-13"
+	Directors        []string     `bson:"directors" json:"directors" validate:"max=20,dive,required,max=200"`
+	Cast             []CastMember `bson:"cast" json:"cast" validate:"max=200,dive"`
+
+	// Credits link the movie to people. When set, Directors and Cast are derived from them.
+	Credits []Credit `bson:"credits" json:"credits" validate:"max=500,dive"`
+
+	// EnrichedAt is when the metadata was last refreshed from the external movie
+	// database; nil if it never was.
+	EnrichedAt *time.Time `bson:"enriched_at,omitempty" json:"enriched_at,omitempty"`
+}
+
+// CastMember is one billed performer; cast lists are kept in billing order.
+type CastMember struct {
+	Name      string `bson:"name" json:"name" validate:"required,max=200"`
+	Character string `bson:"character" json:"character" validate:"max=200"`
+}
+
+// ReleaseYear returns the year of ReleaseDate, or 0 if it is unknown.
+func (m Movie) ReleaseYear() int {
+	if len(m.ReleaseDate) < 4 {
+		return 0
+	}
+	year, _ := strconv.Atoi(m.ReleaseDate[:4])
+	return year
+}
+
+// FillDefaults replaces nil lists with empty ones, so movies always serialise
+// genre, directors, cast and credits as arrays.
+func (m *Movie) FillDefaults() {
+	if m.Genre == nil {
+		m.Genre = []Genre{}
+	}
+	if m.Directors == nil {
+		m.Directors = []string{}
+	}
+	if m.Cast == nil {
+		m.Cast = []CastMember{}
+	}
+	if m.Credits == nil {
+		m.Credits = []Credit{}
+	}
+}
+
+// UnmarshalBSON decodes documents written before the metadata fields existed:
+// missing fields keep their zero values and missing lists decode as empty.
+func (m *Movie) UnmarshalBSON(data []byte) error {
+	type plain Movie // drops this method so Unmarshal does not recurse
+	if err := bson.Unmarshal(data, (*plain)(m)); err != nil {
+		return err
+	}
+	m.FillDefaults()
+	return nil
 }
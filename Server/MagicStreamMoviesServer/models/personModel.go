@@ -0,0 +1,58 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Credit roles.
+const (
+	CreditActor    = "actor"
+	CreditDirector = "director"
+	CreditWriter   = "writer"
+	CreditProducer = "producer"
+	CreditComposer = "composer"
+)
+
+// Person is anyone credited on a movie. PersonID is the hex of a fresh ObjectID,
+// assigned on creation like User.UserID.
+type Person struct {
+	PersonID  string    `bson:"person_id" json:"person_id"`
+	Name      string    `bson:"name" json:"name" validate:"required,max=200"`
+	BirthDate string    `bson:"birth_date" json:"birth_date" validate:"omitempty,datetime=2006-01-02"`
+	Biography string    `bson:"biography" json:"biography" validate:"max=10000"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Credit links a person to a movie. Name is a copy of Person.Name, kept in step
+// on rename, so movies render without looking up every person.
+type Credit struct {
+	PersonID  string `bson:"person_id" json:"person_id" validate:"required"`
+	Name      string `bson:"name" json:"name" validate:"-"`
+	Role      string `bson:"role" json:"role" validate:"required,oneof=actor director writer producer composer"`
+	Character string `bson:"character" json:"character" validate:"max=200"`
+	// Order is the billing position; lower comes first.
+	Order int `bson:"order" json:"order" validate:"gte=0"`
+}
+
+// SortCredits orders credits by billing position, keeping submission order for ties.
+func SortCredits(credits []Credit) {
+	sort.SliceStable(credits, func(i, j int) bool { return credits[i].Order < credits[j].Order })
+}
+
+// DirectorsAndCast derives Movie.Directors and Movie.Cast from sorted credits, so the
+// name-based list filters and clients reading only those fields see the credited people.
+func DirectorsAndCast(credits []Credit) ([]string, []CastMember) {
+	directors := []string{}
+	cast := []CastMember{}
+	for _, c := range credits {
+		switch c.Role {
+		case CreditDirector:
+			directors = append(directors, c.Name)
+		case CreditActor:
+			cast = append(cast, CastMember{Name: c.Name, Character: c.Character})
+		}
+	}
+	return directors, cast
+}
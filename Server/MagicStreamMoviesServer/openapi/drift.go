@@ -0,0 +1,39 @@
+package openapi
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Drift compares the routes registered on the Gin engine with the document and
+// returns one message per mismatch, sorted; an empty result means they agree.
+// main runs it at startup and with -check-openapi, which exits non-zero on drift
+// so CI catches a route added without documentation (or documented but never mounted).
+func Drift(doc *Document, registered gin.RoutesInfo) []string {
+	inSpec := map[string]bool{}
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			inSpec[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	var drift []string
+	seen := map[string]bool{}
+	for _, r := range registered {
+		key := r.Method + " " + specPath(r.Path)
+		seen[key] = true
+		if !inSpec[key] {
+			drift = append(drift, "registered but not documented: "+key)
+		}
+	}
+	for key := range inSpec {
+		if !seen[key] {
+			drift = append(drift, "documented but not registered: "+key)
+		}
+	}
+
+	slices.Sort(drift)
+	return drift
+}
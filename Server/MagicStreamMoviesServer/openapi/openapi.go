@@ -0,0 +1,259 @@
+// Package openapi generates the OpenAPI 3.1 document for the API from the route
+// table and the model types, and serves it together with an interactive docs page.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/apierror"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/routes"
+)
+
+// Paths served by this package.
+const (
+	SpecPath = "/openapi.json"
+	DocsPath = "/docs"
+)
+
+// Document is an OpenAPI 3.1 document.
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type Operation struct {
+	Summary     string                `json:"summary"`
+	OperationID string                `json:"operationId"`
+	Tags        []string              `json:"tags,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+const cookieAuth = "cookieAuth"
+
+// opsRoutes are the unversioned operational endpoints registered in main.
+var opsRoutes = []struct {
+	method, path, summary, contentType string
+}{
+	{http.MethodGet, "/hello", "Greeting", "text/plain"},
+	{http.MethodGet, "/healthz", "Liveness probe", "application/json"},
+	{http.MethodGet, "/readyz", "Readiness probe (database, JWT keys, migrations)", "application/json"},
+	{http.MethodGet, "/metrics", "Prometheus metrics", "text/plain"},
+	{http.MethodGet, SpecPath, "This OpenAPI document", "application/json"},
+	{http.MethodGet, DocsPath, "Interactive API documentation", "text/html"},
+}
+
+// Build generates the document for the route table. Legacy aliases are listed
+// as deprecated operations so clients can find their successors.
+func Build(table []routes.Route) *Document {
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info: Info{
+			Title:       "MagicStream API",
+			Version:     "1.0.0",
+			Description: "Errors are returned as RFC 7807 application/problem+json with a machine-readable code.",
+		},
+		Paths: map[string]map[string]Operation{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+			SecuritySchemes: map[string]SecurityScheme{
+				cookieAuth: {Type: "apiKey", In: "cookie", Name: "access_token", Description: "Set by login, register and refresh"},
+			},
+		},
+	}
+	s := schemas(doc.Components.Schemas)
+	problem := s.of(reflect.TypeFor[apierror.Problem]())
+
+	for _, r := range table {
+		op := s.operation(r, problem)
+		doc.add(r.Method, routes.V1Prefix+r.Path, op)
+
+		if r.Legacy != "" {
+			legacy := op
+			legacy.OperationID += "Legacy"
+			legacy.Deprecated = true
+			legacy.Summary += " (deprecated alias of " + routes.V1Prefix + r.Path + ")"
+			legacy.Tags = []string{"legacy"}
+			doc.add(r.Method, r.Legacy, legacy)
+		}
+	}
+
+	for _, o := range opsRoutes {
+		doc.add(o.method, o.path, Operation{
+			Summary:     o.summary,
+			OperationID: operationID(o.method, o.path),
+			Tags:        []string{"operations"},
+			Responses: map[string]Response{
+				"200": {Description: "OK", Content: map[string]MediaType{o.contentType: {Schema: &Schema{}}}},
+			},
+		})
+	}
+
+	return doc
+}
+
+func (s schemas) operation(r routes.Route, problem *Schema) Operation {
+	op := Operation{
+		Summary:     r.Summary,
+		OperationID: operationID(r.Method, r.Path),
+		Tags:        []string{tag(r.Path)},
+		Responses:   map[string]Response{},
+	}
+
+	for _, segment := range strings.Split(r.Path, "/") {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+
+	pathParams := len(op.Parameters) > 0
+	if r.Query != nil {
+		op.Parameters = append(op.Parameters, s.queryParameters(reflect.TypeOf(r.Query))...)
+	}
+
+	if r.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: s.of(reflect.TypeOf(r.Request))}},
+		}
+	}
+	if len(r.RequestMedia) > 0 {
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{}}
+		for _, mediaType := range r.RequestMedia {
+			op.RequestBody.Content[mediaType] = MediaType{Schema: &Schema{Type: "string"}}
+		}
+		// Uploads are sent as a single form field named "file".
+		if _, ok := op.RequestBody.Content["multipart/form-data"]; ok {
+			op.RequestBody.Content["multipart/form-data"] = MediaType{Schema: &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{"file": {Type: "string", Format: "binary"}},
+				Required:   []string{"file"},
+			}}
+		}
+	}
+
+	success := Response{Description: http.StatusText(r.Status)}
+	if r.Response != nil {
+		success.Content = map[string]MediaType{"application/json": {Schema: s.of(reflect.TypeOf(r.Response))}}
+	}
+	if len(r.ResponseMedia) > 0 {
+		success.Content = map[string]MediaType{}
+		for _, mediaType := range r.ResponseMedia {
+			success.Content[mediaType] = MediaType{Schema: &Schema{Type: "string"}}
+		}
+	}
+	op.Responses[strconv.Itoa(r.Status)] = success
+	if r.Cache.Validate {
+		op.Responses[strconv.Itoa(http.StatusNotModified)] = Response{Description: "Not Modified (If-None-Match / If-Modified-Since matched)"}
+	}
+
+	problemResponse := func(status int) {
+		op.Responses[strconv.Itoa(status)] = Response{
+			Description: http.StatusText(status),
+			Content:     map[string]MediaType{apierror.ContentType: {Schema: problem}},
+		}
+	}
+	if op.RequestBody != nil || len(op.Parameters) > 0 {
+		problemResponse(http.StatusBadRequest)
+	}
+	if r.Auth || r.Admin || strings.HasPrefix(r.Path, "/auth/") {
+		problemResponse(http.StatusUnauthorized)
+	}
+	if r.Auth || r.Admin {
+		op.Security = []map[string][]string{{cookieAuth: {}}}
+	}
+	if r.Admin {
+		problemResponse(http.StatusForbidden)
+	}
+	if pathParams {
+		problemResponse(http.StatusNotFound)
+	}
+	for _, status := range r.Errors {
+		problemResponse(status)
+	}
+	problemResponse(http.StatusInternalServerError)
+
+	return op
+}
+
+func (d *Document) add(method, ginPath string, op Operation) {
+	path := specPath(ginPath)
+	if d.Paths[path] == nil {
+		d.Paths[path] = map[string]Operation{}
+	}
+	d.Paths[path][strings.ToLower(method)] = op
+}
+
+// specPath converts gin's ":param" segments to OpenAPI "{param}" templates.
+func specPath(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID turns "GET /movies/:imdb_id/review" into "getMoviesImdbIdReview".
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == ':' || r == '_' || r == '.' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// tag groups operations by their first path segment ("/movies/:imdb_id" -> "movies").
+func tag(path string) string {
+	first, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return first
+}
@@ -0,0 +1,220 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Schema is the subset of JSON Schema (2020-12, as used by OpenAPI 3.1) the generator emits.
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        any                `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	MinLength   *int               `json:"minLength,omitempty"`
+	MaxLength   *int               `json:"maxLength,omitempty"`
+	MinItems    *int               `json:"minItems,omitempty"`
+	MaxItems    *int               `json:"maxItems,omitempty"`
+	UniqueItems bool               `json:"uniqueItems,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+
+	AdditionalProperties *Schema `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType     = reflect.TypeFor[time.Time]()
+	objectIDType = reflect.TypeFor[bson.ObjectID]()
+)
+
+// schemas collects named struct schemas for components.schemas while generating.
+type schemas map[string]*Schema
+
+// of returns the schema for t, registering named structs as components and
+// returning a $ref to them.
+func (s schemas) of(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case objectIDType:
+		return &Schema{Type: "string", Pattern: "^[0-9a-f]{24}$", Description: "ObjectID as hex"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		if _, ok := s[t.Name()]; !ok {
+			s[t.Name()] = nil // reserve the name so recursive types terminate
+			s[t.Name()] = s.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+	return &Schema{}
+}
+
+// object builds an object schema from json tags, applying validate tag constraints.
+// Untagged embedded structs are flattened into the object, as encoding/json does.
+func (s schemas) object(t reflect.Type) *Schema {
+	obj := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" && f.Anonymous && f.Type.Kind() == reflect.Struct {
+			embedded := s.object(f.Type)
+			for propName, prop := range embedded.Properties {
+				obj.Properties[propName] = prop
+			}
+			obj.Required = append(obj.Required, embedded.Required...)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := s.of(f.Type)
+		if applyValidateTag(prop, f.Type, f.Tag.Get("validate")) {
+			obj.Required = append(obj.Required, name)
+		}
+		obj.Properties[name] = prop
+	}
+	return obj
+}
+
+// queryParameters describes the form-tagged fields of a query struct as "in: query"
+// parameters. Untagged embedded structs contribute their own parameters.
+func (s schemas) queryParameters(t reflect.Type) []Parameter {
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("form"), ",")
+		if name == "" && f.Anonymous && f.Type.Kind() == reflect.Struct {
+			params = append(params, s.queryParameters(f.Type)...)
+			continue
+		}
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		schema := s.of(f.Type)
+		required := applyValidateTag(schema, f.Type, f.Tag.Get("validate"))
+		params = append(params, Parameter{Name: name, In: "query", Required: required, Schema: schema})
+	}
+	return params
+}
+
+// applyValidateTag maps go-playground/validator rules onto JSON Schema keywords
+// and reports whether the field is required. Rules after "dive" apply to elements.
+func applyValidateTag(prop *Schema, t reflect.Type, tag string) (required bool) {
+	if tag == "" || tag == "-" || prop.Ref != "" {
+		return strings.HasPrefix(tag, "required")
+	}
+
+	kind := t.Kind()
+	for _, rule := range strings.Split(tag, ",") {
+		if rule == "dive" {
+			break
+		}
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "email":
+			prop.Format = "email"
+		case "url":
+			prop.Format = "uri"
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				prop.Enum = append(prop.Enum, v)
+			}
+		case "min", "max", "len":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			setBound(prop, kind, name, n)
+		case "gte", "lte":
+			f, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			if name == "gte" {
+				prop.Minimum = &f
+			} else {
+				prop.Maximum = &f
+			}
+		case "datetime":
+			if param == "2006-01-02" {
+				prop.Format = "date"
+			}
+		case "lowercase":
+			prop.Pattern = "^[^A-Z]*$"
+		case "unique":
+			prop.UniqueItems = true
+		}
+	}
+	return required
+}
+
+func setBound(prop *Schema, kind reflect.Kind, rule string, n int) {
+	lower := rule == "min" || rule == "len"
+	upper := rule == "max" || rule == "len"
+
+	switch kind {
+	case reflect.String:
+		if lower {
+			prop.MinLength = &n
+		}
+		if upper {
+			prop.MaxLength = &n
+		}
+	case reflect.Slice, reflect.Array:
+		if lower {
+			prop.MinItems = &n
+		}
+		if upper {
+			prop.MaxItems = &n
+		}
+	default:
+		f := float64(n)
+		if lower {
+			prop.Minimum = &f
+		}
+		if upper {
+			prop.Maximum = &f
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Package poster validates uploaded poster images and renders the variants the
+// site serves: the original file, thumbnails resized to each of Widths, and a
+// lossy WebP copy of every thumbnail. Variants are stored in a blobstore.BlobStore
+// under a content-derived ID, so a stored variant never changes.
+package poster
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	_ "image/gif" // registers the GIF decoder
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/blobstore"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+)
+
+// DefaultMaxUploadBytes caps an uploaded poster unless POSTER_MAX_BYTES says otherwise.
+const DefaultMaxUploadBytes = 10 << 20
+
+// DefaultBaseURL is where the API serves stored images, used unless IMAGE_BASE_URL is set.
+const DefaultBaseURL = "/api/v1/images"
+
+// Bounds on the uploaded image, checked before it is decoded.
+const (
+	minDimension = 100
+	maxDimension = 6000
+)
+
+// Widths are the thumbnail widths rendered, in pixels. Widths not smaller than the
+// original are skipped; the original already serves them.
+var Widths = []int{500, 185}
+
+// jpegQuality is used for thumbnails of JPEG, GIF and WebP uploads.
+const jpegQuality = 85
+
+// webpQuality is used for the WebP copies of thumbnails. WebP holds up better than
+// JPEG at the same setting, so it can be lower for the same look.
+const webpQuality = 80
+
+// ErrUnsupportedType is returned for uploads that are not JPEG, PNG, GIF or WebP.
+var ErrUnsupportedType = errors.New("poster must be a JPEG, PNG, GIF or WebP image")
+
+// ImageError reports an upload of a supported type that cannot be used as a poster.
+type ImageError struct {
+	Reason string
+}
+
+func (e *ImageError) Error() string {
+	return e.Reason
+}
+
+// Image is one stored variant of a poster.
+type Image struct {
+	// Variant is "original" or "w<width>".
+	Variant     string `json:"variant"`
+	File        string `json:"file"` // e.g. "w185.webp"
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Bytes       int    `json:"bytes"`
+
+	data []byte
+}
+
+// Poster is a processed upload.
+type Poster struct {
+	// ID is derived from the uploaded bytes: uploading the same file twice yields the same poster.
+	ID     string  `json:"poster_id"`
+	Images []Image `json:"images"`
+}
+
+// sourceTypes maps the sniffed content type of an upload to its file extension.
+var sourceTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// MaxUploadBytes returns POSTER_MAX_BYTES if it is a positive integer, else DefaultMaxUploadBytes.
+func MaxUploadBytes() int64 {
+	n, err := strconv.ParseInt(os.Getenv("POSTER_MAX_BYTES"), 10, 64)
+	if err != nil || n <= 0 {
+		return DefaultMaxUploadBytes
+	}
+	return n
+}
+
+// BaseURL returns IMAGE_BASE_URL without a trailing slash, or DefaultBaseURL. Set it
+// to serve images from a CDN or the S3 bucket itself; image URLs are BaseURL + "/" + Key.
+// A path, like the default, is made absolute with PUBLIC_BASE_URL.
+func BaseURL() string {
+	if base := strings.TrimRight(os.Getenv("IMAGE_BASE_URL"), "/"); base != "" {
+		return base
+	}
+	return DefaultBaseURL
+}
+
+// Process checks that data is a usable poster and renders its variants. The type
+// is sniffed from the content; the uploaded file name and declared type are ignored.
+func Process(data []byte) (Poster, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := sourceTypes[contentType]
+	if !ok {
+		return Poster{}, ErrUnsupportedType
+	}
+
+	// Check the dimensions from the header first, so an oversized image is never decoded.
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Poster{}, &ImageError{Reason: "is not a readable " + strings.ToUpper(ext) + " image"}
+	}
+	if cfg.Width < minDimension || cfg.Height < minDimension || cfg.Width > maxDimension || cfg.Height > maxDimension {
+		return Poster{}, &ImageError{Reason: fmt.Sprintf("is %dx%d pixels; posters must be between %d and %d pixels on each side",
+			cfg.Width, cfg.Height, minDimension, maxDimension)}
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Poster{}, &ImageError{Reason: "is not a readable " + strings.ToUpper(ext) + " image"}
+	}
+
+	sum := sha256.Sum256(data)
+	p := Poster{ID: hex.EncodeToString(sum[:16])}
+	p.Images = append(p.Images, Image{
+		Variant: "original", File: "original." + ext, ContentType: contentType,
+		Width: cfg.Width, Height: cfg.Height, Bytes: len(data), data: data,
+	})
+
+	for _, width := range Widths {
+		if width >= cfg.Width {
+			continue
+		}
+		variant := "w" + strconv.Itoa(width)
+		// PNG keeps transparency; the other formats become JPEG on a white background.
+		thumb := resize(src, width, ext != "png")
+		var buf bytes.Buffer
+		img := Image{Variant: variant, Width: thumb.Bounds().Dx(), Height: thumb.Bounds().Dy()}
+		if ext == "png" {
+			img.File, img.ContentType = variant+".png", "image/png"
+			err = png.Encode(&buf, thumb)
+		} else {
+			img.File, img.ContentType = variant+".jpg", "image/jpeg"
+			err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: jpegQuality})
+		}
+		if err != nil {
+			return Poster{}, err
+		}
+		img.data = buf.Bytes()
+		img.Bytes = len(img.data)
+		p.Images = append(p.Images, img)
+
+		if webpSupported {
+			data, err := encodeWebP(thumb)
+			if err != nil {
+				return Poster{}, err
+			}
+			p.Images = append(p.Images, Image{
+				Variant: variant, File: variant + ".webp", ContentType: "image/webp",
+				Width: img.Width, Height: img.Height, Bytes: len(data), data: data,
+			})
+		}
+	}
+	return p, nil
+}
+
+// resize scales src to width, keeping its aspect ratio. opaque fills transparent
+// areas with white, for formats without an alpha channel.
+func resize(src image.Image, width int, opaque bool) image.Image {
+	b := src.Bounds()
+	height := max(1, int(math.Round(float64(b.Dy())*float64(width)/float64(b.Dx()))))
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	if opaque {
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	}
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Over, nil)
+	return dst
+}
+
+// Key returns the blob key of one of the poster's files.
+func Key(posterID, file string) string {
+	return "posters/" + posterID + "/" + file
+}
+
+// posterID and fileName match the IDs and file names Process produces.
+var (
+	posterID = regexp.MustCompile(`^[0-9a-f]{32}$`)
+	fileName = regexp.MustCompile(`^(original|w[0-9]+)\.(jpg|png|gif|webp)$`)
+)
+
+// ValidFile reports whether posterID and file could name a stored poster variant.
+func ValidFile(id, file string) bool {
+	return posterID.MatchString(id) && fileName.MatchString(file)
+}
+
+// Save stores every variant of p.
+func Save(ctx context.Context, blobs blobstore.BlobStore, p Poster) error {
+	for _, img := range p.Images {
+		if err := blobs.Put(ctx, Key(p.ID, img.File), bytes.NewReader(img.data), int64(len(img.data)), img.ContentType); err != nil {
+			return fmt.Errorf("storing %s: %w", img.File, err)
+		}
+	}
+	return nil
+}
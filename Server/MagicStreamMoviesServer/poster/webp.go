@@ -0,0 +1,17 @@
+//go:build cgo
+
+package poster
+
+import (
+	"image"
+
+	"github.com/chai2010/webp"
+)
+
+// webpSupported reports whether WebP variants are rendered. The lossy encoder is
+// libwebp, so builds without cgo skip them.
+const webpSupported = true
+
+func encodeWebP(img image.Image) ([]byte, error) {
+	return webp.EncodeRGBA(img, webpQuality)
+}
@@ -0,0 +1,16 @@
+//go:build !cgo
+
+package poster
+
+import (
+	"errors"
+	"image"
+)
+
+// webpSupported reports whether WebP variants are rendered. The lossy encoder is
+// libwebp, so builds without cgo skip them.
+const webpSupported = false
+
+func encodeWebP(image.Image) ([]byte, error) {
+	return nil, errors.New("WebP encoding needs a cgo build")
+}
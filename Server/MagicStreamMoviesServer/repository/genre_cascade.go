@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+)
+
+// Genre ids and names are copied by value into Movie.Genre and User.FavouriteGenres,
+// and recommendations match on the copied name. Renames and merges therefore have to
+// rewrite every copy, not just the genres collection.
+
+// GenreCascade counts the documents a rename or merge rewrote.
+type GenreCascade struct {
+	Movies int64
+	Users  int64
+}
+
+// RenameGenre renames a genre and the copies embedded in movies and users.
+// The genre itself is renamed first, so if a later step fails, running the
+// rename again completes it. Returns ErrNotFound when the genre does not exist.
+func RenameGenre(ctx context.Context, store Store, genreId int, name string) (GenreCascade, error) {
+	if err := store.Genres.Rename(ctx, genreId, name); err != nil {
+		return GenreCascade{}, err
+	}
+	return replaceEverywhere(ctx, store, []int{genreId}, models.Genre{GenreId: genreId, GenreName: name})
+}
+
+// MergeGenres rewrites every embedded copy of the source genre into target, then
+// deletes the source. Movies and users that had both keep a single target entry.
+// The source is deleted last, so a failed merge can simply be retried.
+func MergeGenres(ctx context.Context, store Store, sourceId int, target models.Genre) (GenreCascade, error) {
+	cascade, err := replaceEverywhere(ctx, store, []int{sourceId}, target)
+	if err != nil {
+		return cascade, err
+	}
+	return cascade, store.Genres.Delete(ctx, sourceId)
+}
+
+func replaceEverywhere(ctx context.Context, store Store, fromIDs []int, to models.Genre) (GenreCascade, error) {
+	var cascade GenreCascade
+	var err error
+	if cascade.Movies, err = store.Movies.ReplaceGenre(ctx, fromIDs, to); err != nil {
+		return cascade, err
+	}
+	cascade.Users, err = store.Users.ReplaceFavouriteGenre(ctx, fromIDs, to)
+	return cascade, err
+}
+
+// ReplaceGenres returns genres with every entry whose genre_id is in fromIDs replaced
+// by to, keeping only the first entry for each genre_id, and reports whether anything
+// changed. Backends that cannot rewrite embedded genres server-side use it.
+func ReplaceGenres(genres []models.Genre, fromIDs []int, to models.Genre) ([]models.Genre, bool) {
+	out := make([]models.Genre, 0, len(genres))
+	for _, g := range genres {
+		if slices.Contains(fromIDs, g.GenreId) {
+			g = to
+		}
+		if !slices.ContainsFunc(out, func(seen models.Genre) bool { return seen.GenreId == g.GenreId }) {
+			out = append(out, g)
+		}
+	}
+	return out, !slices.Equal(out, genres)
+}
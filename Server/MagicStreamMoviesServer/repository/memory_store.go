@@ -0,0 +1,771 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// NewMemoryStore returns repositories kept in process memory.
+// It needs no database and is meant for handler tests (httptest) and local experiments.
+func NewMemoryStore(genres ...models.Genre) Store {
+	return Store{
+		Movies:      NewMemoryMovieRepository(),
+		Users:       NewMemoryUserRepository(),
+		Genres:      NewMemoryGenreRepository(genres...),
+		People:      NewMemoryPersonRepository(),
+		Collections: NewMemoryCollectionRepository(),
+	}
+}
+
+// ---------- MOVIES ----------
+
+type memoryMovieRepository struct {
+	mu     sync.RWMutex
+	movies []models.Movie // insertion order, mirrors a collection scan
+}
+
+// NewMemoryMovieRepository returns an empty in-memory MovieRepository.
+func NewMemoryMovieRepository() MovieRepository {
+	return &memoryMovieRepository{}
+}
+
+func (r *memoryMovieRepository) List(ctx context.Context, filter MovieFilter) ([]models.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	movies := []models.Movie{}
+	for _, m := range r.movies {
+		if filter.Matches(m) {
+			movies = append(movies, cloneMovie(m))
+		}
+	}
+	return movies, nil
+}
+
+// Stream works on a snapshot, so fn may call back into the repository.
+func (r *memoryMovieRepository) Stream(ctx context.Context, filter MovieFilter, fn func(models.Movie) error) error {
+	movies, _ := r.List(ctx, filter)
+	sort.SliceStable(movies, func(i, j int) bool { return movies[i].ID.Hex() < movies[j].ID.Hex() })
+	for _, m := range movies {
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryMovieRepository) FindByImdbID(ctx context.Context, imdbID string) (models.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, m := range r.movies {
+		if m.ImdbID == imdbID {
+			return cloneMovie(m), nil
+		}
+	}
+	return models.Movie{}, ErrNotFound
+}
+
+func (r *memoryMovieRepository) Create(ctx context.Context, movie models.Movie) (models.Movie, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, m := range r.movies {
+		if m.ImdbID == movie.ImdbID {
+			return models.Movie{}, ErrConflict
+		}
+	}
+	if movie.ID.IsZero() {
+		movie.ID = bson.NewObjectID()
+	}
+	r.movies = append(r.movies, cloneMovie(movie))
+	return movie, nil
+}
+
+func (r *memoryMovieRepository) UpdateReview(ctx context.Context, imdbID, review string, ranking models.Ranking) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.movies {
+		if r.movies[i].ImdbID == imdbID {
+			r.movies[i].AdminReview = review
+			r.movies[i].Ranking = ranking
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryMovieRepository) ListByGenreNames(ctx context.Context, genreNames []string, limit int) ([]models.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	movies := []models.Movie{}
+	for _, m := range r.movies {
+		if slices.ContainsFunc(m.Genre, func(g models.Genre) bool { return slices.Contains(genreNames, g.GenreName) }) {
+			movies = append(movies, cloneMovie(m))
+		}
+	}
+	sort.SliceStable(movies, func(i, j int) bool {
+		return movies[i].Ranking.RankingValue < movies[j].Ranking.RankingValue
+	})
+	if limit > 0 && len(movies) > limit {
+		movies = movies[:limit]
+	}
+	return movies, nil
+}
+
+func (r *memoryMovieRepository) Upsert(ctx context.Context, movie models.Movie) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.movies {
+		if r.movies[i].ImdbID == movie.ImdbID {
+			existing := r.movies[i]
+			movie.ID, movie.PosterPath, movie.EnrichedAt = existing.ID, existing.PosterPath, existing.EnrichedAt
+			if len(movie.Credits) == 0 {
+				movie.Credits = existing.Credits
+			}
+			r.movies[i] = cloneMovie(movie)
+			return false, nil
+		}
+	}
+	if movie.ID.IsZero() {
+		movie.ID = bson.NewObjectID()
+	}
+	r.movies = append(r.movies, cloneMovie(movie))
+	return true, nil
+}
+
+func (r *memoryMovieRepository) UpsertMany(ctx context.Context, movies []models.Movie) (int, error) {
+	created := 0
+	for _, m := range movies {
+		isNew, _ := r.Upsert(ctx, m)
+		if isNew {
+			created++
+		}
+	}
+	return created, nil
+}
+
+func (r *memoryMovieRepository) CountByGenre(ctx context.Context, genreId int) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var n int64
+	for _, m := range r.movies {
+		if slices.ContainsFunc(m.Genre, func(g models.Genre) bool { return g.GenreId == genreId }) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memoryMovieRepository) ReplaceGenre(ctx context.Context, fromIDs []int, to models.Genre) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for i := range r.movies {
+		if genres, changed := ReplaceGenres(r.movies[i].Genre, fromIDs, to); changed {
+			r.movies[i].Genre = genres
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memoryMovieRepository) ListByImdbIDs(ctx context.Context, imdbIDs []string) ([]models.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	movies := []models.Movie{}
+	for _, m := range r.movies {
+		if slices.Contains(imdbIDs, m.ImdbID) {
+			movies = append(movies, cloneMovie(m))
+		}
+	}
+	return movies, nil
+}
+
+func (r *memoryMovieRepository) ListByPerson(ctx context.Context, personId string) ([]models.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	movies := []models.Movie{}
+	for _, m := range r.movies {
+		if slices.ContainsFunc(m.Credits, func(c models.Credit) bool { return c.PersonID == personId }) {
+			movies = append(movies, cloneMovie(m))
+		}
+	}
+	return movies, nil
+}
+
+func (r *memoryMovieRepository) UpdateCredits(ctx context.Context, imdbID string, credits []models.Credit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.movies {
+		if r.movies[i].ImdbID == imdbID {
+			r.movies[i].Credits = slices.Clone(credits)
+			r.movies[i].Directors, r.movies[i].Cast = models.DirectorsAndCast(credits)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryMovieRepository) UpdateMetadata(ctx context.Context, imdbID string, movie models.Movie) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	movie = cloneMovie(movie)
+	for i := range r.movies {
+		if r.movies[i].ImdbID == imdbID {
+			m := &r.movies[i]
+			m.Title, m.PosterPath, m.YouTubeID, m.Genre = movie.Title, movie.PosterPath, movie.YouTubeID, movie.Genre
+			m.ReleaseDate, m.RuntimeMinutes, m.Synopsis = movie.ReleaseDate, movie.RuntimeMinutes, movie.Synopsis
+			m.OriginalLanguage, m.Certification = movie.OriginalLanguage, movie.Certification
+			m.Directors, m.Cast, m.EnrichedAt = movie.Directors, movie.Cast, movie.EnrichedAt
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryMovieRepository) ListStale(ctx context.Context, before time.Time, limit int) ([]models.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	movies := []models.Movie{}
+	for _, m := range r.movies {
+		if m.EnrichedAt == nil || m.EnrichedAt.Before(before) {
+			movies = append(movies, cloneMovie(m))
+		}
+	}
+	sort.SliceStable(movies, func(i, j int) bool {
+		a, b := movies[i].EnrichedAt, movies[j].EnrichedAt
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return a.Before(*b)
+	})
+	if limit > 0 && len(movies) > limit {
+		movies = movies[:limit]
+	}
+	return movies, nil
+}
+
+func (r *memoryMovieRepository) UpdatePoster(ctx context.Context, imdbID, posterPath string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.movies {
+		if r.movies[i].ImdbID == imdbID {
+			r.movies[i].PosterPath = posterPath
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func cloneMovie(m models.Movie) models.Movie {
+	m.Genre = slices.Clone(m.Genre)
+	m.Directors = slices.Clone(m.Directors)
+	m.Cast = slices.Clone(m.Cast)
+	m.Credits = slices.Clone(m.Credits)
+	if m.EnrichedAt != nil {
+		at := *m.EnrichedAt
+		m.EnrichedAt = &at
+	}
+	m.FillDefaults()
+	return m
+}
+
+// ---------- GENRES ----------
+
+type memoryGenreRepository struct {
+	mu     sync.RWMutex
+	genres []models.Genre
+}
+
+// NewMemoryGenreRepository returns an in-memory GenreRepository seeded with genres.
+func NewMemoryGenreRepository(genres ...models.Genre) GenreRepository {
+	return &memoryGenreRepository{genres: slices.Clone(genres)}
+}
+
+func (r *memoryGenreRepository) List(ctx context.Context) ([]models.Genre, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.genres), nil
+}
+
+func (r *memoryGenreRepository) FindByID(ctx context.Context, genreId int) (models.Genre, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, g := range r.genres {
+		if g.GenreId == genreId {
+			return g, nil
+		}
+	}
+	return models.Genre{}, ErrNotFound
+}
+
+func (r *memoryGenreRepository) Create(ctx context.Context, genre models.Genre) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, g := range r.genres {
+		if g.GenreId == genre.GenreId {
+			return ErrConflict
+		}
+	}
+	r.genres = append(r.genres, genre)
+	return nil
+}
+
+func (r *memoryGenreRepository) Rename(ctx context.Context, genreId int, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.genres {
+		if r.genres[i].GenreId == genreId {
+			r.genres[i].GenreName = name
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryGenreRepository) Delete(ctx context.Context, genreId int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.genres {
+		if r.genres[i].GenreId == genreId {
+			r.genres = slices.Delete(r.genres, i, i+1)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryGenreRepository) Upsert(ctx context.Context, genre models.Genre) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.genres {
+		if r.genres[i].GenreId == genre.GenreId {
+			r.genres[i].GenreName = genre.GenreName
+			return false, nil
+		}
+	}
+	r.genres = append(r.genres, genre)
+	return true, nil
+}
+
+// ---------- PEOPLE ----------
+
+type memoryPersonRepository struct {
+	mu     sync.RWMutex
+	people map[string]models.Person // keyed by person_id
+}
+
+// NewMemoryPersonRepository returns an empty in-memory PersonRepository.
+func NewMemoryPersonRepository() PersonRepository {
+	return &memoryPersonRepository{people: make(map[string]models.Person)}
+}
+
+func (r *memoryPersonRepository) Create(ctx context.Context, person models.Person) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.people[person.PersonID] = person
+	return nil
+}
+
+func (r *memoryPersonRepository) FindByID(ctx context.Context, personId string) (models.Person, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.people[personId]
+	if !ok {
+		return models.Person{}, ErrNotFound
+	}
+	return p, nil
+}
+
+func (r *memoryPersonRepository) FindByIDs(ctx context.Context, personIds []string) ([]models.Person, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	people := []models.Person{}
+	for _, id := range personIds {
+		if p, ok := r.people[id]; ok && !slices.ContainsFunc(people, func(q models.Person) bool { return q.PersonID == id }) {
+			people = append(people, p)
+		}
+	}
+	return people, nil
+}
+
+func (r *memoryPersonRepository) Search(ctx context.Context, query string, limit int) ([]models.Person, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query = strings.ToLower(query)
+	people := []models.Person{}
+	for _, p := range r.people {
+		if strings.Contains(strings.ToLower(p.Name), query) {
+			people = append(people, p)
+		}
+	}
+	sort.Slice(people, func(i, j int) bool {
+		if people[i].Name != people[j].Name {
+			return people[i].Name < people[j].Name
+		}
+		return people[i].PersonID < people[j].PersonID
+	})
+	if limit > 0 && len(people) > limit {
+		people = people[:limit]
+	}
+	return people, nil
+}
+
+func (r *memoryPersonRepository) Update(ctx context.Context, person models.Person) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.people[person.PersonID]
+	if !ok {
+		return ErrNotFound
+	}
+	p.Name = person.Name
+	p.BirthDate = person.BirthDate
+	p.Biography = person.Biography
+	p.UpdatedAt = time.Now()
+	r.people[person.PersonID] = p
+	return nil
+}
+
+func (r *memoryPersonRepository) Delete(ctx context.Context, personId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.people[personId]; !ok {
+		return ErrNotFound
+	}
+	delete(r.people, personId)
+	return nil
+}
+
+// ---------- COLLECTIONS ----------
+
+type memoryCollectionRepository struct {
+	mu          sync.RWMutex
+	collections map[string]models.Collection // keyed by collection_id
+}
+
+// NewMemoryCollectionRepository returns an empty in-memory CollectionRepository.
+func NewMemoryCollectionRepository() CollectionRepository {
+	return &memoryCollectionRepository{collections: make(map[string]models.Collection)}
+}
+
+func (r *memoryCollectionRepository) List(ctx context.Context) ([]models.Collection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collections := make([]models.Collection, 0, len(r.collections))
+	for _, c := range r.collections {
+		collections = append(collections, cloneCollection(c))
+	}
+	sort.Slice(collections, func(i, j int) bool {
+		if collections[i].Name != collections[j].Name {
+			return collections[i].Name < collections[j].Name
+		}
+		return collections[i].CollectionID < collections[j].CollectionID
+	})
+	return collections, nil
+}
+
+func (r *memoryCollectionRepository) FindByID(ctx context.Context, collectionId string) (models.Collection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.collections[collectionId]
+	if !ok {
+		return models.Collection{}, ErrNotFound
+	}
+	return cloneCollection(c), nil
+}
+
+func (r *memoryCollectionRepository) FindByMovie(ctx context.Context, imdbID string) (models.Collection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, c := range r.collections {
+		if slices.Contains(c.MovieIDs, imdbID) {
+			return cloneCollection(c), nil
+		}
+	}
+	return models.Collection{}, ErrNotFound
+}
+
+func (r *memoryCollectionRepository) Create(ctx context.Context, collection models.Collection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.claimed(collection) {
+		return ErrConflict
+	}
+	r.collections[collection.CollectionID] = cloneCollection(collection)
+	return nil
+}
+
+func (r *memoryCollectionRepository) Update(ctx context.Context, collection models.Collection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.collections[collection.CollectionID]
+	if !ok {
+		return ErrNotFound
+	}
+	if r.claimed(collection) {
+		return ErrConflict
+	}
+	c.Name = collection.Name
+	c.Overview = collection.Overview
+	c.MovieIDs = slices.Clone(collection.MovieIDs)
+	c.UpdatedAt = time.Now()
+	r.collections[collection.CollectionID] = c
+	return nil
+}
+
+// claimed reports whether another collection already contains one of collection's movies.
+func (r *memoryCollectionRepository) claimed(collection models.Collection) bool {
+	for id, c := range r.collections {
+		if id != collection.CollectionID && slices.ContainsFunc(c.MovieIDs, func(m string) bool { return slices.Contains(collection.MovieIDs, m) }) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryCollectionRepository) Delete(ctx context.Context, collectionId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.collections[collectionId]; !ok {
+		return ErrNotFound
+	}
+	delete(r.collections, collectionId)
+	return nil
+}
+
+func cloneCollection(c models.Collection) models.Collection {
+	c.MovieIDs = slices.Clone(c.MovieIDs)
+	if c.MovieIDs == nil {
+		c.MovieIDs = []string{}
+	}
+	return c
+}
+
+// ---------- USERS ----------
+
+type memoryUserRepository struct {
+	mu          sync.RWMutex
+	users       map[string]models.User // keyed by user_id
+	tokenHashes map[string]string      // user_id -> refresh_token_hash
+}
+
+// NewMemoryUserRepository returns an empty in-memory UserRepository.
+func NewMemoryUserRepository() UserRepository {
+	return &memoryUserRepository{
+		users:       make(map[string]models.User),
+		tokenHashes: make(map[string]string),
+	}
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.users[user.UserID] = cloneUser(user)
+	return nil
+}
+
+func (r *memoryUserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	_, err := r.FindByEmail(ctx, email)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *memoryUserRepository) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.users {
+		if u.Email == email {
+			return cloneUser(u), nil
+		}
+	}
+	return models.User{}, ErrNotFound
+}
+
+func (r *memoryUserRepository) FindByID(ctx context.Context, userId string) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[userId]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	return cloneUser(u), nil
+}
+
+func (r *memoryUserRepository) FavouriteGenreNames(ctx context.Context, userId string) ([]string, error) {
+	user, err := r.FindByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(user.FavouriteGenres))
+	for _, g := range user.FavouriteGenres {
+		names = append(names, g.GenreName)
+	}
+	return names, nil
+}
+
+func (r *memoryUserRepository) UpdatePassword(ctx context.Context, userId, passwordHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[userId]
+	if !ok {
+		return nil // matches UpdateOne semantics: no match is not an error
+	}
+	u.Password = passwordHash
+	u.UpdatedAt = time.Now()
+	r.users[userId] = u
+	return nil
+}
+
+func (r *memoryUserRepository) UpdateRole(ctx context.Context, userId, role string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[userId]
+	if !ok {
+		return ErrNotFound
+	}
+	u.Role = role
+	u.UpdatedAt = time.Now()
+	r.users[userId] = u
+	return nil
+}
+
+func (r *memoryUserRepository) CountByRole(ctx context.Context) (map[string]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := map[string]int64{}
+	for _, u := range r.users {
+		counts[u.Role]++
+	}
+	return counts, nil
+}
+
+func (r *memoryUserRepository) CountByFavouriteGenre(ctx context.Context, genreId int) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var n int64
+	for _, u := range r.users {
+		if slices.ContainsFunc(u.FavouriteGenres, func(g models.Genre) bool { return g.GenreId == genreId }) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memoryUserRepository) ReplaceFavouriteGenre(ctx context.Context, fromIDs []int, to models.Genre) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for userId, u := range r.users {
+		if genres, changed := ReplaceGenres(u.FavouriteGenres, fromIDs, to); changed {
+			u.FavouriteGenres = genres
+			u.UpdatedAt = time.Now()
+			r.users[userId] = u
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memoryUserRepository) SetRefreshTokenHash(ctx context.Context, userId, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[userId]
+	if !ok {
+		return nil
+	}
+	u.UpdatedAt = time.Now()
+	r.users[userId] = u
+	r.tokenHashes[userId] = tokenHash
+	return nil
+}
+
+func (r *memoryUserRepository) RefreshTokenHash(ctx context.Context, userId string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.users[userId]; !ok {
+		return "", ErrNotFound
+	}
+	return r.tokenHashes[userId], nil
+}
+
+func (r *memoryUserRepository) ClearRefreshTokenHash(ctx context.Context, userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if u, ok := r.users[userId]; ok {
+		u.UpdatedAt = time.Now()
+		r.users[userId] = u
+	}
+	delete(r.tokenHashes, userId)
+	return nil
+}
+
+func (r *memoryUserRepository) ClearAllRefreshTokenHashes(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := int64(len(r.tokenHashes))
+	for userId := range r.tokenHashes {
+		if u, ok := r.users[userId]; ok {
+			u.UpdatedAt = time.Now()
+			r.users[userId] = u
+		}
+	}
+	clear(r.tokenHashes)
+	return n, nil
+}
+
+func cloneUser(u models.User) models.User {
+	u.FavouriteGenres = slices.Clone(u.FavouriteGenres)
+	return u
+}
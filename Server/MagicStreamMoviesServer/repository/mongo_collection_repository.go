@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/database"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoCollectionRepository struct {
+	client *mongo.Client
+}
+
+// NewMongoCollectionRepository returns a CollectionRepository backed by the "collections" collection.
+func NewMongoCollectionRepository(client *mongo.Client) CollectionRepository {
+	return &mongoCollectionRepository{client: client}
+}
+
+func (r *mongoCollectionRepository) collection() *mongo.Collection {
+	return database.OpenCollection("collections", r.client)
+}
+
+func (r *mongoCollectionRepository) List(ctx context.Context) ([]models.Collection, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "collection_id", Value: 1}})
+	cursor, err := r.collection().Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	collections := []models.Collection{}
+	if err := cursor.All(ctx, &collections); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+func (r *mongoCollectionRepository) findOne(ctx context.Context, filter bson.M) (models.Collection, error) {
+	var collection models.Collection
+	err := r.collection().FindOne(ctx, filter).Decode(&collection)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return collection, ErrNotFound
+	}
+	return collection, err
+}
+
+func (r *mongoCollectionRepository) FindByID(ctx context.Context, collectionId string) (models.Collection, error) {
+	return r.findOne(ctx, bson.M{"collection_id": collectionId})
+}
+
+func (r *mongoCollectionRepository) FindByMovie(ctx context.Context, imdbID string) (models.Collection, error) {
+	return r.findOne(ctx, bson.M{"movie_ids": imdbID})
+}
+
+// Create and Update rely on the unique movie_ids index added by migration 6.
+func (r *mongoCollectionRepository) Create(ctx context.Context, collection models.Collection) error {
+	_, err := r.collection().InsertOne(ctx, collection)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (r *mongoCollectionRepository) Update(ctx context.Context, collection models.Collection) error {
+	update := bson.M{"$set": bson.M{
+		"name":       collection.Name,
+		"overview":   collection.Overview,
+		"movie_ids":  collection.MovieIDs,
+		"updated_at": time.Now(),
+	}}
+	result, err := r.collection().UpdateOne(ctx, bson.M{"collection_id": collection.CollectionID}, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoCollectionRepository) Delete(ctx context.Context, collectionId string) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"collection_id": collectionId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
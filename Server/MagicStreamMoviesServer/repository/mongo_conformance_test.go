@@ -0,0 +1,49 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/migrations"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository/repositorytest"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// TestMongoConformance runs the suite against the MongoDB at MONGODB_TEST_URI, in a
+// throwaway database per store that is dropped afterwards. It is skipped when unset.
+func TestMongoConformance(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	repositorytest.Run(t, func(t *testing.T, genres []models.Genre) repository.Store {
+		ctx := context.Background()
+		// The repositories resolve DATABASE_NAME on every call, so each store gets its own database.
+		name := fmt.Sprintf("conformance_%d", time.Now().UnixNano())
+		t.Setenv("DATABASE_NAME", name)
+		t.Cleanup(func() { client.Database(name).Drop(context.Background()) })
+
+		if _, err := migrations.New(client.Database(name)).Up(ctx); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+		store := repository.NewMongoStore(client)
+		for _, g := range genres {
+			if err := store.Genres.Create(ctx, g); err != nil {
+				t.Fatalf("seed genre: %v", err)
+			}
+		}
+		return store
+	})
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/database"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoGenreRepository struct {
+	client *mongo.Client
+}
+
+// NewMongoGenreRepository returns a GenreRepository backed by the "genres" collection.
+func NewMongoGenreRepository(client *mongo.Client) GenreRepository {
+	return &mongoGenreRepository{client: client}
+}
+
+func (r *mongoGenreRepository) collection() *mongo.Collection {
+	return database.OpenCollection("genres", r.client)
+}
+
+func (r *mongoGenreRepository) List(ctx context.Context) ([]models.Genre, error) {
+	cursor, err := r.collection().Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var genres []models.Genre
+	if err := cursor.All(ctx, &genres); err != nil {
+		return nil, err
+	}
+	return genres, nil
+}
+
+func (r *mongoGenreRepository) FindByID(ctx context.Context, genreId int) (models.Genre, error) {
+	var genre models.Genre
+	err := r.collection().FindOne(ctx, bson.M{"genre_id": genreId}).Decode(&genre)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return genre, ErrNotFound
+	}
+	return genre, err
+}
+
+// Create relies on the unique genre_id index added by migration 3.
+func (r *mongoGenreRepository) Create(ctx context.Context, genre models.Genre) error {
+	_, err := r.collection().InsertOne(ctx, genre)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (r *mongoGenreRepository) Rename(ctx context.Context, genreId int, name string) error {
+	result, err := r.collection().UpdateOne(ctx, bson.M{"genre_id": genreId}, bson.M{"$set": bson.M{"genre_name": name}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoGenreRepository) Delete(ctx context.Context, genreId int) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"genre_id": genreId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoGenreRepository) Upsert(ctx context.Context, genre models.Genre) (bool, error) {
+	result, err := r.collection().UpdateOne(ctx,
+		bson.M{"genre_id": genre.GenreId},
+		bson.M{"$set": bson.M{"genre_name": genre.GenreName}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// replaceGenreUpdate is the server-side form of ReplaceGenres for the embedded genre
+// array at field: an update pipeline that maps matching entries to to, then drops
+// entries whose genre_id was already seen, preserving order.
+func replaceGenreUpdate(field string, fromIDs []int, to models.Genre) mongo.Pipeline {
+	replacement := bson.D{{Key: "genre_id", Value: to.GenreId}, {Key: "genre_name", Value: to.GenreName}}
+	mapped := bson.M{"$map": bson.M{
+		"input": "$" + field,
+		"in": bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{"$$this.genre_id", fromIDs}},
+			bson.M{"$literal": replacement}, // a name starting with "$" must not be read as a field path
+			"$$this",
+		}},
+	}}
+	deduplicated := bson.M{"$reduce": bson.M{
+		"input":        mapped,
+		"initialValue": bson.A{},
+		"in": bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{"$$this.genre_id", "$$value.genre_id"}},
+			"$$value",
+			bson.M{"$concatArrays": bson.A{"$$value", bson.A{"$$this"}}},
+		}},
+	}}
+	return mongo.Pipeline{{{Key: "$set", Value: bson.M{field: deduplicated}}}}
+}
@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/database"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoMovieRepository struct {
+	client *mongo.Client
+}
+
+// NewMongoMovieRepository returns a MovieRepository backed by the "movies" collection.
+func NewMongoMovieRepository(client *mongo.Client) MovieRepository {
+	return &mongoMovieRepository{client: client}
+}
+
+func (r *mongoMovieRepository) collection() *mongo.Collection {
+	return database.OpenCollection("movies", r.client)
+}
+
+func (r *mongoMovieRepository) List(ctx context.Context, filter MovieFilter) ([]models.Movie, error) {
+	cursor, err := r.collection().Find(ctx, movieFilterQuery(filter))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var movies []models.Movie
+	if err := cursor.All(ctx, &movies); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+func (r *mongoMovieRepository) Stream(ctx context.Context, filter MovieFilter, fn func(models.Movie) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.collection().Find(ctx, movieFilterQuery(filter), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var movie models.Movie
+		if err := cursor.Decode(&movie); err != nil {
+			return err
+		}
+		if err := fn(movie); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (r *mongoMovieRepository) FindByImdbID(ctx context.Context, imdbID string) (models.Movie, error) {
+	var movie models.Movie
+	err := r.collection().FindOne(ctx, bson.M{"imdb_id": imdbID}).Decode(&movie)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return movie, ErrNotFound
+	}
+	return movie, err
+}
+
+func (r *mongoMovieRepository) Create(ctx context.Context, movie models.Movie) (models.Movie, error) {
+	if movie.ID.IsZero() {
+		movie.ID = bson.NewObjectID()
+	}
+	_, err := r.collection().InsertOne(ctx, movie)
+	if mongo.IsDuplicateKeyError(err) {
+		return models.Movie{}, ErrConflict
+	}
+	if err != nil {
+		return models.Movie{}, err
+	}
+	return movie, nil
+}
+
+func (r *mongoMovieRepository) UpdateReview(ctx context.Context, imdbID, review string, ranking models.Ranking) error {
+	filter := bson.M{"imdb_id": imdbID}
+	update := bson.M{
+		"$set": bson.M{
+			"admin_review": review,
+			"ranking": bson.M{
+				"ranking_value": ranking.RankingValue,
+				"ranking_name":  ranking.RankingName,
+			},
+		},
+	}
+	result, err := r.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoMovieRepository) ListByGenreNames(ctx context.Context, genreNames []string, limit int) ([]models.Movie, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "ranking.ranking_value", Value: 1}}).SetLimit(int64(limit))
+	filter := bson.M{"genre.genre_name": bson.M{"$in": genreNames}}
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var movies []models.Movie
+	if err := cursor.All(ctx, &movies); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+func (r *mongoMovieRepository) Upsert(ctx context.Context, movie models.Movie) (bool, error) {
+	result, err := r.collection().UpdateOne(ctx, bson.M{"imdb_id": movie.ImdbID}, upsertUpdate(movie),
+		options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// upsertUpdate sets the fields an import or seed carries and leaves poster_path,
+// enriched_at and, unless movie has some, credits to a movie that already exists.
+func upsertUpdate(movie models.Movie) bson.M {
+	movie.FillDefaults()
+	if movie.ID.IsZero() {
+		movie.ID = bson.NewObjectID()
+	}
+	set := bson.M{
+		"title":             movie.Title,
+		"youtube_id":        movie.YouTubeID,
+		"genre":             movie.Genre,
+		"admin_review":      movie.AdminReview,
+		"ranking":           movie.Ranking,
+		"release_date":      movie.ReleaseDate,
+		"runtime_minutes":   movie.RuntimeMinutes,
+		"synopsis":          movie.Synopsis,
+		"original_language": movie.OriginalLanguage,
+		"certification":     movie.Certification,
+		"directors":         movie.Directors,
+		"cast":              movie.Cast,
+	}
+	onInsert := bson.M{"_id": movie.ID, "poster_path": movie.PosterPath}
+	if len(movie.Credits) > 0 {
+		set["credits"] = movie.Credits
+	} else {
+		onInsert["credits"] = movie.Credits
+	}
+	if movie.EnrichedAt != nil {
+		onInsert["enriched_at"] = movie.EnrichedAt
+	}
+	return bson.M{"$set": set, "$setOnInsert": onInsert}
+}
+
+// UpsertMany applies the updates Upsert would as one ordered bulk write.
+func (r *mongoMovieRepository) UpsertMany(ctx context.Context, movies []models.Movie) (int, error) {
+	if len(movies) == 0 {
+		return 0, nil
+	}
+	writes := make([]mongo.WriteModel, 0, len(movies))
+	for _, movie := range movies {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"imdb_id": movie.ImdbID}).SetUpdate(upsertUpdate(movie)).SetUpsert(true))
+	}
+	result, err := r.collection().BulkWrite(ctx, writes)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.UpsertedCount), nil
+}
+
+func (r *mongoMovieRepository) CountByGenre(ctx context.Context, genreId int) (int64, error) {
+	return r.collection().CountDocuments(ctx, bson.M{"genre.genre_id": genreId})
+}
+
+func (r *mongoMovieRepository) ReplaceGenre(ctx context.Context, fromIDs []int, to models.Genre) (int64, error) {
+	filter := bson.M{"genre.genre_id": bson.M{"$in": fromIDs}}
+	result, err := r.collection().UpdateMany(ctx, filter, replaceGenreUpdate("genre", fromIDs, to))
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *mongoMovieRepository) ListByImdbIDs(ctx context.Context, imdbIDs []string) ([]models.Movie, error) {
+	cursor, err := r.collection().Find(ctx, bson.M{"imdb_id": bson.M{"$in": imdbIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	movies := []models.Movie{}
+	if err := cursor.All(ctx, &movies); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+func (r *mongoMovieRepository) ListByPerson(ctx context.Context, personId string) ([]models.Movie, error) {
+	cursor, err := r.collection().Find(ctx, bson.M{"credits.person_id": personId})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var movies []models.Movie
+	if err := cursor.All(ctx, &movies); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+func (r *mongoMovieRepository) UpdateCredits(ctx context.Context, imdbID string, credits []models.Credit) error {
+	directors, cast := models.DirectorsAndCast(credits)
+	update := bson.M{"$set": bson.M{"credits": credits, "directors": directors, "cast": cast}}
+	result, err := r.collection().UpdateOne(ctx, bson.M{"imdb_id": imdbID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoMovieRepository) UpdateMetadata(ctx context.Context, imdbID string, movie models.Movie) error {
+	movie.FillDefaults()
+	update := bson.M{"$set": bson.M{
+		"title":             movie.Title,
+		"poster_path":       movie.PosterPath,
+		"youtube_id":        movie.YouTubeID,
+		"genre":             movie.Genre,
+		"release_date":      movie.ReleaseDate,
+		"runtime_minutes":   movie.RuntimeMinutes,
+		"synopsis":          movie.Synopsis,
+		"original_language": movie.OriginalLanguage,
+		"certification":     movie.Certification,
+		"directors":         movie.Directors,
+		"cast":              movie.Cast,
+		"enriched_at":       movie.EnrichedAt,
+	}}
+	result, err := r.collection().UpdateOne(ctx, bson.M{"imdb_id": imdbID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListStale relies on missing and null enriched_at sorting before any date.
+func (r *mongoMovieRepository) ListStale(ctx context.Context, before time.Time, limit int) ([]models.Movie, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"enriched_at": nil},
+		bson.M{"enriched_at": bson.M{"$lt": before}},
+	}}
+	opts := options.Find().SetSort(bson.D{{Key: "enriched_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	movies := []models.Movie{}
+	if err := cursor.All(ctx, &movies); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+func (r *mongoMovieRepository) UpdatePoster(ctx context.Context, imdbID, posterPath string) error {
+	result, err := r.collection().UpdateOne(ctx, bson.M{"imdb_id": imdbID}, bson.M{"$set": bson.M{"poster_path": posterPath}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// movieFilterQuery translates filter into a find filter document.
+func movieFilterQuery(f MovieFilter) bson.M {
+	query := bson.M{}
+	if f.Genre != "" {
+		query["genre.genre_name"] = f.Genre
+	}
+	if from, until, ok := f.ReleaseDateRange(); ok {
+		query["release_date"] = bson.M{"$gte": from, "$lt": until}
+	}
+	if f.Language != "" {
+		query["original_language"] = f.Language
+	}
+	if f.Certification != "" {
+		query["certification"] = f.Certification
+	}
+	if f.Director != "" {
+		query["directors"] = f.Director
+	}
+	if f.CastMember != "" {
+		query["cast.name"] = f.CastMember
+	}
+	if minimum, maximum, ok := f.RuntimeRange(); ok {
+		query["runtime_minutes"] = bson.M{"$gte": minimum, "$lte": maximum}
+	}
+	return query
+}
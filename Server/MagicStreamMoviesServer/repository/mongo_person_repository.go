@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/database"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoPersonRepository struct {
+	client *mongo.Client
+}
+
+// NewMongoPersonRepository returns a PersonRepository backed by the "people" collection.
+func NewMongoPersonRepository(client *mongo.Client) PersonRepository {
+	return &mongoPersonRepository{client: client}
+}
+
+func (r *mongoPersonRepository) collection() *mongo.Collection {
+	return database.OpenCollection("people", r.client)
+}
+
+func (r *mongoPersonRepository) Create(ctx context.Context, person models.Person) error {
+	_, err := r.collection().InsertOne(ctx, person)
+	return err
+}
+
+func (r *mongoPersonRepository) FindByID(ctx context.Context, personId string) (models.Person, error) {
+	var person models.Person
+	err := r.collection().FindOne(ctx, bson.M{"person_id": personId}).Decode(&person)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return person, ErrNotFound
+	}
+	return person, err
+}
+
+func (r *mongoPersonRepository) FindByIDs(ctx context.Context, personIds []string) ([]models.Person, error) {
+	return r.find(ctx, bson.M{"person_id": bson.M{"$in": personIds}}, options.Find())
+}
+
+// Search matches with an escaped, case-insensitive regex. It cannot use the name
+// index, which is acceptable for an admin-sized people collection.
+func (r *mongoPersonRepository) Search(ctx context.Context, query string, limit int) ([]models.Person, error) {
+	filter := bson.M{}
+	if query != "" {
+		filter["name"] = bson.M{"$regex": regexp.QuoteMeta(query), "$options": "i"}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "person_id", Value: 1}}).SetLimit(int64(limit))
+	return r.find(ctx, filter, opts)
+}
+
+func (r *mongoPersonRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptionsBuilder) ([]models.Person, error) {
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	people := []models.Person{}
+	if err := cursor.All(ctx, &people); err != nil {
+		return nil, err
+	}
+	return people, nil
+}
+
+func (r *mongoPersonRepository) Update(ctx context.Context, person models.Person) error {
+	update := bson.M{"$set": bson.M{
+		"name":       person.Name,
+		"birth_date": person.BirthDate,
+		"biography":  person.Biography,
+		"updated_at": time.Now(),
+	}}
+	result, err := r.collection().UpdateOne(ctx, bson.M{"person_id": person.PersonID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoPersonRepository) Delete(ctx context.Context, personId string) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"person_id": personId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
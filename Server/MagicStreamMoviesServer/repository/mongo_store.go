@@ -0,0 +1,14 @@
+package repository
+
+import "go.mongodb.org/mongo-driver/v2/mongo"
+
+// NewMongoStore returns the MongoDB-backed repositories sharing one client.
+func NewMongoStore(client *mongo.Client) Store {
+	return Store{
+		Movies:      NewMongoMovieRepository(client),
+		Users:       NewMongoUserRepository(client),
+		Genres:      NewMongoGenreRepository(client),
+		People:      NewMongoPersonRepository(client),
+		Collections: NewMongoCollectionRepository(client),
+	}
+}
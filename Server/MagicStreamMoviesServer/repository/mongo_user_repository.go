@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/database"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoUserRepository struct {
+	client *mongo.Client
+}
+
+// NewMongoUserRepository returns a UserRepository backed by the "users" collection.
+func NewMongoUserRepository(client *mongo.Client) UserRepository {
+	return &mongoUserRepository{client: client}
+}
+
+func (r *mongoUserRepository) collection() *mongo.Collection {
+	return database.OpenCollection("users", r.client)
+}
+
+func (r *mongoUserRepository) Create(ctx context.Context, user models.User) error {
+	_, err := r.collection().InsertOne(ctx, user)
+	return err
+}
+
+func (r *mongoUserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	count, err := r.collection().CountDocuments(ctx, bson.D{{Key: "email", Value: email}})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *mongoUserRepository) findOne(ctx context.Context, filter bson.M) (models.User, error) {
+	var user models.User
+	err := r.collection().FindOne(ctx, filter).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user, ErrNotFound
+	}
+	return user, err
+}
+
+func (r *mongoUserRepository) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	return r.findOne(ctx, bson.M{"email": email})
+}
+
+func (r *mongoUserRepository) FindByID(ctx context.Context, userId string) (models.User, error) {
+	return r.findOne(ctx, bson.M{"user_id": userId})
+}
+
+func (r *mongoUserRepository) FavouriteGenreNames(ctx context.Context, userId string) ([]string, error) {
+	var user struct {
+		FavouriteGenres []models.Genre `bson:"favourite_genres" json:"favourite_genres"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"favourite_genres.genre_name": 1})
+	err := r.collection().FindOne(ctx, bson.M{"user_id": userId}, opts).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(user.FavouriteGenres))
+	for _, g := range user.FavouriteGenres {
+		names = append(names, g.GenreName)
+	}
+	return names, nil
+}
+
+func (r *mongoUserRepository) UpdatePassword(ctx context.Context, userId, passwordHash string) error {
+	update := bson.M{"$set": bson.M{"password": passwordHash, "updated_at": time.Now()}}
+	_, err := r.collection().UpdateOne(ctx, bson.M{"user_id": userId}, update)
+	return err
+}
+
+func (r *mongoUserRepository) SetRefreshTokenHash(ctx context.Context, userId, tokenHash string) error {
+	updateData := bson.M{
+		"$set": bson.M{
+			"refresh_token_hash": tokenHash,
+			"updated_at":         time.Now(),
+		},
+	}
+	_, err := r.collection().UpdateOne(ctx, bson.M{"user_id": userId}, updateData)
+	return err
+}
+
+func (r *mongoUserRepository) RefreshTokenHash(ctx context.Context, userId string) (string, error) {
+	var user bson.M
+	err := r.collection().FindOne(ctx, bson.M{"user_id": userId}).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	storedHash, _ := user["refresh_token_hash"].(string)
+	return storedHash, nil
+}
+
+func (r *mongoUserRepository) ClearRefreshTokenHash(ctx context.Context, userId string) error {
+	updateData := bson.M{
+		"$unset": bson.M{
+			"refresh_token_hash": "",
+		},
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+	}
+	_, err := r.collection().UpdateOne(ctx, bson.M{"user_id": userId}, updateData)
+	return err
+}
+
+func (r *mongoUserRepository) UpdateRole(ctx context.Context, userId, role string) error {
+	update := bson.M{"$set": bson.M{"role": role, "updated_at": time.Now()}}
+	result, err := r.collection().UpdateOne(ctx, bson.M{"user_id": userId}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoUserRepository) CountByRole(ctx context.Context) (map[string]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$role", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.collection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Role  string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(groups))
+	for _, g := range groups {
+		counts[g.Role] = g.Count
+	}
+	return counts, nil
+}
+
+func (r *mongoUserRepository) CountByFavouriteGenre(ctx context.Context, genreId int) (int64, error) {
+	return r.collection().CountDocuments(ctx, bson.M{"favourite_genres.genre_id": genreId})
+}
+
+func (r *mongoUserRepository) ReplaceFavouriteGenre(ctx context.Context, fromIDs []int, to models.Genre) (int64, error) {
+	filter := bson.M{"favourite_genres.genre_id": bson.M{"$in": fromIDs}}
+	update := append(replaceGenreUpdate("favourite_genres", fromIDs, to),
+		bson.D{{Key: "$set", Value: bson.M{"updated_at": time.Now()}}})
+	result, err := r.collection().UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *mongoUserRepository) ClearAllRefreshTokenHashes(ctx context.Context) (int64, error) {
+	updateData := bson.M{
+		"$unset": bson.M{"refresh_token_hash": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+	result, err := r.collection().UpdateMany(ctx, bson.M{"refresh_token_hash": bson.M{"$exists": true}}, updateData)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+)
+
+// MovieFilter narrows MovieRepository.List. Zero fields do not filter; string
+// fields match exactly. Movies whose metadata is unknown (zero) never match a
+// filter on that field.
+type MovieFilter struct {
+	Genre string // genre_name
+	// YearFrom and YearTo bound the release year, inclusive.
+	YearFrom int
+	YearTo   int
+	// Language is an ISO 639-1 code, compared with original_language.
+	Language      string
+	Certification string
+	Director      string
+	CastMember    string // cast[].name
+	// RuntimeMin and RuntimeMax bound runtime_minutes, inclusive.
+	RuntimeMin int
+	RuntimeMax int
+}
+
+// ReleaseDateRange returns the release_date bounds for the year filter as
+// [from, until) "YYYY-MM-DD" strings. Dates sort as strings, and from is never
+// empty, so movies without a release date are excluded.
+func (f MovieFilter) ReleaseDateRange() (from, until string, ok bool) {
+	if f.YearFrom == 0 && f.YearTo == 0 {
+		return "", "", false
+	}
+	from = fmt.Sprintf("%04d-01-01", max(f.YearFrom, 1))
+	until = "9999-12-31"
+	if f.YearTo > 0 {
+		until = fmt.Sprintf("%04d-01-01", f.YearTo+1)
+	}
+	return from, until, true
+}
+
+// RuntimeRange returns the inclusive runtime_minutes bounds, excluding unknown (0) runtimes.
+func (f MovieFilter) RuntimeRange() (minimum, maximum int, ok bool) {
+	if f.RuntimeMin == 0 && f.RuntimeMax == 0 {
+		return 0, 0, false
+	}
+	maximum = f.RuntimeMax
+	if maximum == 0 {
+		maximum = int(^uint(0) >> 1)
+	}
+	return max(f.RuntimeMin, 1), maximum, true
+}
+
+// Matches reports whether m passes the filter. Backends that cannot push the
+// filter into a query use it.
+func (f MovieFilter) Matches(m models.Movie) bool {
+	if f.Genre != "" && !slices.ContainsFunc(m.Genre, func(g models.Genre) bool { return g.GenreName == f.Genre }) {
+		return false
+	}
+	if from, until, ok := f.ReleaseDateRange(); ok && (m.ReleaseDate < from || m.ReleaseDate >= until) {
+		return false
+	}
+	if f.Language != "" && m.OriginalLanguage != f.Language {
+		return false
+	}
+	if f.Certification != "" && m.Certification != f.Certification {
+		return false
+	}
+	if f.Director != "" && !slices.Contains(m.Directors, f.Director) {
+		return false
+	}
+	if f.CastMember != "" && !slices.ContainsFunc(m.Cast, func(c models.CastMember) bool { return c.Name == f.CastMember }) {
+		return false
+	}
+	if minimum, maximum, ok := f.RuntimeRange(); ok && (m.RuntimeMinutes < minimum || m.RuntimeMinutes > maximum) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+)
+
+// UpdatePerson saves person and rewrites the name copied into the credits (and
+// the Directors and Cast derived from them) of every movie crediting them. The
+// person is saved first, so if a later step fails, repeating the update completes
+// it. It returns how many movies were rewritten.
+func UpdatePerson(ctx context.Context, store Store, person models.Person) (int64, error) {
+	if err := store.People.Update(ctx, person); err != nil {
+		return 0, err
+	}
+
+	movies, err := store.Movies.ListByPerson(ctx, person.PersonID)
+	if err != nil {
+		return 0, err
+	}
+
+	var updated int64
+	for _, movie := range movies {
+		changed := false
+		for i := range movie.Credits {
+			if movie.Credits[i].PersonID == person.PersonID && movie.Credits[i].Name != person.Name {
+				movie.Credits[i].Name = person.Name
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := store.Movies.UpdateCredits(ctx, movie.ImdbID, movie.Credits); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
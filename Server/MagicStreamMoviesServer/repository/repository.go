@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+)
+
+// ErrNotFound is returned when the requested document does not exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is returned when a create would duplicate an existing key.
+var ErrConflict = errors.New("already exists")
+
+// MovieRepository persists movies.
+type MovieRepository interface {
+	// List returns the movies matching filter; the zero MovieFilter returns the whole catalogue.
+	List(ctx context.Context, filter MovieFilter) ([]models.Movie, error)
+	// Stream calls fn for each movie matching filter, in ID order, without loading the
+	// whole result into memory. It stops at, and returns, the first error from fn.
+	Stream(ctx context.Context, filter MovieFilter, fn func(models.Movie) error) error
+	// FindByImdbID returns ErrNotFound when no movie has the given imdb_id.
+	FindByImdbID(ctx context.Context, imdbID string) (models.Movie, error)
+	// Create stores a new movie, assigning an ID if it has none, and returns it.
+	// It returns ErrConflict when the imdb_id is already taken.
+	Create(ctx context.Context, movie models.Movie) (models.Movie, error)
+	// UpdateReview sets admin_review and ranking; returns ErrNotFound when nothing matched.
+	UpdateReview(ctx context.Context, imdbID, review string, ranking models.Ranking) error
+	// ListByGenreNames returns up to limit movies having any of the genre names,
+	// ordered by ranking_value ascending.
+	ListByGenreNames(ctx context.Context, genreNames []string, limit int) ([]models.Movie, error)
+	// Upsert inserts the movie or updates the one with the same imdb_id from it. An
+	// existing movie keeps its ID, poster_path and enriched_at, and its credits unless
+	// movie has some, since posters, enrichment and credits are set separately.
+	// It reports whether a new movie was created.
+	Upsert(ctx context.Context, movie models.Movie) (bool, error)
+	// UpsertMany upserts each movie as Upsert does, atomically where the backend allows,
+	// and returns how many were created. imdb_ids must be unique within movies.
+	UpsertMany(ctx context.Context, movies []models.Movie) (int, error)
+	// CountByGenre returns how many movies embed the genre with genreId.
+	CountByGenre(ctx context.Context, genreId int) (int64, error)
+	// ReplaceGenre rewrites every embedded genre whose genre_id is in fromIDs to to,
+	// as ReplaceGenres does, and returns how many movies were changed.
+	ReplaceGenre(ctx context.Context, fromIDs []int, to models.Genre) (int64, error)
+	// ListByImdbIDs returns the movies that exist among imdbIDs, in no particular order.
+	ListByImdbIDs(ctx context.Context, imdbIDs []string) ([]models.Movie, error)
+	// ListByPerson returns the movies crediting personId, in no particular order.
+	ListByPerson(ctx context.Context, personId string) ([]models.Movie, error)
+	// UpdateCredits replaces a movie's credits, which must be sorted by billing order,
+	// and sets Directors and Cast from models.DirectorsAndCast. Returns ErrNotFound
+	// when no movie has the imdb_id.
+	UpdateCredits(ctx context.Context, imdbID string, credits []models.Credit) error
+	// UpdateMetadata replaces the externally sourced fields of the movie with the
+	// imdb_id — title, poster_path, youtube_id, genre, the descriptive metadata,
+	// directors, cast and enriched_at — leaving admin_review, ranking and credits
+	// alone. Returns ErrNotFound when no movie has the imdb_id.
+	UpdateMetadata(ctx context.Context, imdbID string, movie models.Movie) error
+	// ListStale returns up to limit movies never enriched or last enriched before
+	// before, never-enriched first and then oldest first.
+	ListStale(ctx context.Context, before time.Time, limit int) ([]models.Movie, error)
+	// UpdatePoster sets poster_path; returns ErrNotFound when nothing matched.
+	UpdatePoster(ctx context.Context, imdbID, posterPath string) error
+}
+
+// GenreRepository persists genres.
+type GenreRepository interface {
+	List(ctx context.Context) ([]models.Genre, error)
+	// FindByID returns ErrNotFound when no genre has the given genre_id.
+	FindByID(ctx context.Context, genreId int) (models.Genre, error)
+	// Create returns ErrConflict when the genre_id is already taken.
+	Create(ctx context.Context, genre models.Genre) error
+	// Rename and Delete return ErrNotFound when no genre has the given genre_id.
+	// Neither touches the copies embedded in movies and users; see RenameGenre and MergeGenres.
+	Rename(ctx context.Context, genreId int, name string) error
+	Delete(ctx context.Context, genreId int) error
+	// Upsert inserts the genre or renames the one with the same genre_id.
+	// It reports whether a new genre was created.
+	Upsert(ctx context.Context, genre models.Genre) (bool, error)
+}
+
+// UserRepository persists users and their refresh token hashes.
+type UserRepository interface {
+	Create(ctx context.Context, user models.User) error
+	EmailExists(ctx context.Context, email string) (bool, error)
+	// FindByEmail and FindByID return ErrNotFound when the user does not exist.
+	FindByEmail(ctx context.Context, email string) (models.User, error)
+	FindByID(ctx context.Context, userId string) (models.User, error)
+	FavouriteGenreNames(ctx context.Context, userId string) ([]string, error)
+	UpdatePassword(ctx context.Context, userId, passwordHash string) error
+	// UpdateRole returns ErrNotFound when the user does not exist.
+	UpdateRole(ctx context.Context, userId, role string) error
+	// CountByRole returns the number of users per role.
+	CountByRole(ctx context.Context) (map[string]int64, error)
+	// CountByFavouriteGenre returns how many users have the genre with genreId as a favourite.
+	CountByFavouriteGenre(ctx context.Context, genreId int) (int64, error)
+	// ReplaceFavouriteGenre is ReplaceGenre for favourite_genres; it returns how many users were changed.
+	ReplaceFavouriteGenre(ctx context.Context, fromIDs []int, to models.Genre) (int64, error)
+
+	// SetRefreshTokenHash replaces the stored refresh token hash (token rotation).
+	SetRefreshTokenHash(ctx context.Context, userId, tokenHash string) error
+	// RefreshTokenHash returns the stored hash, or "" if the session was revoked.
+	RefreshTokenHash(ctx context.Context, userId string) (string, error)
+	ClearRefreshTokenHash(ctx context.Context, userId string) error
+	// ClearAllRefreshTokenHashes revokes every session and returns how many were revoked.
+	ClearAllRefreshTokenHashes(ctx context.Context) (int64, error)
+}
+
+// PersonRepository persists people credited on movies.
+type PersonRepository interface {
+	// Create stores a new person; PersonID must already be set.
+	Create(ctx context.Context, person models.Person) error
+	// FindByID, Update and Delete return ErrNotFound when the person does not exist.
+	FindByID(ctx context.Context, personId string) (models.Person, error)
+	// FindByIDs returns the people that exist among personIds, in no particular order.
+	FindByIDs(ctx context.Context, personIds []string) ([]models.Person, error)
+	// Search returns up to limit people whose name contains query, case-insensitively,
+	// ordered by name. An empty query matches everyone.
+	Search(ctx context.Context, query string, limit int) ([]models.Person, error)
+	// Update replaces name, birth_date and biography and sets updated_at. It does not
+	// touch the names copied into movie credits; see RenamePerson.
+	Update(ctx context.Context, person models.Person) error
+	Delete(ctx context.Context, personId string) error
+}
+
+// CollectionRepository persists movie collections and their ordered membership.
+type CollectionRepository interface {
+	// List returns every collection ordered by name.
+	List(ctx context.Context) ([]models.Collection, error)
+	// FindByID, Update and Delete return ErrNotFound when the collection does not exist.
+	FindByID(ctx context.Context, collectionId string) (models.Collection, error)
+	// FindByMovie returns the collection containing imdbID, or ErrNotFound.
+	FindByMovie(ctx context.Context, imdbID string) (models.Collection, error)
+	// Create stores a new collection; CollectionID must already be set. Create and
+	// Update return ErrConflict when a movie already belongs to another collection.
+	Create(ctx context.Context, collection models.Collection) error
+	// Update replaces name, overview and movie_ids and sets updated_at.
+	Update(ctx context.Context, collection models.Collection) error
+	Delete(ctx context.Context, collectionId string) error
+}
+
+// Store groups the repositories a backend provides.
+type Store struct {
+	Movies      MovieRepository
+	Users       UserRepository
+	Genres      GenreRepository
+	People      PersonRepository
+	Collections CollectionRepository
+}
@@ -0,0 +1,1100 @@
+// Package repositorytest holds the conformance suite every repository backend must pass.
+//
+// A backend wires it up from its own test file:
+//
+//	func TestConformance(t *testing.T) {
+//		repositorytest.Run(t, func(t *testing.T, genres []models.Genre) repository.Store {
+//			return repository.NewMemoryStore(genres...)
+//		})
+//	}
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Factory returns a fresh, empty store whose genre repository is seeded with genres.
+type Factory func(t *testing.T, genres []models.Genre) repository.Store
+
+var (
+	action = models.Genre{GenreId: 1, GenreName: "Action"}
+	comedy = models.Genre{GenreId: 2, GenreName: "Comedy"}
+	drama  = models.Genre{GenreId: 3, GenreName: "Drama"}
+)
+
+// Run executes the whole suite against the backend built by newStore.
+func Run(t *testing.T, newStore Factory) {
+	t.Run("Genres", func(t *testing.T) { testGenres(t, newStore) })
+	t.Run("Movies", func(t *testing.T) { testMovies(t, newStore) })
+	t.Run("RecommendedMovies", func(t *testing.T) { testListByGenreNames(t, newStore) })
+	t.Run("Users", func(t *testing.T) { testUsers(t, newStore) })
+	t.Run("RefreshTokenHash", func(t *testing.T) { testRefreshTokenHash(t, newStore) })
+	t.Run("Upsert", func(t *testing.T) { testUpsert(t, newStore) })
+	t.Run("UpsertMany", func(t *testing.T) { testUpsertMany(t, newStore) })
+	t.Run("Stream", func(t *testing.T) { testStream(t, newStore) })
+	t.Run("Roles", func(t *testing.T) { testRoles(t, newStore) })
+	t.Run("GenreAdmin", func(t *testing.T) { testGenreAdmin(t, newStore) })
+	t.Run("GenreCascade", func(t *testing.T) { testGenreCascade(t, newStore) })
+	t.Run("MovieMetadata", func(t *testing.T) { testMovieMetadata(t, newStore) })
+	t.Run("MovieFilter", func(t *testing.T) { testMovieFilter(t, newStore) })
+	t.Run("People", func(t *testing.T) { testPeople(t, newStore) })
+	t.Run("Credits", func(t *testing.T) { testCredits(t, newStore) })
+	t.Run("Collections", func(t *testing.T) { testCollections(t, newStore) })
+	t.Run("Enrichment", func(t *testing.T) { testEnrichment(t, newStore) })
+}
+
+func sampleMovie(imdbID string, rank int, genres ...models.Genre) models.Movie {
+	return models.Movie{
+		ImdbID:      imdbID,
+		Title:       "Movie " + imdbID,
+		PosterPath:  "https://example.com/" + imdbID + ".jpg",
+		YouTubeID:   "yt-" + imdbID,
+		Genre:       genres,
+		AdminReview: "review " + imdbID,
+		Ranking:     models.Ranking{RankingValue: rank, RankingName: "Rank"},
+	}
+}
+
+func sampleUser(email string, genres ...models.Genre) models.User {
+	oid := bson.NewObjectID()
+	now := time.Now().UTC().Truncate(time.Second)
+	return models.User{
+		ID:              oid,
+		UserID:          oid.Hex(),
+		FirstName:       "Ada",
+		LastName:        "Lovelace",
+		Email:           email,
+		Password:        "$2a$12$hash",
+		Role:            models.RoleUser,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		FavouriteGenres: genres,
+	}
+}
+
+func testGenres(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, []models.Genre{action, comedy})
+
+	genres, err := store.Genres.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(genres) != 2 {
+		t.Fatalf("List returned %d genres, want 2", len(genres))
+	}
+	for _, want := range []models.Genre{action, comedy} {
+		found := false
+		for _, g := range genres {
+			found = found || g == want
+		}
+		if !found {
+			t.Errorf("List is missing %+v", want)
+		}
+	}
+}
+
+func testMovies(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, nil)
+
+	if _, err := store.Movies.FindByImdbID(ctx, "tt0000000"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("FindByImdbID on empty store: got %v, want ErrNotFound", err)
+	}
+
+	created, err := store.Movies.Create(ctx, sampleMovie("tt0000001", 5, action, drama))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ID.IsZero() {
+		t.Fatal("Create did not assign an ID")
+	}
+
+	got, err := store.Movies.FindByImdbID(ctx, "tt0000001")
+	if err != nil {
+		t.Fatalf("FindByImdbID: %v", err)
+	}
+	if got.ID != created.ID || got.Title != created.Title || got.PosterPath != created.PosterPath ||
+		got.YouTubeID != created.YouTubeID || got.AdminReview != created.AdminReview || got.Ranking != created.Ranking {
+		t.Errorf("FindByImdbID = %+v, want %+v", got, created)
+	}
+	if len(got.Genre) != 2 || got.Genre[0] != action || got.Genre[1] != drama {
+		t.Errorf("genres not round-tripped in order: %+v", got.Genre)
+	}
+	if _, err := store.Movies.Create(ctx, sampleMovie("tt0000001", 1, comedy)); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("Create with a taken imdb_id: got %v, want ErrConflict", err)
+	}
+
+	if _, err := store.Movies.Create(ctx, sampleMovie("tt0000002", 1, comedy)); err != nil {
+		t.Fatalf("Create second movie: %v", err)
+	}
+	movies, err := store.Movies.List(ctx, repository.MovieFilter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(movies) != 2 {
+		t.Fatalf("List returned %d movies, want 2", len(movies))
+	}
+
+	ranking := models.Ranking{RankingValue: 1, RankingName: "Excellent"}
+	if err := store.Movies.UpdateReview(ctx, "tt0000001", "updated", ranking); err != nil {
+		t.Fatalf("UpdateReview: %v", err)
+	}
+	got, _ = store.Movies.FindByImdbID(ctx, "tt0000001")
+	if got.AdminReview != "updated" || got.Ranking != ranking {
+		t.Errorf("UpdateReview not persisted: %+v", got)
+	}
+	if err := store.Movies.UpdateReview(ctx, "tt9999999", "x", ranking); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("UpdateReview on missing movie: got %v, want ErrNotFound", err)
+	}
+
+	if err := store.Movies.UpdatePoster(ctx, "tt0000001", "https://cdn.example.com/p.jpg"); err != nil {
+		t.Fatalf("UpdatePoster: %v", err)
+	}
+	got, _ = store.Movies.FindByImdbID(ctx, "tt0000001")
+	if got.PosterPath != "https://cdn.example.com/p.jpg" || got.AdminReview != "updated" {
+		t.Errorf("UpdatePoster not persisted: %+v", got)
+	}
+	if err := store.Movies.UpdatePoster(ctx, "tt9999999", "https://cdn.example.com/p.jpg"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("UpdatePoster on missing movie: got %v, want ErrNotFound", err)
+	}
+}
+
+func testListByGenreNames(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, nil)
+
+	for _, m := range []models.Movie{
+		sampleMovie("tt1", 3, action),
+		sampleMovie("tt2", 1, comedy, action),
+		sampleMovie("tt3", 2, drama),
+		sampleMovie("tt4", 4, comedy),
+	} {
+		if _, err := store.Movies.Create(ctx, m); err != nil {
+			t.Fatalf("Create %s: %v", m.ImdbID, err)
+		}
+	}
+
+	movies, err := store.Movies.ListByGenreNames(ctx, []string{"Action", "Comedy"}, 10)
+	if err != nil {
+		t.Fatalf("ListByGenreNames: %v", err)
+	}
+	want := []string{"tt2", "tt1", "tt4"}
+	if len(movies) != len(want) {
+		t.Fatalf("ListByGenreNames returned %d movies, want %d", len(movies), len(want))
+	}
+	for i, id := range want {
+		if movies[i].ImdbID != id {
+			t.Errorf("position %d = %s, want %s (sorted by ranking_value)", i, movies[i].ImdbID, id)
+		}
+	}
+
+	limited, err := store.Movies.ListByGenreNames(ctx, []string{"Action", "Comedy"}, 2)
+	if err != nil {
+		t.Fatalf("ListByGenreNames with limit: %v", err)
+	}
+	if len(limited) != 2 {
+		t.Errorf("limit ignored: got %d movies", len(limited))
+	}
+
+	none, err := store.Movies.ListByGenreNames(ctx, []string{"Horror"}, 10)
+	if err != nil {
+		t.Fatalf("ListByGenreNames unknown genre: %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("unknown genre matched %d movies", len(none))
+	}
+}
+
+func testUsers(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, nil)
+
+	user := sampleUser("ada@example.com", action, comedy)
+
+	exists, err := store.Users.EmailExists(ctx, user.Email)
+	if err != nil || exists {
+		t.Fatalf("EmailExists before Create = %v, %v", exists, err)
+	}
+	if _, err := store.Users.FindByEmail(ctx, user.Email); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("FindByEmail before Create: got %v, want ErrNotFound", err)
+	}
+
+	if err := store.Users.Create(ctx, user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if exists, err := store.Users.EmailExists(ctx, user.Email); err != nil || !exists {
+		t.Fatalf("EmailExists after Create = %v, %v", exists, err)
+	}
+
+	byEmail, err := store.Users.FindByEmail(ctx, user.Email)
+	if err != nil {
+		t.Fatalf("FindByEmail: %v", err)
+	}
+	byID, err := store.Users.FindByID(ctx, user.UserID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	for _, got := range []models.User{byEmail, byID} {
+		if got.UserID != user.UserID || got.Email != user.Email || got.FirstName != user.FirstName ||
+			got.LastName != user.LastName || got.Password != user.Password || got.Role != user.Role {
+			t.Errorf("user not round-tripped: %+v", got)
+		}
+		if !got.CreatedAt.Equal(user.CreatedAt) {
+			t.Errorf("created_at = %v, want %v", got.CreatedAt, user.CreatedAt)
+		}
+		if len(got.FavouriteGenres) != 2 || got.FavouriteGenres[0] != action || got.FavouriteGenres[1] != comedy {
+			t.Errorf("favourite genres not round-tripped: %+v", got.FavouriteGenres)
+		}
+	}
+
+	names, err := store.Users.FavouriteGenreNames(ctx, user.UserID)
+	if err != nil {
+		t.Fatalf("FavouriteGenreNames: %v", err)
+	}
+	if len(names) != 2 || names[0] != "Action" || names[1] != "Comedy" {
+		t.Errorf("FavouriteGenreNames = %v", names)
+	}
+	if _, err := store.Users.FavouriteGenreNames(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("FavouriteGenreNames on missing user: got %v, want ErrNotFound", err)
+	}
+
+	if err := store.Users.UpdatePassword(ctx, user.UserID, "$argon2id$new"); err != nil {
+		t.Fatalf("UpdatePassword: %v", err)
+	}
+	got, _ := store.Users.FindByID(ctx, user.UserID)
+	if got.Password != "$argon2id$new" {
+		t.Errorf("UpdatePassword not persisted: %q", got.Password)
+	}
+}
+
+func testRefreshTokenHash(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, nil)
+
+	if _, err := store.Users.RefreshTokenHash(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("RefreshTokenHash on missing user: got %v, want ErrNotFound", err)
+	}
+
+	user := sampleUser("grace@example.com", drama)
+	if err := store.Users.Create(ctx, user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if hash, err := store.Users.RefreshTokenHash(ctx, user.UserID); err != nil || hash != "" {
+		t.Fatalf("RefreshTokenHash before login = %q, %v; want empty", hash, err)
+	}
+
+	if err := store.Users.SetRefreshTokenHash(ctx, user.UserID, "first"); err != nil {
+		t.Fatalf("SetRefreshTokenHash: %v", err)
+	}
+	if err := store.Users.SetRefreshTokenHash(ctx, user.UserID, "second"); err != nil {
+		t.Fatalf("SetRefreshTokenHash (rotation): %v", err)
+	}
+	if hash, err := store.Users.RefreshTokenHash(ctx, user.UserID); err != nil || hash != "second" {
+		t.Fatalf("RefreshTokenHash after rotation = %q, %v; want %q", hash, err, "second")
+	}
+
+	if err := store.Users.ClearRefreshTokenHash(ctx, user.UserID); err != nil {
+		t.Fatalf("ClearRefreshTokenHash: %v", err)
+	}
+	if hash, err := store.Users.RefreshTokenHash(ctx, user.UserID); err != nil || hash != "" {
+		t.Fatalf("RefreshTokenHash after revoke = %q, %v; want empty", hash, err)
+	}
+}
+
+func testUpsert(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, []models.Genre{action})
+
+	created, err := store.Genres.Upsert(ctx, comedy)
+	if err != nil || !created {
+		t.Fatalf("Genres.Upsert new = %v, %v; want created", created, err)
+	}
+	created, err = store.Genres.Upsert(ctx, models.Genre{GenreId: action.GenreId, GenreName: "Action & Adventure"})
+	if err != nil || created {
+		t.Fatalf("Genres.Upsert existing = %v, %v; want updated", created, err)
+	}
+	genres, _ := store.Genres.List(ctx)
+	if len(genres) != 2 {
+		t.Fatalf("List after Upsert returned %d genres, want 2", len(genres))
+	}
+	for _, g := range genres {
+		if g.GenreId == action.GenreId && g.GenreName != "Action & Adventure" {
+			t.Errorf("genre not renamed: %+v", g)
+		}
+	}
+
+	movie := sampleMovie("tt7", 5, action)
+	created, err = store.Movies.Upsert(ctx, movie)
+	if err != nil || !created {
+		t.Fatalf("Movies.Upsert new = %v, %v; want created", created, err)
+	}
+	first, _ := store.Movies.FindByImdbID(ctx, "tt7")
+
+	// Enrichment, credits and an uploaded poster must survive a re-import.
+	now := time.Now().UTC().Truncate(time.Second)
+	first.EnrichedAt = &now
+	if err := store.Movies.UpdateMetadata(ctx, "tt7", first); err != nil {
+		t.Fatalf("UpdateMetadata: %v", err)
+	}
+	director := samplePerson("Dana Director")
+	if err := store.People.Create(ctx, director); err != nil {
+		t.Fatalf("Create person: %v", err)
+	}
+	credits := []models.Credit{{PersonID: director.PersonID, Name: director.Name, Role: models.CreditDirector}}
+	if err := store.Movies.UpdateCredits(ctx, "tt7", credits); err != nil {
+		t.Fatalf("UpdateCredits: %v", err)
+	}
+	if err := store.Movies.UpdatePoster(ctx, "tt7", "https://cdn.example.com/tt7.jpg"); err != nil {
+		t.Fatalf("UpdatePoster: %v", err)
+	}
+
+	movie.Title = "Replaced"
+	movie.Genre = []models.Genre{comedy, drama}
+	created, err = store.Movies.Upsert(ctx, movie)
+	if err != nil || created {
+		t.Fatalf("Movies.Upsert existing = %v, %v; want replaced", created, err)
+	}
+	got, _ := store.Movies.FindByImdbID(ctx, "tt7")
+	if got.ID != first.ID {
+		t.Errorf("Upsert changed the ID from %v to %v", first.ID, got.ID)
+	}
+	if got.Title != "Replaced" || len(got.Genre) != 2 || got.Genre[0] != comedy {
+		t.Errorf("Upsert did not replace the movie: %+v", got)
+	}
+	if got.PosterPath != "https://cdn.example.com/tt7.jpg" || got.EnrichedAt == nil || !got.EnrichedAt.Equal(now) {
+		t.Errorf("Upsert overwrote the poster or enrichment: %q, %v", got.PosterPath, got.EnrichedAt)
+	}
+	if len(got.Credits) != 1 || got.Credits[0].PersonID != director.PersonID {
+		t.Errorf("Upsert without credits dropped the existing ones: %+v", got.Credits)
+	}
+	if movies, _ := store.Movies.List(ctx, repository.MovieFilter{}); len(movies) != 1 {
+		t.Errorf("Upsert duplicated the movie: %d movies", len(movies))
+	}
+}
+
+func testUpsertMany(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, []models.Genre{action, comedy})
+
+	if n, err := store.Movies.UpsertMany(ctx, nil); err != nil || n != 0 {
+		t.Fatalf("UpsertMany(nil) = %d, %v; want 0", n, err)
+	}
+	existing, err := store.Movies.Create(ctx, sampleMovie("tt1", 1, action))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Movies.UpdatePoster(ctx, "tt1", "https://cdn.example.com/tt1.jpg"); err != nil {
+		t.Fatalf("UpdatePoster: %v", err)
+	}
+
+	replaced := sampleMovie("tt1", 2, comedy)
+	replaced.Title = "Replaced"
+	replaced.Directors = []string{"Jane Doe"}
+	batch := []models.Movie{replaced, sampleMovie("tt2", 3, action), sampleMovie("tt3", 4, comedy)}
+	created, err := store.Movies.UpsertMany(ctx, batch)
+	if err != nil || created != 2 {
+		t.Fatalf("UpsertMany = %d, %v; want 2 created", created, err)
+	}
+
+	got, err := store.Movies.FindByImdbID(ctx, "tt1")
+	if err != nil {
+		t.Fatalf("FindByImdbID: %v", err)
+	}
+	if got.ID != existing.ID {
+		t.Errorf("UpsertMany changed the ID from %v to %v", existing.ID, got.ID)
+	}
+	if got.Title != "Replaced" || len(got.Genre) != 1 || got.Genre[0] != comedy || len(got.Directors) != 1 {
+		t.Errorf("UpsertMany did not replace the movie: %+v", got)
+	}
+	if got.PosterPath != "https://cdn.example.com/tt1.jpg" {
+		t.Errorf("UpsertMany overwrote the uploaded poster with %q", got.PosterPath)
+	}
+	movies, _ := store.Movies.List(ctx, repository.MovieFilter{})
+	if len(movies) != 3 {
+		t.Errorf("List after UpsertMany returned %d movies, want 3", len(movies))
+	}
+	for _, m := range movies {
+		if m.ID.IsZero() {
+			t.Errorf("UpsertMany stored %s without an ID", m.ImdbID)
+		}
+	}
+
+	if created, err := store.Movies.UpsertMany(ctx, batch); err != nil || created != 0 {
+		t.Errorf("UpsertMany again = %d, %v; want 0 created", created, err)
+	}
+}
+
+func testStream(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, []models.Genre{action, comedy})
+
+	// Enough movies to span several pages of a paginating backend.
+	const total = 1203
+	movies := make([]models.Movie, total)
+	for i := range movies {
+		genre := action
+		if i%3 == 0 {
+			genre = comedy
+		}
+		movies[i] = sampleMovie(fmt.Sprintf("tt%05d", i), i+1, genre)
+	}
+	if _, err := store.Movies.UpsertMany(ctx, movies); err != nil {
+		t.Fatalf("UpsertMany: %v", err)
+	}
+
+	var streamed []models.Movie
+	err := store.Movies.Stream(ctx, repository.MovieFilter{}, func(m models.Movie) error {
+		streamed = append(streamed, m)
+		return nil
+	})
+	if err != nil || len(streamed) != total {
+		t.Fatalf("Stream = %d movies, %v; want %d", len(streamed), err, total)
+	}
+	seen := map[string]bool{}
+	for i, m := range streamed {
+		if i > 0 && m.ID.Hex() <= streamed[i-1].ID.Hex() {
+			t.Fatalf("Stream out of ID order at %d: %s after %s", i, m.ID.Hex(), streamed[i-1].ID.Hex())
+		}
+		if seen[m.ImdbID] {
+			t.Fatalf("Stream returned %s twice", m.ImdbID)
+		}
+		seen[m.ImdbID] = true
+	}
+	if len(streamed[0].Genre) != 1 {
+		t.Errorf("Stream did not load genres: %+v", streamed[0])
+	}
+
+	comedies := 0
+	err = store.Movies.Stream(ctx, repository.MovieFilter{Genre: comedy.GenreName}, func(m models.Movie) error {
+		if m.Genre[0] != comedy {
+			t.Errorf("filtered Stream returned %s in %v", m.ImdbID, m.Genre)
+		}
+		comedies++
+		return nil
+	})
+	if err != nil || comedies != (total+2)/3 {
+		t.Errorf("filtered Stream = %d movies, %v; want %d", comedies, err, (total+2)/3)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = store.Movies.Stream(ctx, repository.MovieFilter{}, func(models.Movie) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Stream after fn error = %v with %d calls; want stop after 1", err, calls)
+	}
+}
+
+func testRoles(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, nil)
+
+	alice := sampleUser("alice@example.com")
+	bob := sampleUser("bob@example.com")
+	for _, u := range []models.User{alice, bob} {
+		if err := store.Users.Create(ctx, u); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	if err := store.Users.UpdateRole(ctx, alice.UserID, models.RoleAdmin); err != nil {
+		t.Fatalf("UpdateRole: %v", err)
+	}
+	if err := store.Users.UpdateRole(ctx, "missing", models.RoleAdmin); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("UpdateRole on missing user: got %v, want ErrNotFound", err)
+	}
+	counts, err := store.Users.CountByRole(ctx)
+	if err != nil {
+		t.Fatalf("CountByRole: %v", err)
+	}
+	if counts[models.RoleAdmin] != 1 || counts[models.RoleUser] != 1 {
+		t.Errorf("CountByRole = %v", counts)
+	}
+
+	store.Users.SetRefreshTokenHash(ctx, alice.UserID, "a")
+	store.Users.SetRefreshTokenHash(ctx, bob.UserID, "b")
+	n, err := store.Users.ClearAllRefreshTokenHashes(ctx)
+	if err != nil || n != 2 {
+		t.Fatalf("ClearAllRefreshTokenHashes = %d, %v; want 2", n, err)
+	}
+	for _, u := range []models.User{alice, bob} {
+		if hash, _ := store.Users.RefreshTokenHash(ctx, u.UserID); hash != "" {
+			t.Errorf("session for %s not revoked", u.Email)
+		}
+	}
+}
+
+func testGenreAdmin(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, []models.Genre{action})
+
+	if _, err := store.Genres.FindByID(ctx, comedy.GenreId); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("FindByID on missing genre: got %v, want ErrNotFound", err)
+	}
+	if err := store.Genres.Create(ctx, comedy); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got, err := store.Genres.FindByID(ctx, comedy.GenreId); err != nil || got != comedy {
+		t.Fatalf("FindByID = %+v, %v; want %+v", got, err, comedy)
+	}
+	if err := store.Genres.Create(ctx, models.Genre{GenreId: action.GenreId, GenreName: "Duplicate"}); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("Create with a taken genre_id: got %v, want ErrConflict", err)
+	}
+
+	if err := store.Genres.Rename(ctx, comedy.GenreId, "Comedies"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if got, _ := store.Genres.FindByID(ctx, comedy.GenreId); got.GenreName != "Comedies" {
+		t.Errorf("Rename not persisted: %+v", got)
+	}
+	if err := store.Genres.Rename(ctx, 99, "Missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Rename on missing genre: got %v, want ErrNotFound", err)
+	}
+
+	if err := store.Genres.Delete(ctx, comedy.GenreId); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Genres.Delete(ctx, comedy.GenreId); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Delete twice: got %v, want ErrNotFound", err)
+	}
+	if genres, _ := store.Genres.List(ctx); len(genres) != 1 || genres[0] != action {
+		t.Errorf("List after Delete = %+v", genres)
+	}
+}
+
+func testGenreCascade(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, []models.Genre{action, comedy, drama})
+
+	for _, m := range []models.Movie{
+		sampleMovie("tt1", 1, action, comedy),
+		sampleMovie("tt2", 2, comedy, drama),
+		sampleMovie("tt3", 3, drama),
+	} {
+		if _, err := store.Movies.Create(ctx, m); err != nil {
+			t.Fatalf("Create %s: %v", m.ImdbID, err)
+		}
+	}
+	user := sampleUser("cascade@example.com", comedy, drama)
+	if err := store.Users.Create(ctx, user); err != nil {
+		t.Fatalf("Create user: %v", err)
+	}
+
+	if n, err := store.Movies.CountByGenre(ctx, comedy.GenreId); err != nil || n != 2 {
+		t.Errorf("Movies.CountByGenre = %d, %v; want 2", n, err)
+	}
+	if n, err := store.Users.CountByFavouriteGenre(ctx, drama.GenreId); err != nil || n != 1 {
+		t.Errorf("Users.CountByFavouriteGenre = %d, %v; want 1", n, err)
+	}
+
+	// Rename rewrites the embedded copies, so recommendations match the new name.
+	cascade, err := repository.RenameGenre(ctx, store, comedy.GenreId, "Comedies")
+	if err != nil {
+		t.Fatalf("RenameGenre: %v", err)
+	}
+	if cascade.Movies != 2 || cascade.Users != 1 {
+		t.Errorf("RenameGenre cascade = %+v; want 2 movies, 1 user", cascade)
+	}
+	if movies, _ := store.Movies.ListByGenreNames(ctx, []string{"Comedies"}, 10); len(movies) != 2 {
+		t.Errorf("ListByGenreNames after rename returned %d movies, want 2", len(movies))
+	}
+	if names, _ := store.Users.FavouriteGenreNames(ctx, user.UserID); len(names) != 2 || names[0] != "Comedies" {
+		t.Errorf("favourite genres after rename = %v", names)
+	}
+
+	// Merging drama into comedy leaves tt2 and the user with a single comedy entry.
+	renamedComedy := models.Genre{GenreId: comedy.GenreId, GenreName: "Comedies"}
+	cascade, err = repository.MergeGenres(ctx, store, drama.GenreId, renamedComedy)
+	if err != nil {
+		t.Fatalf("MergeGenres: %v", err)
+	}
+	if cascade.Movies != 2 || cascade.Users != 1 {
+		t.Errorf("MergeGenres cascade = %+v; want 2 movies, 1 user", cascade)
+	}
+	if _, err := store.Genres.FindByID(ctx, drama.GenreId); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("merged genre still exists: %v", err)
+	}
+	tt2, _ := store.Movies.FindByImdbID(ctx, "tt2")
+	if len(tt2.Genre) != 1 || tt2.Genre[0] != renamedComedy {
+		t.Errorf("tt2 genres after merge = %+v", tt2.Genre)
+	}
+	tt3, _ := store.Movies.FindByImdbID(ctx, "tt3")
+	if len(tt3.Genre) != 1 || tt3.Genre[0] != renamedComedy {
+		t.Errorf("tt3 genres after merge = %+v", tt3.Genre)
+	}
+	got, _ := store.Users.FindByID(ctx, user.UserID)
+	if len(got.FavouriteGenres) != 1 || got.FavouriteGenres[0] != renamedComedy {
+		t.Errorf("favourite genres after merge = %+v", got.FavouriteGenres)
+	}
+	if n, _ := store.Movies.CountByGenre(ctx, drama.GenreId); n != 0 {
+		t.Errorf("%d movies still reference the merged genre", n)
+	}
+}
+
+func withMetadata(m models.Movie, released string, runtime int, language, certification string, directors []string, cast ...string) models.Movie {
+	m.ReleaseDate = released
+	m.RuntimeMinutes = runtime
+	m.Synopsis = "About " + m.Title
+	m.OriginalLanguage = language
+	m.Certification = certification
+	m.Directors = directors
+	for _, name := range cast {
+		m.Cast = append(m.Cast, models.CastMember{Name: name, Character: "Role of " + name})
+	}
+	return m
+}
+
+func testMovieMetadata(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, nil)
+
+	full := withMetadata(sampleMovie("tt1", 1, action), "1999-03-31", 136, "en", "R",
+		[]string{"Lana Wachowski", "Lilly Wachowski"}, "Keanu Reeves", "Carrie-Anne Moss")
+	if _, err := store.Movies.Create(ctx, full); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	got, err := store.Movies.FindByImdbID(ctx, "tt1")
+	if err != nil {
+		t.Fatalf("FindByImdbID: %v", err)
+	}
+	if got.ReleaseDate != full.ReleaseDate || got.RuntimeMinutes != full.RuntimeMinutes || got.Synopsis != full.Synopsis ||
+		got.OriginalLanguage != full.OriginalLanguage || got.Certification != full.Certification {
+		t.Errorf("metadata not round-tripped: %+v", got)
+	}
+	if !reflect.DeepEqual(got.Directors, full.Directors) || !reflect.DeepEqual(got.Cast, full.Cast) {
+		t.Errorf("directors/cast not round-tripped in order: %v %v", got.Directors, got.Cast)
+	}
+
+	// Movies without metadata (e.g. stored before it existed) report empty lists, not nil.
+	if _, err := store.Movies.Create(ctx, sampleMovie("tt2", 2, action)); err != nil {
+		t.Fatalf("Create bare movie: %v", err)
+	}
+	bare, _ := store.Movies.FindByImdbID(ctx, "tt2")
+	if bare.Directors == nil || bare.Cast == nil || len(bare.Directors) != 0 || len(bare.Cast) != 0 {
+		t.Errorf("bare movie lists = %#v, %#v; want empty", bare.Directors, bare.Cast)
+	}
+
+	full.Cast = full.Cast[:1]
+	full.RuntimeMinutes = 150
+	if _, err := store.Movies.Upsert(ctx, full); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	got, _ = store.Movies.FindByImdbID(ctx, "tt1")
+	if got.RuntimeMinutes != 150 || len(got.Cast) != 1 || len(got.Directors) != 2 {
+		t.Errorf("Upsert did not replace metadata: %+v", got)
+	}
+}
+
+func testMovieFilter(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, nil)
+
+	for _, m := range []models.Movie{
+		withMetadata(sampleMovie("tt1", 1, action), "1994-09-23", 142, "en", "R", []string{"Frank Darabont"}, "Tim Robbins"),
+		withMetadata(sampleMovie("tt2", 2, comedy), "1999-03-31", 136, "en", "R", []string{"Lana Wachowski"}, "Keanu Reeves"),
+		withMetadata(sampleMovie("tt3", 3, drama), "2001-05-18", 90, "fr", "PG", []string{"Jean-Pierre Jeunet"}, "Audrey Tautou"),
+		sampleMovie("tt4", 4, action), // no metadata
+	} {
+		if _, err := store.Movies.Create(ctx, m); err != nil {
+			t.Fatalf("Create %s: %v", m.ImdbID, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter repository.MovieFilter
+		want   []string
+	}{
+		{"none", repository.MovieFilter{}, []string{"tt1", "tt2", "tt3", "tt4"}},
+		{"genre", repository.MovieFilter{Genre: "Action"}, []string{"tt1", "tt4"}},
+		{"year", repository.MovieFilter{YearFrom: 1999, YearTo: 1999}, []string{"tt2"}},
+		{"year from", repository.MovieFilter{YearFrom: 1999}, []string{"tt2", "tt3"}},
+		{"year to", repository.MovieFilter{YearTo: 1999}, []string{"tt1", "tt2"}},
+		{"language", repository.MovieFilter{Language: "fr"}, []string{"tt3"}},
+		{"certification", repository.MovieFilter{Certification: "R"}, []string{"tt1", "tt2"}},
+		{"director", repository.MovieFilter{Director: "Lana Wachowski"}, []string{"tt2"}},
+		{"cast", repository.MovieFilter{CastMember: "Tim Robbins"}, []string{"tt1"}},
+		{"runtime max", repository.MovieFilter{RuntimeMax: 140}, []string{"tt2", "tt3"}},
+		{"runtime range", repository.MovieFilter{RuntimeMin: 100, RuntimeMax: 140}, []string{"tt2"}},
+		{"combined", repository.MovieFilter{Language: "en", YearFrom: 1995}, []string{"tt2"}},
+	} {
+		movies, err := store.Movies.List(ctx, tc.filter)
+		if err != nil {
+			t.Fatalf("%s: List: %v", tc.name, err)
+		}
+		got := map[string]bool{}
+		for _, m := range movies {
+			got[m.ImdbID] = true
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: List returned %v, want %v", tc.name, keys(got), tc.want)
+			continue
+		}
+		for _, id := range tc.want {
+			if !got[id] {
+				t.Errorf("%s: List returned %v, want %v", tc.name, keys(got), tc.want)
+				break
+			}
+		}
+	}
+}
+
+func samplePerson(name string) models.Person {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	return models.Person{
+		PersonID:  bson.NewObjectID().Hex(),
+		Name:      name,
+		BirthDate: "1970-01-01",
+		Biography: "Biography of " + name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func testPeople(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, nil)
+
+	ann, bob, anna := samplePerson("Ann Lee"), samplePerson("Bob Stone"), samplePerson("Anna 100%")
+	for _, p := range []models.Person{ann, bob, anna} {
+		if err := store.People.Create(ctx, p); err != nil {
+			t.Fatalf("Create %s: %v", p.Name, err)
+		}
+	}
+
+	got, err := store.People.FindByID(ctx, ann.PersonID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if got.Name != ann.Name || got.BirthDate != ann.BirthDate || got.Biography != ann.Biography {
+		t.Errorf("FindByID = %+v, want %+v", got, ann)
+	}
+	if _, err := store.People.FindByID(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("FindByID(missing) err = %v, want ErrNotFound", err)
+	}
+
+	found, err := store.People.FindByIDs(ctx, []string{ann.PersonID, "missing", bob.PersonID})
+	if err != nil || len(found) != 2 {
+		t.Errorf("FindByIDs = %d people, %v; want 2", len(found), err)
+	}
+
+	names := func(people []models.Person) []string {
+		out := make([]string, 0, len(people))
+		for _, p := range people {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+	for _, tc := range []struct {
+		query string
+		limit int
+		want  []string
+	}{
+		{"ann", 10, []string{"Ann Lee", "Anna 100%"}},
+		{"STONE", 10, []string{"Bob Stone"}},
+		{"", 2, []string{"Ann Lee", "Anna 100%"}},
+		{"100%", 10, []string{"Anna 100%"}},
+		{"%", 10, []string{"Anna 100%"}},
+		{"_", 10, []string{}},
+	} {
+		people, err := store.People.Search(ctx, tc.query, tc.limit)
+		if err != nil {
+			t.Fatalf("Search(%q): %v", tc.query, err)
+		}
+		if got := names(people); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Search(%q, %d) = %v, want %v", tc.query, tc.limit, got, tc.want)
+		}
+	}
+
+	bob.Name = "Robert Stone"
+	if err := store.People.Update(ctx, bob); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got, _ := store.People.FindByID(ctx, bob.PersonID); got.Name != "Robert Stone" {
+		t.Errorf("name after Update = %q", got.Name)
+	}
+	if err := store.People.Update(ctx, samplePerson("Nobody")); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Update(missing) err = %v, want ErrNotFound", err)
+	}
+
+	if err := store.People.Delete(ctx, anna.PersonID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.People.Delete(ctx, anna.PersonID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("second Delete err = %v, want ErrNotFound", err)
+	}
+}
+
+func testCredits(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, []models.Genre{action})
+
+	director, actor := samplePerson("Dana Director"), samplePerson("Alex Actor")
+	for _, p := range []models.Person{director, actor} {
+		if err := store.People.Create(ctx, p); err != nil {
+			t.Fatalf("Create %s: %v", p.Name, err)
+		}
+	}
+	for _, id := range []string{"tt1", "tt2"} {
+		if _, err := store.Movies.Create(ctx, sampleMovie(id, 1, action)); err != nil {
+			t.Fatalf("Create %s: %v", id, err)
+		}
+	}
+
+	credits := []models.Credit{
+		{PersonID: director.PersonID, Name: director.Name, Role: models.CreditDirector, Order: 0},
+		{PersonID: actor.PersonID, Name: actor.Name, Role: models.CreditActor, Character: "Hero", Order: 1},
+		{PersonID: director.PersonID, Name: director.Name, Role: models.CreditActor, Character: "Cameo", Order: 2},
+	}
+	if err := store.Movies.UpdateCredits(ctx, "tt1", credits); err != nil {
+		t.Fatalf("UpdateCredits: %v", err)
+	}
+	if err := store.Movies.UpdateCredits(ctx, "tt2", credits[1:2]); err != nil {
+		t.Fatalf("UpdateCredits: %v", err)
+	}
+	if err := store.Movies.UpdateCredits(ctx, "missing", credits); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("UpdateCredits(missing) err = %v, want ErrNotFound", err)
+	}
+
+	tt1, err := store.Movies.FindByImdbID(ctx, "tt1")
+	if err != nil {
+		t.Fatalf("FindByImdbID: %v", err)
+	}
+	if !reflect.DeepEqual(tt1.Credits, credits) {
+		t.Errorf("credits = %+v, want %+v", tt1.Credits, credits)
+	}
+	if !reflect.DeepEqual(tt1.Directors, []string{"Dana Director"}) || len(tt1.Cast) != 2 || tt1.Cast[1].Character != "Cameo" {
+		t.Errorf("derived directors/cast = %v / %+v", tt1.Directors, tt1.Cast)
+	}
+
+	if movies, err := store.Movies.ListByPerson(ctx, actor.PersonID); err != nil || len(movies) != 2 {
+		t.Errorf("ListByPerson(actor) = %d movies, %v; want 2", len(movies), err)
+	}
+	if movies, err := store.Movies.ListByPerson(ctx, director.PersonID); err != nil || len(movies) != 1 {
+		t.Errorf("ListByPerson(director) = %d movies, %v; want 1", len(movies), err)
+	}
+
+	// Renaming rewrites every credit copy, including the derived cast entries.
+	director.Name = "Dana D. Director"
+	updated, err := repository.UpdatePerson(ctx, store, director)
+	if err != nil {
+		t.Fatalf("UpdatePerson: %v", err)
+	}
+	if updated != 1 {
+		t.Errorf("UpdatePerson rewrote %d movies, want 1", updated)
+	}
+	tt1, _ = store.Movies.FindByImdbID(ctx, "tt1")
+	if tt1.Credits[0].Name != director.Name || tt1.Credits[2].Name != director.Name || tt1.Directors[0] != director.Name {
+		t.Errorf("credits after rename = %+v, directors = %v", tt1.Credits, tt1.Directors)
+	}
+	if movies, _ := store.Movies.List(ctx, repository.MovieFilter{Director: director.Name}); len(movies) != 1 {
+		t.Errorf("List by renamed director returned %d movies, want 1", len(movies))
+	}
+
+	if err := store.Movies.UpdateCredits(ctx, "tt2", nil); err != nil {
+		t.Fatalf("UpdateCredits(nil): %v", err)
+	}
+	tt2, _ := store.Movies.FindByImdbID(ctx, "tt2")
+	if tt2.Credits == nil || len(tt2.Credits) != 0 || len(tt2.Cast) != 0 {
+		t.Errorf("tt2 after clearing credits = %+v / %+v", tt2.Credits, tt2.Cast)
+	}
+}
+
+func sampleCollection(name string, movieIDs ...string) models.Collection {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	return models.Collection{
+		CollectionID: bson.NewObjectID().Hex(),
+		Name:         name,
+		Overview:     "All of " + name,
+		MovieIDs:     movieIDs,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+func testCollections(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, []models.Genre{action})
+
+	for _, id := range []string{"tt1", "tt2", "tt3"} {
+		if _, err := store.Movies.Create(ctx, sampleMovie(id, 1, action)); err != nil {
+			t.Fatalf("Create %s: %v", id, err)
+		}
+	}
+	if movies, err := store.Movies.ListByImdbIDs(ctx, []string{"tt3", "missing", "tt1"}); err != nil || len(movies) != 2 {
+		t.Errorf("ListByImdbIDs = %d movies, %v; want 2", len(movies), err)
+	}
+
+	trilogy := sampleCollection("Trilogy", "tt3", "tt1", "tt2")
+	empty := sampleCollection("Anthology")
+	for _, c := range []models.Collection{trilogy, empty} {
+		if err := store.Collections.Create(ctx, c); err != nil {
+			t.Fatalf("Create %s: %v", c.Name, err)
+		}
+	}
+
+	got, err := store.Collections.FindByID(ctx, trilogy.CollectionID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if !reflect.DeepEqual(got.MovieIDs, trilogy.MovieIDs) || got.Name != trilogy.Name || got.Overview != trilogy.Overview {
+		t.Errorf("FindByID = %+v, want %+v", got, trilogy)
+	}
+	if got, _ := store.Collections.FindByID(ctx, empty.CollectionID); got.MovieIDs == nil || len(got.MovieIDs) != 0 {
+		t.Errorf("empty collection movie_ids = %#v, want []", got.MovieIDs)
+	}
+	if _, err := store.Collections.FindByID(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("FindByID(missing) err = %v, want ErrNotFound", err)
+	}
+
+	list, err := store.Collections.List(ctx)
+	if err != nil || len(list) != 2 || list[0].Name != "Anthology" {
+		t.Errorf("List = %+v, %v; want Anthology first", list, err)
+	}
+
+	byMovie, err := store.Collections.FindByMovie(ctx, "tt1")
+	if err != nil || byMovie.CollectionID != trilogy.CollectionID {
+		t.Errorf("FindByMovie(tt1) = %+v, %v", byMovie, err)
+	}
+	if ref, ok := byMovie.RefFor("tt1"); !ok || ref.Position != 2 || ref.Total != 3 {
+		t.Errorf("RefFor(tt1) = %+v, %v; want part 2 of 3", ref, ok)
+	}
+	if _, err := store.Collections.FindByMovie(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("FindByMovie(missing) err = %v, want ErrNotFound", err)
+	}
+
+	// A movie belongs to at most one collection.
+	if err := store.Collections.Create(ctx, sampleCollection("Other", "tt2")); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("Create with a claimed movie err = %v, want ErrConflict", err)
+	}
+	empty.MovieIDs = []string{"tt1"}
+	if err := store.Collections.Update(ctx, empty); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("Update with a claimed movie err = %v, want ErrConflict", err)
+	}
+
+	// Reordering and shrinking a collection frees the dropped movie.
+	trilogy.Name = "Duology"
+	trilogy.MovieIDs = []string{"tt1", "tt3"}
+	if err := store.Collections.Update(ctx, trilogy); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got, _ := store.Collections.FindByID(ctx, trilogy.CollectionID); got.Name != "Duology" || !reflect.DeepEqual(got.MovieIDs, trilogy.MovieIDs) {
+		t.Errorf("after Update = %+v", got)
+	}
+	empty.MovieIDs = []string{"tt2"}
+	if err := store.Collections.Update(ctx, empty); err != nil {
+		t.Errorf("Update with a freed movie: %v", err)
+	}
+	if err := store.Collections.Update(ctx, sampleCollection("Nowhere")); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Update(missing) err = %v, want ErrNotFound", err)
+	}
+
+	if err := store.Collections.Delete(ctx, trilogy.CollectionID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Collections.FindByMovie(ctx, "tt1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("FindByMovie after Delete err = %v, want ErrNotFound", err)
+	}
+	if err := store.Collections.Delete(ctx, trilogy.CollectionID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("second Delete err = %v, want ErrNotFound", err)
+	}
+}
+
+func keys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+func testEnrichment(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, []models.Genre{action, comedy})
+
+	for _, id := range []string{"tt1", "tt2", "tt3"} {
+		if _, err := store.Movies.Create(ctx, sampleMovie(id, 1, action)); err != nil {
+			t.Fatalf("Create %s: %v", id, err)
+		}
+	}
+	person := samplePerson("Dana Director")
+	if err := store.People.Create(ctx, person); err != nil {
+		t.Fatalf("Create person: %v", err)
+	}
+	credits := []models.Credit{{PersonID: person.PersonID, Name: person.Name, Role: models.CreditDirector}}
+	if err := store.Movies.UpdateCredits(ctx, "tt1", credits); err != nil {
+		t.Fatalf("UpdateCredits: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	old := now.Add(-48 * time.Hour)
+	enriched := sampleMovie("tt1", 1, comedy)
+	enriched.Title = "Enriched Title"
+	enriched.ReleaseDate = "1999-03-31"
+	enriched.RuntimeMinutes = 136
+	enriched.Synopsis = "A hacker learns the truth."
+	enriched.OriginalLanguage = "en"
+	enriched.Certification = "R"
+	enriched.Directors = []string{"Dana Director"}
+	enriched.Cast = []models.CastMember{{Name: "Alex Actor", Character: "Neo"}}
+	enriched.AdminReview = "must not be written"
+	enriched.EnrichedAt = &now
+	if err := store.Movies.UpdateMetadata(ctx, "tt1", enriched); err != nil {
+		t.Fatalf("UpdateMetadata: %v", err)
+	}
+	if err := store.Movies.UpdateMetadata(ctx, "missing", enriched); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("UpdateMetadata(missing) err = %v, want ErrNotFound", err)
+	}
+
+	tt1, err := store.Movies.FindByImdbID(ctx, "tt1")
+	if err != nil {
+		t.Fatalf("FindByImdbID: %v", err)
+	}
+	if tt1.Title != enriched.Title || tt1.RuntimeMinutes != 136 || tt1.Certification != "R" || tt1.Synopsis != enriched.Synopsis {
+		t.Errorf("metadata after UpdateMetadata = %+v", tt1)
+	}
+	if !reflect.DeepEqual(tt1.Genre, []models.Genre{comedy}) || !reflect.DeepEqual(tt1.Cast, enriched.Cast) {
+		t.Errorf("genre/cast = %+v / %+v", tt1.Genre, tt1.Cast)
+	}
+	if tt1.AdminReview != "review tt1" || len(tt1.Credits) != 1 {
+		t.Errorf("UpdateMetadata touched admin fields: review %q, credits %+v", tt1.AdminReview, tt1.Credits)
+	}
+	if tt1.EnrichedAt == nil || !tt1.EnrichedAt.Equal(now) {
+		t.Errorf("enriched_at = %v, want %v", tt1.EnrichedAt, now)
+	}
+
+	tt2 := sampleMovie("tt2", 1, action)
+	tt2.EnrichedAt = &old
+	if err := store.Movies.UpdateMetadata(ctx, "tt2", tt2); err != nil {
+		t.Fatalf("UpdateMetadata(tt2): %v", err)
+	}
+
+	// tt3 was never enriched, tt2 is older than the cut-off and tt1 is fresh.
+	stale, err := store.Movies.ListStale(ctx, now.Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("ListStale: %v", err)
+	}
+	var got []string
+	for _, m := range stale {
+		got = append(got, m.ImdbID)
+	}
+	if !reflect.DeepEqual(got, []string{"tt3", "tt2"}) {
+		t.Errorf("ListStale = %v, want [tt3 tt2]", got)
+	}
+	if stale, _ := store.Movies.ListStale(ctx, now.Add(time.Hour), 2); len(stale) != 2 || stale[1].ImdbID != "tt2" {
+		t.Errorf("ListStale(limit 2) = %d movies, want tt3 then tt2", len(stale))
+	}
+}
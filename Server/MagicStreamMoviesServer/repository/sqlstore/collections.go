@@ -0,0 +1,174 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+)
+
+const collectionColumns = "collection_id, name, overview, created_at, updated_at"
+
+type collectionRepository struct {
+	db *DB
+}
+
+func (r *collectionRepository) List(ctx context.Context) ([]models.Collection, error) {
+	return r.query(ctx, "SELECT "+collectionColumns+" FROM collections ORDER BY name, collection_id")
+}
+
+func (r *collectionRepository) FindByID(ctx context.Context, collectionId string) (models.Collection, error) {
+	return r.findOne(ctx, "SELECT "+collectionColumns+" FROM collections WHERE collection_id = ?", collectionId)
+}
+
+func (r *collectionRepository) FindByMovie(ctx context.Context, imdbID string) (models.Collection, error) {
+	return r.findOne(ctx, "SELECT "+collectionColumns+" FROM collections WHERE collection_id = "+
+		"(SELECT collection_id FROM collection_movies WHERE imdb_id = ?)", imdbID)
+}
+
+func (r *collectionRepository) findOne(ctx context.Context, query string, args ...any) (models.Collection, error) {
+	collections, err := r.query(ctx, query, args...)
+	if err != nil {
+		return models.Collection{}, err
+	}
+	if len(collections) == 0 {
+		return models.Collection{}, repository.ErrNotFound
+	}
+	return collections[0], nil
+}
+
+func (r *collectionRepository) Create(ctx context.Context, collection models.Collection) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.checkUnclaimed(ctx, tx, collection); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, r.db.rebind("INSERT INTO collections ("+collectionColumns+") VALUES ("+placeholders(5)+")"),
+		collection.CollectionID, collection.Name, collection.Overview, collection.CreatedAt.UTC(), collection.UpdatedAt.UTC())
+	if err != nil {
+		return err
+	}
+	if err := r.insertMembers(ctx, tx, collection); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *collectionRepository) Update(ctx context.Context, collection models.Collection) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		r.db.rebind("UPDATE collections SET name = ?, overview = ?, updated_at = ? WHERE collection_id = ?"),
+		collection.Name, collection.Overview, time.Now().UTC(), collection.CollectionID)
+	if err := affectedOne(result, err); err != nil {
+		return err
+	}
+	if err := r.checkUnclaimed(ctx, tx, collection); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, r.db.rebind("DELETE FROM collection_movies WHERE collection_id = ?"), collection.CollectionID); err != nil {
+		return err
+	}
+	if err := r.insertMembers(ctx, tx, collection); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkUnclaimed returns ErrConflict when another collection already holds one of
+// collection's movies. The unique imdb_id column backs this up under concurrency.
+func (r *collectionRepository) checkUnclaimed(ctx context.Context, tx *sql.Tx, collection models.Collection) error {
+	if len(collection.MovieIDs) == 0 {
+		return nil
+	}
+	args := []any{collection.CollectionID}
+	for _, id := range collection.MovieIDs {
+		args = append(args, id)
+	}
+
+	var count int
+	err := tx.QueryRowContext(ctx, r.db.rebind("SELECT COUNT(*) FROM collection_movies WHERE collection_id <> ? AND imdb_id IN ("+
+		placeholders(len(collection.MovieIDs))+")"), args...).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+func (r *collectionRepository) insertMembers(ctx context.Context, tx *sql.Tx, collection models.Collection) error {
+	stmt := r.db.rebind("INSERT INTO collection_movies (collection_id, position, imdb_id) VALUES (?, ?, ?)")
+	for i, id := range collection.MovieIDs {
+		if _, err := tx.ExecContext(ctx, stmt, collection.CollectionID, i, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *collectionRepository) Delete(ctx context.Context, collectionId string) error {
+	result, err := r.db.ExecContext(ctx, r.db.rebind("DELETE FROM collections WHERE collection_id = ?"), collectionId)
+	return affectedOne(result, err)
+}
+
+// query runs a collection SELECT and attaches each collection's members in order.
+// Rows are fully read first because SQLite runs on a single connection.
+func (r *collectionRepository) query(ctx context.Context, query string, args ...any) ([]models.Collection, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := []models.Collection{}
+	index := map[string]int{}
+	for rows.Next() {
+		c := models.Collection{MovieIDs: []string{}}
+		if err := rows.Scan(&c.CollectionID, &c.Name, &c.Overview, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		index[c.CollectionID] = len(collections)
+		collections = append(collections, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(collections) == 0 {
+		return collections, nil
+	}
+
+	ids := make([]any, 0, len(collections))
+	for _, c := range collections {
+		ids = append(ids, c.CollectionID)
+	}
+	members, err := r.db.QueryContext(ctx, r.db.rebind("SELECT collection_id, imdb_id FROM collection_movies WHERE collection_id IN ("+
+		placeholders(len(ids))+") ORDER BY collection_id, position"), ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer members.Close()
+
+	for members.Next() {
+		var collectionId, imdbID string
+		if err := members.Scan(&collectionId, &imdbID); err != nil {
+			return nil, err
+		}
+		if i, ok := index[collectionId]; ok {
+			collections[i].MovieIDs = append(collections[i].MovieIDs, imdbID)
+		}
+	}
+	return collections, members.Err()
+}
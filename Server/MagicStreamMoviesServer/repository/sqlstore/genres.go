@@ -0,0 +1,182 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+)
+
+type genreRepository struct {
+	db *DB
+}
+
+func (r *genreRepository) List(ctx context.Context) ([]models.Genre, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT genre_id, genre_name FROM genres ORDER BY genre_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []models.Genre{}
+	for rows.Next() {
+		var g models.Genre
+		if err := rows.Scan(&g.GenreId, &g.GenreName); err != nil {
+			return nil, err
+		}
+		genres = append(genres, g)
+	}
+	return genres, rows.Err()
+}
+
+func (r *genreRepository) FindByID(ctx context.Context, genreId int) (models.Genre, error) {
+	var g models.Genre
+	err := r.db.QueryRowContext(ctx, r.db.rebind("SELECT genre_id, genre_name FROM genres WHERE genre_id = ?"), genreId).
+		Scan(&g.GenreId, &g.GenreName)
+	if isNoRows(err) {
+		return models.Genre{}, repository.ErrNotFound
+	}
+	return g, err
+}
+
+func (r *genreRepository) Create(ctx context.Context, genre models.Genre) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRowContext(ctx, r.db.rebind("SELECT COUNT(*) FROM genres WHERE genre_id = ?"), genre.GenreId).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return repository.ErrConflict
+	}
+	if _, err := tx.ExecContext(ctx, r.db.rebind("INSERT INTO genres (genre_id, genre_name) VALUES (?, ?)"),
+		genre.GenreId, genre.GenreName); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *genreRepository) Rename(ctx context.Context, genreId int, name string) error {
+	result, err := r.db.ExecContext(ctx, r.db.rebind("UPDATE genres SET genre_name = ? WHERE genre_id = ?"), name, genreId)
+	return affectedOne(result, err)
+}
+
+func (r *genreRepository) Delete(ctx context.Context, genreId int) error {
+	result, err := r.db.ExecContext(ctx, r.db.rebind("DELETE FROM genres WHERE genre_id = ?"), genreId)
+	return affectedOne(result, err)
+}
+
+func (r *genreRepository) Upsert(ctx context.Context, genre models.Genre) (bool, error) {
+	result, err := r.db.ExecContext(ctx, r.db.rebind("UPDATE genres SET genre_name = ? WHERE genre_id = ?"),
+		genre.GenreName, genre.GenreId)
+	if err != nil {
+		return false, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n > 0 {
+		return false, err
+	}
+
+	_, err = r.db.ExecContext(ctx, r.db.rebind("INSERT INTO genres (genre_id, genre_name) VALUES (?, ?)"),
+		genre.GenreId, genre.GenreName)
+	return err == nil, err
+}
+
+// affectedOne turns an UPDATE or DELETE that matched no rows into repository.ErrNotFound.
+func affectedOne(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// embeddedGenres is a table of genre copies owned by a movie or user, stored in position order.
+type embeddedGenres struct {
+	table string // "movie_genres" or "user_favourite_genres"
+	owner string // "movie_id" or "user_id"
+}
+
+var (
+	movieGenres         = embeddedGenres{table: "movie_genres", owner: "movie_id"}
+	userFavouriteGenres = embeddedGenres{table: "user_favourite_genres", owner: "user_id"}
+)
+
+func (e embeddedGenres) insert(ctx context.Context, db *DB, tx *sql.Tx, ownerID string, genres []models.Genre) error {
+	stmt := db.rebind("INSERT INTO " + e.table + " (" + e.owner + ", position, genre_id, genre_name) VALUES (?, ?, ?, ?)")
+	for i, g := range genres {
+		if _, err := tx.ExecContext(ctx, stmt, ownerID, i, g.GenreId, g.GenreName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e embeddedGenres) countOwners(ctx context.Context, db *DB, genreId int) (int64, error) {
+	var count int64
+	err := db.QueryRowContext(ctx, db.rebind("SELECT COUNT(DISTINCT "+e.owner+") FROM "+e.table+" WHERE genre_id = ?"), genreId).
+		Scan(&count)
+	return count, err
+}
+
+// replace applies repository.ReplaceGenres to every owner holding one of fromIDs and
+// returns the IDs of the owners whose genres changed. Rows are fully read before
+// the next statement because SQLite runs on a single connection.
+func (e embeddedGenres) replace(ctx context.Context, db *DB, tx *sql.Tx, fromIDs []int, to models.Genre) ([]string, error) {
+	args := make([]any, 0, len(fromIDs))
+	for _, id := range fromIDs {
+		args = append(args, id)
+	}
+	rows, err := tx.QueryContext(ctx, db.rebind(
+		"SELECT "+e.owner+", genre_id, genre_name FROM "+e.table+" WHERE "+e.owner+" IN ("+
+			"SELECT "+e.owner+" FROM "+e.table+" WHERE genre_id IN ("+placeholders(len(args))+")"+
+			") ORDER BY "+e.owner+", position"), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var owners []string
+	current := map[string][]models.Genre{}
+	for rows.Next() {
+		var ownerID string
+		var g models.Genre
+		if err := rows.Scan(&ownerID, &g.GenreId, &g.GenreName); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if _, ok := current[ownerID]; !ok {
+			owners = append(owners, ownerID)
+		}
+		current[ownerID] = append(current[ownerID], g)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, ownerID := range owners {
+		genres, ok := repository.ReplaceGenres(current[ownerID], fromIDs, to)
+		if !ok {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, db.rebind("DELETE FROM "+e.table+" WHERE "+e.owner+" = ?"), ownerID); err != nil {
+			return nil, err
+		}
+		if err := e.insert(ctx, db, tx, ownerID, genres); err != nil {
+			return nil, err
+		}
+		changed = append(changed, ownerID)
+	}
+	return changed, nil
+}
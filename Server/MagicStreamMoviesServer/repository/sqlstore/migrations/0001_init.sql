@@ -0,0 +1,52 @@
+-- Initial schema mirroring the movies, genres and users collections.
+-- Genre ids and names are copied by value into movie_genres and
+-- user_favourite_genres, the same way Mongo embeds them in each document.
+
+CREATE TABLE genres (
+    genre_id   INTEGER PRIMARY KEY,
+    genre_name TEXT NOT NULL
+);
+
+CREATE TABLE movies (
+    id            TEXT PRIMARY KEY,
+    imdb_id       TEXT NOT NULL UNIQUE,
+    title         TEXT NOT NULL,
+    poster_path   TEXT NOT NULL,
+    youtube_id    TEXT NOT NULL,
+    admin_review  TEXT NOT NULL,
+    ranking_value INTEGER NOT NULL,
+    ranking_name  TEXT NOT NULL
+);
+
+CREATE INDEX idx_movies_ranking_value ON movies (ranking_value);
+
+CREATE TABLE movie_genres (
+    movie_id   TEXT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    position   INTEGER NOT NULL,
+    genre_id   INTEGER NOT NULL,
+    genre_name TEXT NOT NULL,
+    PRIMARY KEY (movie_id, position)
+);
+
+CREATE INDEX idx_movie_genres_genre_name ON movie_genres (genre_name);
+
+CREATE TABLE users (
+    id                 TEXT PRIMARY KEY,
+    user_id            TEXT NOT NULL UNIQUE,
+    first_name         TEXT NOT NULL,
+    last_name          TEXT NOT NULL,
+    email              TEXT NOT NULL UNIQUE,
+    password           TEXT NOT NULL,
+    role               TEXT NOT NULL,
+    created_at         TIMESTAMP NOT NULL,
+    updated_at         TIMESTAMP NOT NULL,
+    refresh_token_hash TEXT
+);
+
+CREATE TABLE user_favourite_genres (
+    user_id    TEXT NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    position   INTEGER NOT NULL,
+    genre_id   INTEGER NOT NULL,
+    genre_name TEXT NOT NULL,
+    PRIMARY KEY (user_id, position)
+);
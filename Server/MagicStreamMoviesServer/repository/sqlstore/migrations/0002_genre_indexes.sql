@@ -0,0 +1,6 @@
+-- Indexes for the genre admin API: renames, merges and in-use checks look up
+-- the embedded genre copies by genre_id.
+
+CREATE INDEX idx_movie_genres_genre_id ON movie_genres (genre_id);
+
+CREATE INDEX idx_user_favourite_genres_genre_id ON user_favourite_genres (genre_id);
@@ -0,0 +1,29 @@
+-- Descriptive movie metadata. Existing rows get empty/zero values, which the
+-- API reports as unknown. Directors and cast are kept in billing order.
+
+ALTER TABLE movies ADD COLUMN release_date TEXT NOT NULL DEFAULT '';
+ALTER TABLE movies ADD COLUMN runtime_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE movies ADD COLUMN synopsis TEXT NOT NULL DEFAULT '';
+ALTER TABLE movies ADD COLUMN original_language TEXT NOT NULL DEFAULT '';
+ALTER TABLE movies ADD COLUMN certification TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_movies_release_date ON movies (release_date);
+
+CREATE TABLE movie_directors (
+    movie_id TEXT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    name     TEXT NOT NULL,
+    PRIMARY KEY (movie_id, position)
+);
+
+CREATE INDEX idx_movie_directors_name ON movie_directors (name);
+
+CREATE TABLE movie_cast (
+    movie_id       TEXT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    position       INTEGER NOT NULL,
+    name           TEXT NOT NULL,
+    character_name TEXT NOT NULL,
+    PRIMARY KEY (movie_id, position)
+);
+
+CREATE INDEX idx_movie_cast_name ON movie_cast (name);
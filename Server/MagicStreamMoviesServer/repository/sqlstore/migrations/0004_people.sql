@@ -0,0 +1,27 @@
+-- People and movie credits. Credit names are copied by value, like genre names,
+-- and rewritten when a person is renamed; person_id has no foreign key so the
+-- table mirrors the Mongo documents.
+
+CREATE TABLE people (
+    person_id  TEXT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    birth_date TEXT NOT NULL,
+    biography  TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_people_name ON people (name);
+
+CREATE TABLE movie_credits (
+    movie_id       TEXT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    position       INTEGER NOT NULL,
+    person_id      TEXT NOT NULL,
+    name           TEXT NOT NULL,
+    role           TEXT NOT NULL,
+    character_name TEXT NOT NULL,
+    billing_order  INTEGER NOT NULL,
+    PRIMARY KEY (movie_id, position)
+);
+
+CREATE INDEX idx_movie_credits_person_id ON movie_credits (person_id);
@@ -0,0 +1,17 @@
+-- Movie collections (franchises, trilogies). Membership is ordered by position;
+-- the unique imdb_id keeps each movie in at most one collection.
+
+CREATE TABLE collections (
+    collection_id TEXT PRIMARY KEY,
+    name          TEXT NOT NULL,
+    overview      TEXT NOT NULL,
+    created_at    TIMESTAMP NOT NULL,
+    updated_at    TIMESTAMP NOT NULL
+);
+
+CREATE TABLE collection_movies (
+    collection_id TEXT NOT NULL REFERENCES collections (collection_id) ON DELETE CASCADE,
+    position      INTEGER NOT NULL,
+    imdb_id       TEXT NOT NULL UNIQUE,
+    PRIMARY KEY (collection_id, position)
+);
@@ -0,0 +1,6 @@
+-- When each movie's metadata was last refreshed from the external movie database.
+-- NULL means never; the refresher picks those movies first.
+
+ALTER TABLE movies ADD COLUMN enriched_at TIMESTAMP;
+
+CREATE INDEX idx_movies_enriched_at ON movies (enriched_at);
@@ -0,0 +1,553 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const movieColumns = "id, imdb_id, title, poster_path, youtube_id, admin_review, ranking_value, ranking_name, " +
+	"release_date, runtime_minutes, synopsis, original_language, certification, enriched_at"
+
+type movieRepository struct {
+	db *DB
+}
+
+func (r *movieRepository) List(ctx context.Context, filter repository.MovieFilter) ([]models.Movie, error) {
+	where, args := movieFilterWhere(filter)
+	return r.query(ctx, "SELECT "+movieColumns+" FROM movies m"+where+" ORDER BY id", args...)
+}
+
+// movieFilterWhere translates filter into a WHERE clause over "movies m".
+func movieFilterWhere(f repository.MovieFilter) (string, []any) {
+	var conds []string
+	var args []any
+	add := func(cond string, values ...any) {
+		conds = append(conds, cond)
+		args = append(args, values...)
+	}
+
+	if f.Genre != "" {
+		add("EXISTS (SELECT 1 FROM movie_genres g WHERE g.movie_id = m.id AND g.genre_name = ?)", f.Genre)
+	}
+	if from, until, ok := f.ReleaseDateRange(); ok {
+		add("m.release_date >= ? AND m.release_date < ?", from, until)
+	}
+	if f.Language != "" {
+		add("m.original_language = ?", f.Language)
+	}
+	if f.Certification != "" {
+		add("m.certification = ?", f.Certification)
+	}
+	if f.Director != "" {
+		add("EXISTS (SELECT 1 FROM movie_directors d WHERE d.movie_id = m.id AND d.name = ?)", f.Director)
+	}
+	if f.CastMember != "" {
+		add("EXISTS (SELECT 1 FROM movie_cast c WHERE c.movie_id = m.id AND c.name = ?)", f.CastMember)
+	}
+	if minimum, maximum, ok := f.RuntimeRange(); ok {
+		add("m.runtime_minutes >= ? AND m.runtime_minutes <= ?", minimum, maximum)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// streamPageSize is how many movies Stream loads per query.
+const streamPageSize = 500
+
+// Stream reads the result in pages keyed on id rather than holding a cursor open,
+// because the single SQLite connection is needed to load each page's side tables.
+func (r *movieRepository) Stream(ctx context.Context, filter repository.MovieFilter, fn func(models.Movie) error) error {
+	where, args := movieFilterWhere(filter)
+	if where == "" {
+		where = " WHERE m.id > ?"
+	} else {
+		where += " AND m.id > ?"
+	}
+	query := "SELECT " + movieColumns + " FROM movies m" + where + " ORDER BY id LIMIT " + strconv.Itoa(streamPageSize)
+
+	after := ""
+	for {
+		page, err := r.query(ctx, query, append(args, after)...)
+		if err != nil {
+			return err
+		}
+		for _, m := range page {
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+		if len(page) < streamPageSize {
+			return nil
+		}
+		after = page[len(page)-1].ID.Hex()
+	}
+}
+
+func (r *movieRepository) FindByImdbID(ctx context.Context, imdbID string) (models.Movie, error) {
+	movies, err := r.query(ctx, "SELECT "+movieColumns+" FROM movies WHERE imdb_id = ?", imdbID)
+	if err != nil {
+		return models.Movie{}, err
+	}
+	if len(movies) == 0 {
+		return models.Movie{}, repository.ErrNotFound
+	}
+	return movies[0], nil
+}
+
+func (r *movieRepository) Create(ctx context.Context, movie models.Movie) (models.Movie, error) {
+	if movie.ID.IsZero() {
+		movie.ID = bson.NewObjectID()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Movie{}, err
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRowContext(ctx, r.db.rebind("SELECT COUNT(*) FROM movies WHERE imdb_id = ?"), movie.ImdbID).Scan(&count); err != nil {
+		return models.Movie{}, err
+	}
+	if count > 0 {
+		return models.Movie{}, repository.ErrConflict
+	}
+	// The UNIQUE constraint still catches a concurrent insert of the same imdb_id.
+	if err := r.insert(ctx, tx, movie); err != nil {
+		if isUniqueViolation(err) {
+			return models.Movie{}, repository.ErrConflict
+		}
+		return models.Movie{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Movie{}, err
+	}
+	return movie, nil
+}
+
+func (r *movieRepository) insert(ctx context.Context, tx *sql.Tx, movie models.Movie) error {
+	_, err := tx.ExecContext(ctx, r.db.rebind("INSERT INTO movies ("+movieColumns+") VALUES ("+placeholders(14)+")"),
+		movie.ID.Hex(), movie.ImdbID, movie.Title, movie.PosterPath, movie.YouTubeID,
+		movie.AdminReview, movie.Ranking.RankingValue, movie.Ranking.RankingName,
+		movie.ReleaseDate, movie.RuntimeMinutes, movie.Synopsis, movie.OriginalLanguage, movie.Certification,
+		nullTime(movie.EnrichedAt))
+	if err != nil {
+		return err
+	}
+	return r.insertChildren(ctx, tx, movie.ID.Hex(), movie)
+}
+
+// insertChildren writes the ordered lists stored in side tables: genres, directors, cast and credits.
+func (r *movieRepository) insertChildren(ctx context.Context, tx *sql.Tx, movieID string, movie models.Movie) error {
+	if err := r.insertGenres(ctx, tx, movieID, movie.Genre); err != nil {
+		return err
+	}
+	return r.insertPeople(ctx, tx, movieID, movie)
+}
+
+// insertPeople writes the directors, cast and credits side tables.
+func (r *movieRepository) insertPeople(ctx context.Context, tx *sql.Tx, movieID string, movie models.Movie) error {
+	stmt := r.db.rebind("INSERT INTO movie_directors (movie_id, position, name) VALUES (?, ?, ?)")
+	for i, name := range movie.Directors {
+		if _, err := tx.ExecContext(ctx, stmt, movieID, i, name); err != nil {
+			return err
+		}
+	}
+	stmt = r.db.rebind("INSERT INTO movie_cast (movie_id, position, name, character_name) VALUES (?, ?, ?, ?)")
+	for i, member := range movie.Cast {
+		if _, err := tx.ExecContext(ctx, stmt, movieID, i, member.Name, member.Character); err != nil {
+			return err
+		}
+	}
+	stmt = r.db.rebind("INSERT INTO movie_credits (movie_id, position, person_id, name, role, character_name, billing_order) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?)")
+	for i, c := range movie.Credits {
+		if _, err := tx.ExecContext(ctx, stmt, movieID, i, c.PersonID, c.Name, c.Role, c.Character, c.Order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *movieRepository) deleteFrom(ctx context.Context, tx *sql.Tx, movieID string, tables ...string) error {
+	for _, table := range tables {
+		if _, err := tx.ExecContext(ctx, r.db.rebind("DELETE FROM "+table+" WHERE movie_id = ?"), movieID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *movieRepository) Upsert(ctx context.Context, movie models.Movie) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	created, err := r.upsert(ctx, tx, movie)
+	if err != nil {
+		return false, err
+	}
+	return created, tx.Commit()
+}
+
+// UpsertMany upserts the whole batch in one transaction.
+func (r *movieRepository) UpsertMany(ctx context.Context, movies []models.Movie) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	n := 0
+	for _, movie := range movies {
+		created, err := r.upsert(ctx, tx, movie)
+		if err != nil {
+			return 0, err
+		}
+		if created {
+			n++
+		}
+	}
+	return n, tx.Commit()
+}
+
+func (r *movieRepository) upsert(ctx context.Context, tx *sql.Tx, movie models.Movie) (bool, error) {
+	var id string
+	err := tx.QueryRowContext(ctx, r.db.rebind("SELECT id FROM movies WHERE imdb_id = ?"), movie.ImdbID).Scan(&id)
+	created := isNoRows(err)
+	if err != nil && !created {
+		return false, err
+	}
+
+	if created {
+		if movie.ID.IsZero() {
+			movie.ID = bson.NewObjectID()
+		}
+		return true, r.insert(ctx, tx, movie)
+	}
+
+	_, err = tx.ExecContext(ctx, r.db.rebind(
+		"UPDATE movies SET title = ?, youtube_id = ?, admin_review = ?, ranking_value = ?, ranking_name = ?, "+
+			"release_date = ?, runtime_minutes = ?, synopsis = ?, original_language = ?, certification = ? WHERE id = ?"),
+		movie.Title, movie.YouTubeID, movie.AdminReview,
+		movie.Ranking.RankingValue, movie.Ranking.RankingName,
+		movie.ReleaseDate, movie.RuntimeMinutes, movie.Synopsis, movie.OriginalLanguage, movie.Certification, id)
+	if err != nil {
+		return false, err
+	}
+	tables := []string{"movie_genres", "movie_directors", "movie_cast"}
+	if len(movie.Credits) > 0 {
+		tables = append(tables, "movie_credits")
+	}
+	if err := r.deleteFrom(ctx, tx, id, tables...); err != nil {
+		return false, err
+	}
+	return false, r.insertChildren(ctx, tx, id, movie)
+}
+
+// nullTime stores a nil time as NULL.
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+func (r *movieRepository) insertGenres(ctx context.Context, tx *sql.Tx, movieID string, genres []models.Genre) error {
+	return movieGenres.insert(ctx, r.db, tx, movieID, genres)
+}
+
+func (r *movieRepository) CountByGenre(ctx context.Context, genreId int) (int64, error) {
+	return movieGenres.countOwners(ctx, r.db, genreId)
+}
+
+func (r *movieRepository) ReplaceGenre(ctx context.Context, fromIDs []int, to models.Genre) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	changed, err := movieGenres.replace(ctx, r.db, tx, fromIDs, to)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(changed)), tx.Commit()
+}
+
+func (r *movieRepository) ListByImdbIDs(ctx context.Context, imdbIDs []string) ([]models.Movie, error) {
+	if len(imdbIDs) == 0 {
+		return []models.Movie{}, nil
+	}
+	args := make([]any, 0, len(imdbIDs))
+	for _, id := range imdbIDs {
+		args = append(args, id)
+	}
+	return r.query(ctx, "SELECT "+movieColumns+" FROM movies WHERE imdb_id IN ("+placeholders(len(args))+") ORDER BY id", args...)
+}
+
+func (r *movieRepository) ListByPerson(ctx context.Context, personId string) ([]models.Movie, error) {
+	return r.query(ctx, "SELECT "+movieColumns+" FROM movies m WHERE EXISTS ("+
+		"SELECT 1 FROM movie_credits c WHERE c.movie_id = m.id AND c.person_id = ?) ORDER BY id", personId)
+}
+
+func (r *movieRepository) UpdateCredits(ctx context.Context, imdbID string, credits []models.Credit) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, r.db.rebind("SELECT id FROM movies WHERE imdb_id = ?"), imdbID).Scan(&id)
+	if isNoRows(err) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	movie := models.Movie{Credits: credits}
+	movie.Directors, movie.Cast = models.DirectorsAndCast(credits)
+	if err := r.deleteFrom(ctx, tx, id, "movie_directors", "movie_cast", "movie_credits"); err != nil {
+		return err
+	}
+	if err := r.insertPeople(ctx, tx, id, movie); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *movieRepository) UpdateMetadata(ctx context.Context, imdbID string, movie models.Movie) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, r.db.rebind("SELECT id FROM movies WHERE imdb_id = ?"), imdbID).Scan(&id)
+	if isNoRows(err) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, r.db.rebind(
+		"UPDATE movies SET title = ?, poster_path = ?, youtube_id = ?, release_date = ?, runtime_minutes = ?, "+
+			"synopsis = ?, original_language = ?, certification = ?, enriched_at = ? WHERE id = ?"),
+		movie.Title, movie.PosterPath, movie.YouTubeID, movie.ReleaseDate, movie.RuntimeMinutes,
+		movie.Synopsis, movie.OriginalLanguage, movie.Certification, nullTime(movie.EnrichedAt), id)
+	if err != nil {
+		return err
+	}
+	if err := r.deleteFrom(ctx, tx, id, "movie_genres", "movie_directors", "movie_cast"); err != nil {
+		return err
+	}
+	if err := r.insertGenres(ctx, tx, id, movie.Genre); err != nil {
+		return err
+	}
+	// Credits are left in place, so only the directors and cast side tables are rewritten.
+	movie.Credits = nil
+	if err := r.insertPeople(ctx, tx, id, movie); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListStale orders NULLs first explicitly, as SQLite and Postgres disagree on the default.
+func (r *movieRepository) ListStale(ctx context.Context, before time.Time, limit int) ([]models.Movie, error) {
+	return r.query(ctx, "SELECT "+movieColumns+" FROM movies WHERE enriched_at IS NULL OR enriched_at < ? "+
+		"ORDER BY CASE WHEN enriched_at IS NULL THEN 0 ELSE 1 END, enriched_at, id LIMIT ?", before.UTC(), limit)
+}
+
+func (r *movieRepository) UpdatePoster(ctx context.Context, imdbID, posterPath string) error {
+	result, err := r.db.ExecContext(ctx, r.db.rebind("UPDATE movies SET poster_path = ? WHERE imdb_id = ?"), posterPath, imdbID)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *movieRepository) UpdateReview(ctx context.Context, imdbID, review string, ranking models.Ranking) error {
+	result, err := r.db.ExecContext(ctx,
+		r.db.rebind("UPDATE movies SET admin_review = ?, ranking_value = ?, ranking_name = ? WHERE imdb_id = ?"),
+		review, ranking.RankingValue, ranking.RankingName, imdbID)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *movieRepository) ListByGenreNames(ctx context.Context, genreNames []string, limit int) ([]models.Movie, error) {
+	if len(genreNames) == 0 {
+		return []models.Movie{}, nil
+	}
+
+	args := make([]any, 0, len(genreNames)+1)
+	for _, name := range genreNames {
+		args = append(args, name)
+	}
+	args = append(args, limit)
+
+	return r.query(ctx, "SELECT "+movieColumns+" FROM movies m WHERE EXISTS ("+
+		"SELECT 1 FROM movie_genres g WHERE g.movie_id = m.id AND g.genre_name IN ("+placeholders(len(genreNames))+")"+
+		") ORDER BY ranking_value, id LIMIT ?", args...)
+}
+
+// query runs a movie SELECT and attaches each movie's genres, directors, cast and credits.
+// Rows are fully read before the side-table lookups because SQLite runs on a single connection.
+func (r *movieRepository) query(ctx context.Context, query string, args ...any) ([]models.Movie, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	movies := []models.Movie{}
+	index := map[string]int{}
+	for rows.Next() {
+		var m models.Movie
+		var id string
+		var enrichedAt sql.NullTime
+		if err := rows.Scan(&id, &m.ImdbID, &m.Title, &m.PosterPath, &m.YouTubeID,
+			&m.AdminReview, &m.Ranking.RankingValue, &m.Ranking.RankingName,
+			&m.ReleaseDate, &m.RuntimeMinutes, &m.Synopsis, &m.OriginalLanguage, &m.Certification, &enrichedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if enrichedAt.Valid {
+			at := enrichedAt.Time.UTC()
+			m.EnrichedAt = &at
+		}
+		if m.ID, err = bson.ObjectIDFromHex(id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		m.FillDefaults()
+		index[id] = len(movies)
+		movies = append(movies, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(movies) == 0 {
+		return movies, nil
+	}
+
+	ids := make([]any, 0, len(movies))
+	for _, m := range movies {
+		ids = append(ids, m.ID.Hex())
+	}
+	in := " WHERE movie_id IN (" + placeholders(len(ids)) + ") ORDER BY movie_id, position"
+
+	err = r.scanChildren(ctx, "SELECT movie_id, genre_id, genre_name FROM movie_genres"+in, ids, func(rows *sql.Rows) error {
+		var movieID string
+		var g models.Genre
+		if err := rows.Scan(&movieID, &g.GenreId, &g.GenreName); err != nil {
+			return err
+		}
+		if i, ok := index[movieID]; ok {
+			movies[i].Genre = append(movies[i].Genre, g)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.scanChildren(ctx, "SELECT movie_id, name FROM movie_directors"+in, ids, func(rows *sql.Rows) error {
+		var movieID, name string
+		if err := rows.Scan(&movieID, &name); err != nil {
+			return err
+		}
+		if i, ok := index[movieID]; ok {
+			movies[i].Directors = append(movies[i].Directors, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.scanChildren(ctx, "SELECT movie_id, name, character_name FROM movie_cast"+in, ids, func(rows *sql.Rows) error {
+		var movieID string
+		var member models.CastMember
+		if err := rows.Scan(&movieID, &member.Name, &member.Character); err != nil {
+			return err
+		}
+		if i, ok := index[movieID]; ok {
+			movies[i].Cast = append(movies[i].Cast, member)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.scanChildren(ctx, "SELECT movie_id, person_id, name, role, character_name, billing_order FROM movie_credits"+in, ids, func(rows *sql.Rows) error {
+		var movieID string
+		var c models.Credit
+		if err := rows.Scan(&movieID, &c.PersonID, &c.Name, &c.Role, &c.Character, &c.Order); err != nil {
+			return err
+		}
+		if i, ok := index[movieID]; ok {
+			movies[i].Credits = append(movies[i].Credits, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+// scanChildren runs a side-table query and calls scan for each row.
+func (r *movieRepository) scanChildren(ctx context.Context, query string, args []any, scan func(*sql.Rows) error) error {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind(query), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// isNoRows reports whether err is sql.ErrNoRows.
+func isNoRows(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
@@ -0,0 +1,83 @@
+package sqlstore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+)
+
+const personColumns = "person_id, name, birth_date, biography, created_at, updated_at"
+
+type personRepository struct {
+	db *DB
+}
+
+func (r *personRepository) Create(ctx context.Context, person models.Person) error {
+	_, err := r.db.ExecContext(ctx, r.db.rebind("INSERT INTO people ("+personColumns+") VALUES ("+placeholders(6)+")"),
+		person.PersonID, person.Name, person.BirthDate, person.Biography, person.CreatedAt.UTC(), person.UpdatedAt.UTC())
+	return err
+}
+
+func (r *personRepository) FindByID(ctx context.Context, personId string) (models.Person, error) {
+	people, err := r.query(ctx, "SELECT "+personColumns+" FROM people WHERE person_id = ?", personId)
+	if err != nil {
+		return models.Person{}, err
+	}
+	if len(people) == 0 {
+		return models.Person{}, repository.ErrNotFound
+	}
+	return people[0], nil
+}
+
+func (r *personRepository) FindByIDs(ctx context.Context, personIds []string) ([]models.Person, error) {
+	if len(personIds) == 0 {
+		return []models.Person{}, nil
+	}
+	args := make([]any, 0, len(personIds))
+	for _, id := range personIds {
+		args = append(args, id)
+	}
+	return r.query(ctx, "SELECT "+personColumns+" FROM people WHERE person_id IN ("+placeholders(len(args))+")", args...)
+}
+
+// likeEscaper escapes the LIKE wildcards so a search for "50%" matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *personRepository) Search(ctx context.Context, query string, limit int) ([]models.Person, error) {
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(query)) + "%"
+	return r.query(ctx, "SELECT "+personColumns+` FROM people WHERE LOWER(name) LIKE ? ESCAPE '\' ORDER BY name, person_id LIMIT ?`,
+		pattern, limit)
+}
+
+func (r *personRepository) Update(ctx context.Context, person models.Person) error {
+	result, err := r.db.ExecContext(ctx,
+		r.db.rebind("UPDATE people SET name = ?, birth_date = ?, biography = ?, updated_at = ? WHERE person_id = ?"),
+		person.Name, person.BirthDate, person.Biography, time.Now().UTC(), person.PersonID)
+	return affectedOne(result, err)
+}
+
+func (r *personRepository) Delete(ctx context.Context, personId string) error {
+	result, err := r.db.ExecContext(ctx, r.db.rebind("DELETE FROM people WHERE person_id = ?"), personId)
+	return affectedOne(result, err)
+}
+
+func (r *personRepository) query(ctx context.Context, query string, args ...any) ([]models.Person, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	people := []models.Person{}
+	for rows.Next() {
+		var p models.Person
+		if err := rows.Scan(&p.PersonID, &p.Name, &p.BirthDate, &p.Biography, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		people = append(people, p)
+	}
+	return people, rows.Err()
+}
@@ -0,0 +1,269 @@
+// Package sqlstore implements the repository interfaces on top of database/sql,
+// for deployments and CI runs that do not have MongoDB. SQLite and Postgres are supported.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" driver
+	"modernc.org/sqlite"               // registers the "sqlite" driver
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Dialect selects the SQL flavour and driver.
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// DB is a *sql.DB that knows which placeholder style its dialect uses.
+type DB struct {
+	*sql.DB
+	dialect Dialect
+}
+
+// Open connects to the database. For SQLite the pool is limited to a single
+// connection, which serialises writes and keeps ":memory:" databases shared, and
+// foreign keys are switched on through the DSN so every connection the pool opens
+// enforces them.
+func Open(dialect Dialect, dsn string) (*DB, error) {
+	var driver string
+	switch dialect {
+	case SQLite:
+		driver = "sqlite"
+		dsn = withForeignKeys(dsn)
+	case Postgres:
+		driver = "pgx"
+	default:
+		return nil, fmt.Errorf("unsupported SQL dialect %q", dialect)
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if dialect == SQLite {
+		db.SetMaxOpenConns(1)
+	}
+
+	return &DB{DB: db, dialect: dialect}, nil
+}
+
+// withForeignKeys adds the pragma the SQLite driver runs on each new connection.
+func withForeignKeys(dsn string) string {
+	if strings.Contains(dsn, "_pragma=foreign_keys") {
+		return dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&_pragma=foreign_keys(1)"
+	}
+	return dsn + "?_pragma=foreign_keys(1)"
+}
+
+// NewStore returns the SQL-backed repositories. Call Migrate first.
+func NewStore(db *DB) repository.Store {
+	return repository.Store{
+		Movies:      &movieRepository{db: db},
+		Users:       &userRepository{db: db},
+		Genres:      &genreRepository{db: db},
+		People:      &personRepository{db: db},
+		Collections: &collectionRepository{db: db},
+	}
+}
+
+// rebind rewrites "?" placeholders into "$1, $2, ..." for Postgres.
+func (db *DB) rebind(query string) string {
+	if db.dialect != Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// placeholders returns "?, ?, ?" for n arguments.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// isUniqueViolation reports whether err is a UNIQUE or PRIMARY KEY constraint
+// failure from either driver.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505"
+	}
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		return liteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || liteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	}
+	return false
+}
+
+type migration struct {
+	version int
+	name    string
+	path    string
+}
+
+// migrations lists the embedded migration files ordered by version.
+func migrations() ([]migration, error) {
+	paths, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	list := make([]migration, 0, len(paths))
+	for _, path := range paths {
+		name := strings.TrimSuffix(strings.TrimPrefix(path, "migrations/"), ".sql")
+		version, err := strconv.Atoi(strings.SplitN(name, "_", 2)[0])
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: file name must start with a version number", path)
+		}
+		list = append(list, migration{version: version, name: name, path: path})
+	}
+	return list, nil
+}
+
+func (db *DB) appliedVersions(ctx context.Context) (map[int]bool, error) {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+// PendingMigrations returns the names of embedded migrations not yet applied.
+func (db *DB) PendingMigrations(ctx context.Context) ([]string, error) {
+	applied, err := db.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	all, err := migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, m := range all {
+		if !applied[m.version] {
+			pending = append(pending, m.name)
+		}
+	}
+	return pending, nil
+}
+
+// Migrate applies every embedded migration not yet recorded in schema_migrations
+// and returns the names of the ones it ran. Each migration runs in its own transaction.
+func (db *DB) Migrate(ctx context.Context) ([]string, error) {
+	applied, err := db.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	all, err := migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []string
+	for _, m := range all {
+		if applied[m.version] {
+			continue
+		}
+
+		body, err := migrationFiles.ReadFile(m.path)
+		if err != nil {
+			return ran, err
+		}
+		if err := db.applyMigration(ctx, m.version, m.name, string(body)); err != nil {
+			return ran, fmt.Errorf("migration %s: %w", m.name, err)
+		}
+		ran = append(ran, m.name)
+	}
+	return ran, nil
+}
+
+func (db *DB) applyMigration(ctx context.Context, version int, name, body string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range splitStatements(body) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		db.rebind("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)"),
+		version, name, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// splitStatements splits a migration file on ";" after dropping "--" comments.
+func splitStatements(body string) []string {
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var stmts []string
+	for _, stmt := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts
+}
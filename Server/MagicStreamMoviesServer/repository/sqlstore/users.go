@@ -0,0 +1,215 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const userColumns = "id, user_id, first_name, last_name, email, password, role, created_at, updated_at"
+
+type userRepository struct {
+	db *DB
+}
+
+func (r *userRepository) Create(ctx context.Context, user models.User) error {
+	if user.ID.IsZero() {
+		user.ID = bson.NewObjectID()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, r.db.rebind("INSERT INTO users ("+userColumns+") VALUES ("+placeholders(9)+")"),
+		user.ID.Hex(), user.UserID, user.FirstName, user.LastName, user.Email,
+		user.Password, user.Role, user.CreatedAt.UTC(), user.UpdatedAt.UTC())
+	if err != nil {
+		return err
+	}
+
+	if err := userFavouriteGenres.insert(ctx, r.db, tx, user.UserID, user.FavouriteGenres); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *userRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, r.db.rebind("SELECT COUNT(*) FROM users WHERE email = ?"), email).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	return r.findOne(ctx, "email", email)
+}
+
+func (r *userRepository) FindByID(ctx context.Context, userId string) (models.User, error) {
+	return r.findOne(ctx, "user_id", userId)
+}
+
+// findOne loads a user by a unique column ("email" or "user_id") with their favourite genres.
+func (r *userRepository) findOne(ctx context.Context, column, value string) (models.User, error) {
+	var u models.User
+	var id string
+	err := r.db.QueryRowContext(ctx, r.db.rebind("SELECT "+userColumns+" FROM users WHERE "+column+" = ?"), value).
+		Scan(&id, &u.UserID, &u.FirstName, &u.LastName, &u.Email, &u.Password, &u.Role, &u.CreatedAt, &u.UpdatedAt)
+	if isNoRows(err) {
+		return models.User{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+	if u.ID, err = bson.ObjectIDFromHex(id); err != nil {
+		return models.User{}, err
+	}
+
+	u.FavouriteGenres, err = r.favouriteGenres(ctx, u.UserID)
+	if err != nil {
+		return models.User{}, err
+	}
+	return u, nil
+}
+
+func (r *userRepository) favouriteGenres(ctx context.Context, userId string) ([]models.Genre, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.db.rebind("SELECT genre_id, genre_name FROM user_favourite_genres WHERE user_id = ? ORDER BY position"), userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []models.Genre{}
+	for rows.Next() {
+		var g models.Genre
+		if err := rows.Scan(&g.GenreId, &g.GenreName); err != nil {
+			return nil, err
+		}
+		genres = append(genres, g)
+	}
+	return genres, rows.Err()
+}
+
+func (r *userRepository) FavouriteGenreNames(ctx context.Context, userId string) ([]string, error) {
+	if _, err := r.RefreshTokenHash(ctx, userId); err != nil {
+		return nil, err // ErrNotFound when the user does not exist
+	}
+	genres, err := r.favouriteGenres(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(genres))
+	for _, g := range genres {
+		names = append(names, g.GenreName)
+	}
+	return names, nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, userId, passwordHash string) error {
+	_, err := r.db.ExecContext(ctx, r.db.rebind("UPDATE users SET password = ?, updated_at = ? WHERE user_id = ?"),
+		passwordHash, time.Now().UTC(), userId)
+	return err
+}
+
+func (r *userRepository) SetRefreshTokenHash(ctx context.Context, userId, tokenHash string) error {
+	_, err := r.db.ExecContext(ctx, r.db.rebind("UPDATE users SET refresh_token_hash = ?, updated_at = ? WHERE user_id = ?"),
+		tokenHash, time.Now().UTC(), userId)
+	return err
+}
+
+func (r *userRepository) RefreshTokenHash(ctx context.Context, userId string) (string, error) {
+	var hash sql.NullString
+	err := r.db.QueryRowContext(ctx, r.db.rebind("SELECT refresh_token_hash FROM users WHERE user_id = ?"), userId).Scan(&hash)
+	if isNoRows(err) {
+		return "", repository.ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return hash.String, nil
+}
+
+func (r *userRepository) ClearRefreshTokenHash(ctx context.Context, userId string) error {
+	_, err := r.db.ExecContext(ctx, r.db.rebind("UPDATE users SET refresh_token_hash = NULL, updated_at = ? WHERE user_id = ?"),
+		time.Now().UTC(), userId)
+	return err
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, userId, role string) error {
+	result, err := r.db.ExecContext(ctx, r.db.rebind("UPDATE users SET role = ?, updated_at = ? WHERE user_id = ?"),
+		role, time.Now().UTC(), userId)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) CountByRole(ctx context.Context) (map[string]int64, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT role, COUNT(*) FROM users GROUP BY role")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var role string
+		var n int64
+		if err := rows.Scan(&role, &n); err != nil {
+			return nil, err
+		}
+		counts[role] = n
+	}
+	return counts, rows.Err()
+}
+
+func (r *userRepository) ClearAllRefreshTokenHashes(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		r.db.rebind("UPDATE users SET refresh_token_hash = NULL, updated_at = ? WHERE refresh_token_hash IS NOT NULL"),
+		time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *userRepository) CountByFavouriteGenre(ctx context.Context, genreId int) (int64, error) {
+	return userFavouriteGenres.countOwners(ctx, r.db, genreId)
+}
+
+func (r *userRepository) ReplaceFavouriteGenre(ctx context.Context, fromIDs []int, to models.Genre) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	changed, err := userFavouriteGenres.replace(ctx, r.db, tx, fromIDs, to)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().UTC()
+	for _, userId := range changed {
+		if _, err := tx.ExecContext(ctx, r.db.rebind("UPDATE users SET updated_at = ? WHERE user_id = ?"), now, userId); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(changed)), tx.Commit()
+}
@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+)
+
+// Timeouts bounds individual repository calls. Deadlines are layered on top of the
+// caller's context, so a cancelled request still aborts the query immediately.
+type Timeouts struct {
+	Read  time.Duration
+	Write time.Duration
+}
+
+// LoadTimeouts reads per-operation deadlines from the environment:
+//
+//	DB_READ_TIMEOUT   lookups and listings (default 5s)
+//	DB_WRITE_TIMEOUT  inserts, updates and upserts (default 10s)
+func LoadTimeouts() Timeouts {
+	return Timeouts{
+		Read:  envDuration("DB_READ_TIMEOUT", 5*time.Second),
+		Write: envDuration("DB_WRITE_TIMEOUT", 10*time.Second),
+	}
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// WithTimeouts wraps every repository in store so each call runs under the matching deadline.
+func WithTimeouts(store Store, t Timeouts) Store {
+	return Store{
+		Movies:      &timeoutMovieRepository{next: store.Movies, t: t},
+		Users:       &timeoutUserRepository{next: store.Users, t: t},
+		Genres:      &timeoutGenreRepository{next: store.Genres, t: t},
+		People:      &timeoutPersonRepository{next: store.People, t: t},
+		Collections: &timeoutCollectionRepository{next: store.Collections, t: t},
+	}
+}
+
+func (t Timeouts) read(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, t.Read)
+}
+
+func (t Timeouts) write(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, t.Write)
+}
+
+// ---------- MOVIES ----------
+
+type timeoutMovieRepository struct {
+	next MovieRepository
+	t    Timeouts
+}
+
+func (r *timeoutMovieRepository) List(ctx context.Context, filter MovieFilter) ([]models.Movie, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.List(ctx, filter)
+}
+
+// Stream lasts as long as the caller keeps consuming, so it runs under the caller's
+// context alone rather than the read deadline.
+func (r *timeoutMovieRepository) Stream(ctx context.Context, filter MovieFilter, fn func(models.Movie) error) error {
+	return r.next.Stream(ctx, filter, fn)
+}
+
+func (r *timeoutMovieRepository) FindByImdbID(ctx context.Context, imdbID string) (models.Movie, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.FindByImdbID(ctx, imdbID)
+}
+
+func (r *timeoutMovieRepository) Create(ctx context.Context, movie models.Movie) (models.Movie, error) {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Create(ctx, movie)
+}
+
+func (r *timeoutMovieRepository) UpdateReview(ctx context.Context, imdbID, review string, ranking models.Ranking) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.UpdateReview(ctx, imdbID, review, ranking)
+}
+
+func (r *timeoutMovieRepository) ListByGenreNames(ctx context.Context, genreNames []string, limit int) ([]models.Movie, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.ListByGenreNames(ctx, genreNames, limit)
+}
+
+func (r *timeoutMovieRepository) Upsert(ctx context.Context, movie models.Movie) (bool, error) {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Upsert(ctx, movie)
+}
+
+func (r *timeoutMovieRepository) UpsertMany(ctx context.Context, movies []models.Movie) (int, error) {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.UpsertMany(ctx, movies)
+}
+
+func (r *timeoutMovieRepository) CountByGenre(ctx context.Context, genreId int) (int64, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.CountByGenre(ctx, genreId)
+}
+
+func (r *timeoutMovieRepository) ReplaceGenre(ctx context.Context, fromIDs []int, to models.Genre) (int64, error) {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.ReplaceGenre(ctx, fromIDs, to)
+}
+
+func (r *timeoutMovieRepository) ListByImdbIDs(ctx context.Context, imdbIDs []string) ([]models.Movie, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.ListByImdbIDs(ctx, imdbIDs)
+}
+
+func (r *timeoutMovieRepository) ListByPerson(ctx context.Context, personId string) ([]models.Movie, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.ListByPerson(ctx, personId)
+}
+
+func (r *timeoutMovieRepository) UpdateCredits(ctx context.Context, imdbID string, credits []models.Credit) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.UpdateCredits(ctx, imdbID, credits)
+}
+
+func (r *timeoutMovieRepository) UpdateMetadata(ctx context.Context, imdbID string, movie models.Movie) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.UpdateMetadata(ctx, imdbID, movie)
+}
+
+func (r *timeoutMovieRepository) ListStale(ctx context.Context, before time.Time, limit int) ([]models.Movie, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.ListStale(ctx, before, limit)
+}
+
+func (r *timeoutMovieRepository) UpdatePoster(ctx context.Context, imdbID, posterPath string) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.UpdatePoster(ctx, imdbID, posterPath)
+}
+
+// ---------- GENRES ----------
+
+type timeoutGenreRepository struct {
+	next GenreRepository
+	t    Timeouts
+}
+
+func (r *timeoutGenreRepository) List(ctx context.Context) ([]models.Genre, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.List(ctx)
+}
+
+func (r *timeoutGenreRepository) FindByID(ctx context.Context, genreId int) (models.Genre, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.FindByID(ctx, genreId)
+}
+
+func (r *timeoutGenreRepository) Create(ctx context.Context, genre models.Genre) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Create(ctx, genre)
+}
+
+func (r *timeoutGenreRepository) Rename(ctx context.Context, genreId int, name string) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Rename(ctx, genreId, name)
+}
+
+func (r *timeoutGenreRepository) Delete(ctx context.Context, genreId int) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Delete(ctx, genreId)
+}
+
+func (r *timeoutGenreRepository) Upsert(ctx context.Context, genre models.Genre) (bool, error) {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Upsert(ctx, genre)
+}
+
+// ---------- PEOPLE ----------
+
+type timeoutPersonRepository struct {
+	next PersonRepository
+	t    Timeouts
+}
+
+func (r *timeoutPersonRepository) Create(ctx context.Context, person models.Person) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Create(ctx, person)
+}
+
+func (r *timeoutPersonRepository) FindByID(ctx context.Context, personId string) (models.Person, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.FindByID(ctx, personId)
+}
+
+func (r *timeoutPersonRepository) FindByIDs(ctx context.Context, personIds []string) ([]models.Person, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.FindByIDs(ctx, personIds)
+}
+
+func (r *timeoutPersonRepository) Search(ctx context.Context, query string, limit int) ([]models.Person, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.Search(ctx, query, limit)
+}
+
+func (r *timeoutPersonRepository) Update(ctx context.Context, person models.Person) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Update(ctx, person)
+}
+
+func (r *timeoutPersonRepository) Delete(ctx context.Context, personId string) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Delete(ctx, personId)
+}
+
+// ---------- COLLECTIONS ----------
+
+type timeoutCollectionRepository struct {
+	next CollectionRepository
+	t    Timeouts
+}
+
+func (r *timeoutCollectionRepository) List(ctx context.Context) ([]models.Collection, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.List(ctx)
+}
+
+func (r *timeoutCollectionRepository) FindByID(ctx context.Context, collectionId string) (models.Collection, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.FindByID(ctx, collectionId)
+}
+
+func (r *timeoutCollectionRepository) FindByMovie(ctx context.Context, imdbID string) (models.Collection, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.FindByMovie(ctx, imdbID)
+}
+
+func (r *timeoutCollectionRepository) Create(ctx context.Context, collection models.Collection) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Create(ctx, collection)
+}
+
+func (r *timeoutCollectionRepository) Update(ctx context.Context, collection models.Collection) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Update(ctx, collection)
+}
+
+func (r *timeoutCollectionRepository) Delete(ctx context.Context, collectionId string) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Delete(ctx, collectionId)
+}
+
+// ---------- USERS ----------
+
+type timeoutUserRepository struct {
+	next UserRepository
+	t    Timeouts
+}
+
+func (r *timeoutUserRepository) Create(ctx context.Context, user models.User) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.Create(ctx, user)
+}
+
+func (r *timeoutUserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.EmailExists(ctx, email)
+}
+
+func (r *timeoutUserRepository) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.FindByEmail(ctx, email)
+}
+
+func (r *timeoutUserRepository) FindByID(ctx context.Context, userId string) (models.User, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.FindByID(ctx, userId)
+}
+
+func (r *timeoutUserRepository) FavouriteGenreNames(ctx context.Context, userId string) ([]string, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.FavouriteGenreNames(ctx, userId)
+}
+
+func (r *timeoutUserRepository) UpdatePassword(ctx context.Context, userId, passwordHash string) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.UpdatePassword(ctx, userId, passwordHash)
+}
+
+func (r *timeoutUserRepository) UpdateRole(ctx context.Context, userId, role string) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.UpdateRole(ctx, userId, role)
+}
+
+func (r *timeoutUserRepository) CountByRole(ctx context.Context) (map[string]int64, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.CountByRole(ctx)
+}
+
+func (r *timeoutUserRepository) CountByFavouriteGenre(ctx context.Context, genreId int) (int64, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.CountByFavouriteGenre(ctx, genreId)
+}
+
+func (r *timeoutUserRepository) ReplaceFavouriteGenre(ctx context.Context, fromIDs []int, to models.Genre) (int64, error) {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.ReplaceFavouriteGenre(ctx, fromIDs, to)
+}
+
+func (r *timeoutUserRepository) SetRefreshTokenHash(ctx context.Context, userId, tokenHash string) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.SetRefreshTokenHash(ctx, userId, tokenHash)
+}
+
+func (r *timeoutUserRepository) RefreshTokenHash(ctx context.Context, userId string) (string, error) {
+	ctx, cancel := r.t.read(ctx)
+	defer cancel()
+	return r.next.RefreshTokenHash(ctx, userId)
+}
+
+func (r *timeoutUserRepository) ClearRefreshTokenHash(ctx context.Context, userId string) error {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.ClearRefreshTokenHash(ctx, userId)
+}
+
+func (r *timeoutUserRepository) ClearAllRefreshTokenHashes(ctx context.Context) (int64, error) {
+	ctx, cancel := r.t.write(ctx)
+	defer cancel()
+	return r.next.ClearAllRefreshTokenHashes(ctx)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	controller "github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/controllers"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/health"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/openapi"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+)
+
+// TestOpenAPIDrift is -check-openapi as a test, so CI catches a route added
+// without its documentation.
+func TestOpenAPIDrift(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, doc := newRouter(controller.NewHandler(repository.NewMemoryStore()), health.NewChecker(time.Second))
+
+	if drift := openapi.Drift(doc, router.Routes()); len(drift) > 0 {
+		t.Fatalf("OpenAPI document out of sync with routes:\n%v", drift)
+	}
+}
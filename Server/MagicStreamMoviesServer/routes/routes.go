@@ -0,0 +1,442 @@
+// Package routes declares the public API once and mounts it both under /api/v1
+// and at the legacy pre-versioning paths, which are kept as deprecated aliases.
+package routes
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	controller "github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/controllers"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/enrichment"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/httpcache"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/importer"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/middleware"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
+)
+
+// V1Prefix is the mount point of the current API version.
+const V1Prefix = "/api/v1"
+
+// Route is one API endpoint.
+type Route struct {
+	Method string
+	// Path is relative to V1Prefix, e.g. "/movies/:imdb_id".
+	Path string
+	// Legacy is the unversioned path the endpoint was served at before /api/v1,
+	// or "" if it has no deprecated alias.
+	Legacy string
+	Auth   bool
+	// Admin additionally requires the ADMIN role; it implies Auth.
+	Admin   bool
+	Summary string
+	Handler gin.HandlerFunc
+
+	// Request and Response are zero values of the JSON body types, used to
+	// generate the OpenAPI document; nil means no body. Status is the success code.
+	Request  any
+	Response any
+	Status   int
+	// RequestMedia and ResponseMedia list the media types of raw, non-JSON bodies
+	// such as uploaded or downloaded files; they are used instead of Request and Response.
+	RequestMedia  []string
+	ResponseMedia []string
+	// Query is the zero value of the struct the handler binds the query string into
+	// (fields tagged form:"name"); nil means the route takes no query parameters.
+	Query any
+	// Errors lists problem statuses the handler returns beyond those implied by the
+	// route's shape (400, 401, 403, 404, 500), e.g. 409 for conflicts.
+	Errors []int
+
+	// Cache sets Cache-Control and, for catalogue reads, ETag/Last-Modified validators.
+	Cache httpcache.Policy
+}
+
+// Table lists every API endpoint.
+func Table(h *controller.Handler) []Route {
+	noStore := httpcache.Policy{CacheControl: httpcache.NoStore}
+	catalogue := func(cacheControl string) httpcache.Policy {
+		return httpcache.Policy{CacheControl: cacheControl, Validate: true, Version: h.CatalogVersion}
+	}
+
+	return []Route{
+		// Authentication. Logout is public so it works with only the refresh cookie.
+		{
+			Method: http.MethodPost, Path: "/auth/register", Legacy: "/register",
+			Summary: "Register a new user",
+			Handler: h.RegisterUser(),
+			Request: models.User{}, Response: controller.UserEnvelope{}, Status: http.StatusCreated,
+			Cache: noStore,
+		},
+		{
+			Method: http.MethodPost, Path: "/auth/login", Legacy: "/login",
+			Summary: "Log in and receive auth cookies",
+			Handler: h.LoginUser(),
+			Request: models.UserLogin{}, Response: controller.UserEnvelope{}, Status: http.StatusOK,
+			Cache: noStore,
+		},
+		{
+			Method: http.MethodPost, Path: "/auth/refresh", Legacy: "/refresh",
+			Summary:  "Rotate the refresh token and issue a new access token",
+			Handler:  h.RefreshToken(),
+			Response: controller.MessageResponse{}, Status: http.StatusOK,
+			Cache: noStore,
+		},
+		{
+			Method: http.MethodPost, Path: "/auth/logout", Legacy: "/logout",
+			Summary:  "Revoke the refresh token and clear cookies",
+			Handler:  h.Logout(),
+			Response: controller.MessageResponse{}, Status: http.StatusOK,
+			Cache: noStore,
+		},
+
+		// Catalogue
+		{
+			Method: http.MethodGet, Path: "/movies", Legacy: "/movies",
+			Summary:  "List movies, optionally filtered by genre, release year, language, certification, director, cast or runtime",
+			Handler:  h.GetMovies(),
+			Query:    controller.MovieListQuery{},
+			Response: []models.Movie{}, Status: http.StatusOK,
+			Cache: catalogue(httpcache.PublicShort),
+		},
+		{
+			Method: http.MethodPost, Path: "/movies", Legacy: "/addmovie", Auth: true,
+			Summary: "Add a movie",
+			Handler: h.AddMovie(),
+			Request: models.Movie{}, Response: controller.MovieCreatedResponse{}, Status: http.StatusCreated,
+			Errors: []int{http.StatusConflict},
+		},
+		{
+			Method: http.MethodGet, Path: "/movies/export", Auth: true, Admin: true,
+			Summary:       "Download movies matching the list filters as CSV, JSON Lines or Excel-friendly CSV (admin only)",
+			Handler:       h.ExportMovies(),
+			Query:         controller.ExportQuery{},
+			ResponseMedia: []string{"text/csv", "application/x-ndjson"},
+			Status:        http.StatusOK,
+			Cache:         noStore,
+		},
+		{
+			Method: http.MethodPost, Path: "/movies/import", Auth: true, Admin: true,
+			Summary:      "Start a bulk import of movies from a CSV or JSON Lines file, upserting by imdb_id (admin only)",
+			Handler:      h.ImportMovies(),
+			Query:        controller.ImportQuery{},
+			RequestMedia: []string{"text/csv", "application/x-ndjson"},
+			Response:     importer.Job{}, Status: http.StatusAccepted,
+			Errors: []int{http.StatusRequestEntityTooLarge},
+			Cache:  noStore,
+		},
+		{
+			Method: http.MethodGet, Path: "/movies/import/:job_id", Auth: true, Admin: true,
+			Summary:  "Get the progress and per-row errors of a movie import (admin only)",
+			Handler:  h.GetImportJob(),
+			Response: importer.Job{}, Status: http.StatusOK,
+			Cache: noStore,
+		},
+		{
+			Method: http.MethodGet, Path: "/movies/:imdb_id", Legacy: "/movie/:imdb_id", Auth: true,
+			Summary:  "Get a movie by imdb_id, with its position in a collection if it belongs to one",
+			Handler:  h.GetMovie(),
+			Response: controller.MovieDetailResponse{}, Status: http.StatusOK,
+			Cache: catalogue(httpcache.PrivateRevalidate),
+		},
+		{
+			Method: http.MethodPatch, Path: "/movies/:imdb_id/review", Legacy: "/updatereview/:imdb_id", Auth: true, Admin: true,
+			Summary: "Update a movie's admin review and ranking (admin only)",
+			Handler: h.AdminReviewUpdate(),
+			Request: controller.ReviewUpdateRequest{}, Response: controller.ReviewUpdatedResponse{}, Status: http.StatusOK,
+		},
+		{
+			Method: http.MethodGet, Path: "/movies/:imdb_id/enrichment", Auth: true, Admin: true,
+			Summary:  "Preview a movie's metadata from the external movie database without saving it (admin only)",
+			Handler:  h.PreviewEnrichment(),
+			Response: enrichment.Preview{}, Status: http.StatusOK,
+			Errors: []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+			Cache:  noStore,
+		},
+		{
+			Method: http.MethodPost, Path: "/movies/:imdb_id/enrichment", Auth: true, Admin: true,
+			Summary:  "Fill in a movie's metadata from the external movie database and save it (admin only)",
+			Handler:  h.EnrichMovie(),
+			Response: enrichment.Preview{}, Status: http.StatusOK,
+			Errors: []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+			Cache:  noStore,
+		},
+		{
+			Method: http.MethodPut, Path: "/movies/:imdb_id/poster", Auth: true, Admin: true,
+			Summary:      "Upload a poster image, with thumbnails and WebP variants, and set it as the movie's poster_path (admin only)",
+			Handler:      h.UpdateMoviePoster(),
+			RequestMedia: []string{"multipart/form-data"},
+			Response:     controller.PosterResponse{}, Status: http.StatusOK,
+			Errors: []int{http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType, http.StatusServiceUnavailable},
+			Cache:  noStore,
+		},
+		{
+			Method: http.MethodGet, Path: "/movies/:imdb_id/trailer", Auth: true,
+			Summary:  "Get a short-lived signed link to stream the movie's self-hosted trailer",
+			Handler:  h.GetTrailerLink(),
+			Response: controller.TrailerLinkResponse{}, Status: http.StatusOK,
+			Errors: []int{http.StatusServiceUnavailable},
+			Cache:  noStore,
+		},
+		{
+			Method: http.MethodPut, Path: "/movies/:imdb_id/trailer", Auth: true, Admin: true,
+			Summary:      "Upload an MP4 or WebM video as the movie's self-hosted trailer, replacing any previous one (admin only)",
+			Handler:      h.UploadTrailer(),
+			RequestMedia: []string{"video/mp4", "video/webm"},
+			Response:     controller.TrailerResponse{}, Status: http.StatusOK,
+			Errors: []int{http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType, http.StatusServiceUnavailable},
+			Cache:  noStore,
+		},
+		{
+			Method: http.MethodDelete, Path: "/movies/:imdb_id/trailer", Auth: true, Admin: true,
+			Summary: "Delete the movie's self-hosted trailer (admin only)",
+			Handler: h.DeleteTrailer(),
+			Status:  http.StatusNoContent,
+			Errors:  []int{http.StatusServiceUnavailable},
+			Cache:   noStore,
+		},
+		// Media
+		{
+			Method: http.MethodGet, Path: "/media/trailers/:imdb_id",
+			Summary:       "Stream a trailer, with Range support, using a signed link from GET /movies/{imdb_id}/trailer",
+			Handler:       h.StreamTrailer(),
+			Query:         controller.SignedMediaQuery{},
+			ResponseMedia: []string{"video/mp4", "video/webm"},
+			Status:        http.StatusOK,
+			Errors:        []int{http.StatusForbidden, http.StatusServiceUnavailable},
+		},
+		// Images
+		{
+			Method: http.MethodPost, Path: "/images/posters", Auth: true, Admin: true,
+			Summary:      "Upload a poster image and generate its thumbnails and WebP variants (admin only)",
+			Handler:      h.UploadPoster(),
+			RequestMedia: []string{"multipart/form-data"},
+			Response:     controller.PosterResponse{}, Status: http.StatusCreated,
+			Errors: []int{http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType, http.StatusServiceUnavailable},
+			Cache:  noStore,
+		},
+		{
+			Method: http.MethodGet, Path: "/images/posters/:poster_id/:file",
+			Summary:       "Download a stored poster image; variants never change and may be cached indefinitely",
+			Handler:       h.GetPosterImage(),
+			ResponseMedia: []string{"image/jpeg", "image/png", "image/gif", "image/webp"},
+			Status:        http.StatusOK,
+		},
+		{
+			Method: http.MethodGet, Path: "/genres", Legacy: "/genres",
+			Summary:  "List all genres",
+			Handler:  h.GetGenres(),
+			Response: []models.Genre{}, Status: http.StatusOK,
+			Cache: catalogue(httpcache.PublicLong),
+		},
+		{
+			Method: http.MethodPost, Path: "/genres", Auth: true, Admin: true,
+			Summary: "Create a genre (admin only)",
+			Handler: h.CreateGenre(),
+			Request: models.Genre{}, Response: models.Genre{}, Status: http.StatusCreated,
+			Errors: []int{http.StatusConflict},
+			Cache:  noStore,
+		},
+		{
+			Method: http.MethodGet, Path: "/genres/:genre_id",
+			Summary:  "Get a genre by genre_id",
+			Handler:  h.GetGenre(),
+			Response: models.Genre{}, Status: http.StatusOK,
+			Cache: catalogue(httpcache.PublicLong),
+		},
+		{
+			Method: http.MethodPatch, Path: "/genres/:genre_id", Auth: true, Admin: true,
+			Summary: "Rename a genre and every copy embedded in movies and users (admin only)",
+			Handler: h.RenameGenre(),
+			Request: controller.GenreRenameRequest{}, Response: controller.GenreChangeResponse{}, Status: http.StatusOK,
+			Errors: []int{http.StatusConflict},
+			Cache:  noStore,
+		},
+		{
+			Method: http.MethodDelete, Path: "/genres/:genre_id", Auth: true, Admin: true,
+			Summary: "Delete a genre no movie or user references (admin only)",
+			Handler: h.DeleteGenre(),
+			Status:  http.StatusNoContent,
+			Errors:  []int{http.StatusConflict},
+			Cache:   noStore,
+		},
+		{
+			Method: http.MethodPost, Path: "/genres/:genre_id/merge", Auth: true, Admin: true,
+			Summary: "Merge a genre into another, rewriting movies and users, then delete it (admin only)",
+			Handler: h.MergeGenre(),
+			Request: controller.GenreMergeRequest{}, Response: controller.GenreChangeResponse{}, Status: http.StatusOK,
+			Cache: noStore,
+		},
+
+		{
+			Method: http.MethodPut, Path: "/movies/:imdb_id/credits", Auth: true, Admin: true,
+			Summary: "Replace a movie's credits; directors and cast are rebuilt from them (admin only)",
+			Handler: h.UpdateMovieCredits(),
+			Request: controller.CreditsUpdateRequest{}, Response: models.Movie{}, Status: http.StatusOK,
+			Cache: noStore,
+		},
+		// People
+		{
+			Method: http.MethodGet, Path: "/people",
+			Summary:  "Search people by name",
+			Handler:  h.SearchPeople(),
+			Query:    controller.PeopleQuery{},
+			Response: []models.Person{}, Status: http.StatusOK,
+			Cache: catalogue(httpcache.PublicShort),
+		},
+		{
+			Method: http.MethodPost, Path: "/people", Auth: true, Admin: true,
+			Summary: "Create a person (admin only)",
+			Handler: h.CreatePerson(),
+			Request: models.Person{}, Response: models.Person{}, Status: http.StatusCreated,
+			Cache: noStore,
+		},
+		{
+			Method: http.MethodGet, Path: "/people/:person_id",
+			Summary:  "Get a person and their filmography",
+			Handler:  h.GetPerson(),
+			Response: controller.PersonResponse{}, Status: http.StatusOK,
+			Cache: catalogue(httpcache.PublicShort),
+		},
+		{
+			Method: http.MethodPatch, Path: "/people/:person_id", Auth: true, Admin: true,
+			Summary: "Update a person; a new name is copied into their movie credits (admin only)",
+			Handler: h.UpdatePerson(),
+			Request: controller.PersonUpdateRequest{}, Response: controller.PersonUpdatedResponse{}, Status: http.StatusOK,
+			Cache: noStore,
+		},
+		{
+			Method: http.MethodDelete, Path: "/people/:person_id", Auth: true, Admin: true,
+			Summary: "Delete a person with no movie credits (admin only)",
+			Handler: h.DeletePerson(),
+			Status:  http.StatusNoContent,
+			Errors:  []int{http.StatusConflict},
+			Cache:   noStore,
+		},
+		// Collections
+		{
+			Method: http.MethodGet, Path: "/collections",
+			Summary:  "List movie collections",
+			Handler:  h.GetCollections(),
+			Response: []models.Collection{}, Status: http.StatusOK,
+			Cache: catalogue(httpcache.PublicLong),
+		},
+		{
+			Method: http.MethodPost, Path: "/collections", Auth: true, Admin: true,
+			Summary: "Create a collection of movies in order (admin only)",
+			Handler: h.CreateCollection(),
+			Request: models.Collection{}, Response: models.Collection{}, Status: http.StatusCreated,
+			Errors: []int{http.StatusConflict},
+			Cache:  noStore,
+		},
+		{
+			Method: http.MethodGet, Path: "/collections/:collection_id",
+			Summary:  "Get a collection and its movies in order",
+			Handler:  h.GetCollection(),
+			Response: controller.CollectionResponse{}, Status: http.StatusOK,
+			Cache: catalogue(httpcache.PublicShort),
+		},
+		{
+			Method: http.MethodPatch, Path: "/collections/:collection_id", Auth: true, Admin: true,
+			Summary: "Update a collection's name, overview or ordered movies (admin only)",
+			Handler: h.UpdateCollection(),
+			Request: controller.CollectionUpdateRequest{}, Response: models.Collection{}, Status: http.StatusOK,
+			Errors: []int{http.StatusConflict},
+			Cache:  noStore,
+		},
+		{
+			Method: http.MethodDelete, Path: "/collections/:collection_id", Auth: true, Admin: true,
+			Summary: "Delete a collection, keeping its movies (admin only)",
+			Handler: h.DeleteCollection(),
+			Status:  http.StatusNoContent,
+			Cache:   noStore,
+		},
+		// Current user
+		{
+			Method: http.MethodGet, Path: "/me", Legacy: "/profile", Auth: true,
+			Summary:  "Get the current user's profile",
+			Handler:  h.GetProfile(),
+			Response: controller.UserEnvelope{}, Status: http.StatusOK,
+			Cache: noStore,
+		},
+		{
+			Method: http.MethodGet, Path: "/me/recommendations", Legacy: "/recommendedmovies", Auth: true,
+			Summary:  "Movies matching the current user's favourite genres",
+			Handler:  h.GetRecommendedMovies(),
+			Response: []models.Movie{}, Status: http.StatusOK,
+			Cache: noStore,
+		},
+	}
+}
+
+// Register mounts routes under V1Prefix and, for routes that have one, at their
+// legacy path with Deprecation, Sunset and successor Link headers.
+func Register(router *gin.Engine, routes []Route, sunset time.Time) {
+	v1 := router.Group(V1Prefix)
+	for _, r := range routes {
+		v1.Handle(r.Method, r.Path, chain(r)...)
+	}
+
+	for _, r := range routes {
+		if r.Legacy == "" {
+			continue
+		}
+		handlers := append([]gin.HandlerFunc{deprecated(V1Prefix+r.Path, sunset)}, chain(r)...)
+		router.Handle(r.Method, r.Legacy, handlers...)
+	}
+}
+
+// chain orders a route's handlers: authentication and role checks, then caching
+// (so validators are only revealed to authorised clients), then the handler itself.
+func chain(r Route) []gin.HandlerFunc {
+	var handlers []gin.HandlerFunc
+	if r.Auth || r.Admin {
+		handlers = append(handlers, middleware.AuthMiddleware())
+	}
+	if r.Admin {
+		handlers = append(handlers, middleware.RequireAdmin())
+	}
+	if r.Cache != (httpcache.Policy{}) {
+		handlers = append(handlers, httpcache.Middleware(r.Cache))
+	}
+	return append(handlers, r.Handler)
+}
+
+// DeprecatedSince is when the unversioned routes were deprecated in favour of /api/v1.
+var DeprecatedSince = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+// LegacySunset reads LEGACY_ROUTES_SUNSET (YYYY-MM-DD or RFC 3339), the date after
+// which the unversioned routes may be removed. Defaults to 2027-04-30.
+func LegacySunset() time.Time {
+	value := os.Getenv("LEGACY_ROUTES_SUNSET")
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t
+	}
+	return time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+}
+
+// deprecated sets the RFC 9745 Deprecation and RFC 8594 Sunset headers, plus a Link
+// to the successor with path parameters filled in from the request.
+func deprecated(successor string, sunset time.Time) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(DeprecatedSince.Unix(), 10)
+	sunsetHeader := sunset.UTC().Format(http.TimeFormat)
+
+	return func(c *gin.Context) {
+		link := successor
+		for _, p := range c.Params {
+			link = strings.Replace(link, ":"+p.Key, url.PathEscape(p.Value), 1)
+		}
+
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetHeader)
+		c.Header("Link", "<"+link+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
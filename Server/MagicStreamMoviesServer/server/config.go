@@ -0,0 +1,76 @@
+package server
+
+import (
+	"os"
+	"time"
+)
+
+// Config controls how the HTTP server listens and shuts down.
+type Config struct {
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownDelay is how long the server keeps accepting requests after a signal,
+	// while reporting not-ready, so load balancers stop routing to it first.
+	ShutdownDelay time.Duration
+	// ShutdownTimeout bounds how long in-flight requests may drain after that.
+	ShutdownTimeout time.Duration
+
+	// TLS is enabled when both files are set. They are re-read when they change on disk
+	// or when the process receives SIGHUP, so certificates can be rotated without a restart.
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+// LoadConfig reads the server configuration from the environment:
+//
+//	SERVER_ADDR              listen address (default ":8080")
+//	SERVER_READ_TIMEOUT      e.g. "15s" (default 15s)
+//	SERVER_READ_HEADER_TIMEOUT (default 5s)
+//	SERVER_WRITE_TIMEOUT     (default 30s)
+//	SERVER_IDLE_TIMEOUT      (default 60s)
+//	SERVER_SHUTDOWN_DELAY    not-ready period before draining; "0" disables (default 5s)
+//	SERVER_SHUTDOWN_TIMEOUT  (default 20s)
+//	TLS_CERT_FILE, TLS_KEY_FILE
+func LoadConfig() Config {
+	addr := os.Getenv("SERVER_ADDR")
+	if addr == "" {
+		addr = ":8080"
+	}
+
+	return Config{
+		Addr:              addr,
+		ReadTimeout:       envDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+		ReadHeaderTimeout: envDuration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+		WriteTimeout:      envDuration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:       envDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+		ShutdownDelay:     shutdownDelay(),
+		ShutdownTimeout:   envDuration("SERVER_SHUTDOWN_TIMEOUT", 20*time.Second),
+		TLSCertFile:       os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:        os.Getenv("TLS_KEY_FILE"),
+	}
+}
+
+// TLSEnabled reports whether both a certificate and a key were configured.
+func (c Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// shutdownDelay reads SERVER_SHUTDOWN_DELAY, which unlike the timeouts may be zero.
+func shutdownDelay() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("SERVER_SHUTDOWN_DELAY"))
+	if err != nil || d < 0 {
+		return 5 * time.Second
+	}
+	return d
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// certReloader serves the current certificate and reloads it when the files change.
+type certReloader struct {
+	certFile, keyFile string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+// certCheckInterval limits how often handshakes stat the certificate files.
+const certCheckInterval = 10 * time.Second
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := r.reload(); err != nil {
+				slog.Warn("TLS certificate reload failed", "error", err)
+				continue
+			}
+			slog.Info("TLS certificate reloaded", "cert_file", r.certFile)
+		}
+	}()
+
+	return r, nil
+}
+
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load TLS key pair: %w", err)
+	}
+	modTime, _ := r.latestModTime()
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.modTime = modTime
+	r.lastCheck = time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, f := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(f)
+		if err != nil {
+			return latest, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+// A failed reload keeps serving the previous certificate.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	cert, modTime, due := r.cert, r.modTime, time.Since(r.lastCheck) > certCheckInterval
+	r.mu.RUnlock()
+
+	if due {
+		latest, err := r.latestModTime()
+		if err == nil && latest.After(modTime) {
+			if err := r.reload(); err != nil {
+				slog.Warn("TLS certificate reload failed", "error", err)
+			} else {
+				r.mu.RLock()
+				cert = r.cert
+				r.mu.RUnlock()
+			}
+		} else {
+			r.mu.Lock()
+			r.lastCheck = time.Now()
+			r.mu.Unlock()
+		}
+	}
+
+	return cert, nil
+}
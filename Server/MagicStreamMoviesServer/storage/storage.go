@@ -0,0 +1,140 @@
+// Package storage opens the persistence backend selected by configuration.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/database"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/migrations"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository/sqlstore"
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Supported values for STORAGE_BACKEND.
+const (
+	BackendMongo    = "mongo"
+	BackendSQLite   = "sqlite"
+	BackendPostgres = "postgres"
+)
+
+// Backend is an opened storage backend and the repositories it provides.
+// Exactly one of Mongo or SQL is set, depending on Name.
+type Backend struct {
+	repository.Store
+
+	Name  string
+	Mongo *mongo.Client
+	SQL   *sqlstore.DB
+}
+
+// LoadEnv loads .env from the working directory, if present, without overriding
+// variables already set in the process environment. It reports whether the file was found.
+func LoadEnv() bool {
+	return godotenv.Load(".env") == nil
+}
+
+// Open connects to the backend named by STORAGE_BACKEND (default "mongo").
+// SQL backends read their connection string from SQL_DSN.
+// Schema migrations are not applied here; see Migrate.
+func Open(ctx context.Context) (*Backend, error) {
+	if !LoadEnv() {
+		slog.Debug("no .env file found, using process environment")
+	}
+
+	name := strings.ToLower(os.Getenv("STORAGE_BACKEND"))
+	if name == "" {
+		name = BackendMongo
+	}
+
+	switch name {
+	case BackendMongo:
+		client := database.DBInstance()
+		if err := client.Ping(ctx, nil); err != nil {
+			return nil, fmt.Errorf("failed to reach MongoDB server: %w", err)
+		}
+		return &Backend{Store: repository.NewMongoStore(client), Name: name, Mongo: client}, nil
+
+	case BackendSQLite, BackendPostgres:
+		dsn := os.Getenv("SQL_DSN")
+		if dsn == "" {
+			if name != BackendSQLite {
+				return nil, fmt.Errorf("SQL_DSN not set for %s backend", name)
+			}
+			dsn = "file:magicstream.db"
+		}
+		db, err := sqlstore.Open(sqlstore.Dialect(name), dsn)
+		if err != nil {
+			return nil, err
+		}
+		if err := db.PingContext(ctx); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to reach %s database: %w", name, err)
+		}
+		return &Backend{Store: sqlstore.NewStore(db), Name: name, SQL: db}, nil
+	}
+
+	return nil, fmt.Errorf("unknown STORAGE_BACKEND %q (want mongo, sqlite or postgres)", name)
+}
+
+// Ping checks that the backend is reachable.
+func (b *Backend) Ping(ctx context.Context) error {
+	if b.Mongo != nil {
+		return b.Mongo.Ping(ctx, nil)
+	}
+	return b.SQL.PingContext(ctx)
+}
+
+// Close releases the backend's connections.
+func (b *Backend) Close(ctx context.Context) error {
+	if b.Mongo != nil {
+		return b.Mongo.Disconnect(ctx)
+	}
+	return b.SQL.Close()
+}
+
+// Migrate applies pending migrations and returns the names of the ones it ran.
+// MongoDB uses the migrations package; SQL backends use their embedded schema files.
+func (b *Backend) Migrate(ctx context.Context) ([]string, error) {
+	if b.SQL != nil {
+		return b.SQL.Migrate(ctx)
+	}
+	ran, err := migrations.New(database.OpenDatabase(b.Mongo)).Up(ctx)
+	return migrationNames(ran), err
+}
+
+// PendingMigrations returns the names of migrations that have not been applied.
+func (b *Backend) PendingMigrations(ctx context.Context) ([]string, error) {
+	if b.SQL != nil {
+		return b.SQL.PendingMigrations(ctx)
+	}
+	pending, err := migrations.New(database.OpenDatabase(b.Mongo)).Pending(ctx)
+	return migrationNames(pending), err
+}
+
+// RollbackMigration reverts the latest applied migration and returns its name
+// ("" if none was applied). Only MongoDB migrations are reversible.
+func (b *Backend) RollbackMigration(ctx context.Context) (string, error) {
+	if b.SQL != nil {
+		return "", errors.New("SQL schema migrations cannot be rolled back")
+	}
+	reverted, err := migrations.New(database.OpenDatabase(b.Mongo)).Down(ctx)
+	if err != nil || reverted == nil {
+		return "", err
+	}
+	return migrationNames([]migrations.Migration{*reverted})[0], nil
+}
+
+func migrationNames(list []migrations.Migration) []string {
+	names := make([]string, 0, len(list))
+	for _, m := range list {
+		names = append(names, fmt.Sprintf("%04d_%s", m.Version, m.Name))
+	}
+	return names
+}
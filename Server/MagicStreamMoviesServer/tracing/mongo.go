@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type commandKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// MongoCommandMonitor returns a driver CommandMonitor that wraps every command in a client span.
+// The span's parent is taken from the context passed to the driver call, so handlers must
+// derive their contexts from c.Request.Context() for the spans to nest under the request.
+func MongoCommandMonitor() *event.CommandMonitor {
+	tracer := otel.Tracer(instrumentationName)
+	var spans sync.Map // commandKey -> trace.Span
+
+	end := func(connectionID string, requestID int64, err error) {
+		v, ok := spans.LoadAndDelete(commandKey{connectionID, requestID})
+		if !ok {
+			return
+		}
+		span := v.(trace.Span)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			attrs := []attribute.KeyValue{
+				semconv.DBSystemMongoDB,
+				semconv.DBOperationName(e.CommandName),
+				semconv.DBNamespace(e.DatabaseName),
+			}
+			collection := ""
+			if elem, err := e.Command.IndexErr(0); err == nil {
+				if name, ok := elem.Value().StringValueOK(); ok {
+					collection = name
+					attrs = append(attrs, semconv.DBCollectionName(name))
+				}
+			}
+
+			name := e.CommandName
+			if collection != "" {
+				name = collection + "." + e.CommandName
+			}
+			_, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+			spans.Store(commandKey{e.ConnectionID, e.RequestID}, span)
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			end(e.ConnectionID, e.RequestID, nil)
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			end(e.ConnectionID, e.RequestID, e.Failure)
+		},
+	}
+}
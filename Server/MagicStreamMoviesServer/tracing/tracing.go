@@ -0,0 +1,82 @@
+// Package tracing configures OpenTelemetry tracing for HTTP handlers and MongoDB calls.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const instrumentationName = "github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/tracing"
+
+// ServiceName is reported on every span; override with OTEL_SERVICE_NAME.
+func ServiceName() string {
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		return name
+	}
+	return "magicstream-api"
+}
+
+// Init installs the global tracer provider selected by OTEL_TRACES_EXPORTER:
+//
+//	none (default)  tracing disabled, spans are no-ops
+//	otlp            OTLP/HTTP; endpoint and headers come from the standard
+//	                OTEL_EXPORTER_OTLP_* variables (default http://localhost:4318)
+//	console/stdout  pretty-printed spans on stdout, for local debugging
+//
+// The returned function flushes buffered spans and must be called on shutdown.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER")) {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx)
+	case "console", "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q (want none, otlp or console)", os.Getenv("OTEL_TRACES_EXPORTER"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(ServiceName()),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	// The sampler honours OTEL_TRACES_SAMPLER / OTEL_TRACES_SAMPLER_ARG.
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Middleware starts a server span per request named after the route template
+// and stores it in c.Request.Context(), so handlers pass it down to MongoDB.
+func Middleware() gin.HandlerFunc {
+	return otelgin.Middleware(ServiceName())
+}
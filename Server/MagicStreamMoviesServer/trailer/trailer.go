@@ -0,0 +1,118 @@
+// Package trailer stores self-hosted movie trailers in a blobstore.BlobStore. A
+// movie has at most one trailer, kept under trailers/<imdb_id>/ with the file
+// extension of its format.
+package trailer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/blobstore"
+)
+
+// DefaultMaxUploadBytes caps an uploaded trailer unless TRAILER_MAX_BYTES says otherwise.
+const DefaultMaxUploadBytes = 500 << 20
+
+// StreamPath is where the API streams a trailer, followed by the movie's imdb_id.
+// Links to it must be signed with Resource.
+const StreamPath = "/api/v1/media/trailers/"
+
+// ErrNotFound is returned when the movie has no trailer.
+var ErrNotFound = errors.New("trailer not found")
+
+// formats lists the supported content types and their extensions, in lookup order.
+var formats = []struct {
+	contentType, ext string
+}{
+	{"video/mp4", "mp4"},
+	{"video/webm", "webm"},
+}
+
+// MaxUploadBytes returns TRAILER_MAX_BYTES if it is a positive integer, else DefaultMaxUploadBytes.
+func MaxUploadBytes() int64 {
+	n, err := strconv.ParseInt(os.Getenv("TRAILER_MAX_BYTES"), 10, 64)
+	if err != nil || n <= 0 {
+		return DefaultMaxUploadBytes
+	}
+	return n
+}
+
+// Supported reports whether contentType is a format trailers can be stored in.
+func Supported(contentType string) bool {
+	for _, f := range formats {
+		if f.contentType == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// Resource names the movie's trailer in signed links.
+func Resource(imdbID string) string {
+	return "trailers/" + imdbID
+}
+
+func key(imdbID, ext string) string {
+	return "trailers/" + imdbID + "/trailer." + ext
+}
+
+// Trailer is a stored trailer, open for reading. The caller closes it.
+type Trailer struct {
+	*blobstore.Object
+	// ContentType comes from the stored format, as the filesystem backend keeps none.
+	ContentType string
+}
+
+// Open returns the movie's trailer, or ErrNotFound.
+func Open(ctx context.Context, blobs blobstore.BlobStore, imdbID string) (*Trailer, error) {
+	for _, f := range formats {
+		obj, err := blobs.Get(ctx, key(imdbID, f.ext))
+		if errors.Is(err, blobstore.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &Trailer{Object: obj, ContentType: f.contentType}, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Save stores size bytes of r, which must be of a Supported contentType, as the
+// movie's trailer, replacing any trailer in another format.
+func Save(ctx context.Context, blobs blobstore.BlobStore, imdbID string, r io.Reader, size int64, contentType string) error {
+	for _, f := range formats {
+		if f.contentType == contentType {
+			if err := blobs.Put(ctx, key(imdbID, f.ext), r, size, contentType); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range formats {
+		if f.contentType != contentType {
+			if err := blobs.Delete(ctx, key(imdbID, f.ext)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Delete removes the movie's trailer, returning ErrNotFound if it has none.
+func Delete(ctx context.Context, blobs blobstore.BlobStore, imdbID string) error {
+	t, err := Open(ctx, blobs, imdbID)
+	if err != nil {
+		return err
+	}
+	t.Close()
+
+	for _, f := range formats {
+		if err := blobs.Delete(ctx, key(imdbID, f.ext)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
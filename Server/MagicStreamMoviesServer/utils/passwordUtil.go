@@ -0,0 +1,244 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Supported password hashing algorithms.
+// The stored hash always carries its algorithm prefix ("$2a$"/"$2b$" for bcrypt,
+// "$argon2id$" for argon2id) so old hashes keep verifying after the config changes.
+const (
+	PasswordAlgoBcrypt   = "bcrypt"
+	PasswordAlgoArgon2id = "argon2id"
+)
+
+const (
+	defaultBcryptCost = 12
+
+	defaultArgon2Memory      = 64 * 1024 // KiB
+	defaultArgon2Iterations  = 3
+	defaultArgon2Parallelism = 2
+	argon2SaltLength         = 16
+	argon2KeyLength          = 32
+)
+
+var errInvalidPasswordHash = errors.New("invalid password hash format")
+
+// Argon2Params holds the tunable argon2id cost parameters.
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// PasswordHashConfig describes how new password hashes are produced.
+type PasswordHashConfig struct {
+	Algorithm  string
+	BcryptCost int
+	Argon2     Argon2Params
+}
+
+// defaultPasswordHashConfig is used until SetPasswordHashConfig is called.
+var defaultPasswordHashConfig = PasswordHashConfig{
+	Algorithm:  PasswordAlgoBcrypt,
+	BcryptCost: defaultBcryptCost,
+	Argon2: Argon2Params{
+		Memory:      defaultArgon2Memory,
+		Iterations:  defaultArgon2Iterations,
+		Parallelism: defaultArgon2Parallelism,
+	},
+}
+
+var passwordHashConfig = defaultPasswordHashConfig
+
+// SetPasswordHashConfig sets the configuration HashPassword and PasswordNeedsRehash
+// use. Call it once at startup, before any password is hashed.
+func SetPasswordHashConfig(cfg PasswordHashConfig) {
+	passwordHashConfig = cfg
+}
+
+// LoadPasswordHashConfig reads the hashing configuration from the environment:
+//
+//	PASSWORD_HASH_ALGORITHM  bcrypt (default) or argon2id
+//	BCRYPT_COST              bcrypt cost, 4-31 (default 12)
+//	ARGON2_MEMORY_KIB        argon2id memory in KiB (default 65536)
+//	ARGON2_ITERATIONS        argon2id iterations (default 3)
+//	ARGON2_PARALLELISM       argon2id threads, 1-255 (default 2)
+//
+// It returns an error for an unknown algorithm or a value that is not an integer
+// or out of range, rather than hashing with something other than what was asked.
+func LoadPasswordHashConfig() (PasswordHashConfig, error) {
+	cfg := defaultPasswordHashConfig
+
+	switch algorithm := strings.ToLower(os.Getenv("PASSWORD_HASH_ALGORITHM")); algorithm {
+	case "", PasswordAlgoBcrypt:
+	case PasswordAlgoArgon2id:
+		cfg.Algorithm = algorithm
+	default:
+		return PasswordHashConfig{}, fmt.Errorf("PASSWORD_HASH_ALGORITHM: unknown algorithm %q", algorithm)
+	}
+
+	cost, err := envRange("BCRYPT_COST", defaultBcryptCost, int64(bcrypt.MinCost), int64(bcrypt.MaxCost))
+	if err != nil {
+		return PasswordHashConfig{}, err
+	}
+	memory, err := envRange("ARGON2_MEMORY_KIB", defaultArgon2Memory, 1, math.MaxUint32)
+	if err != nil {
+		return PasswordHashConfig{}, err
+	}
+	iterations, err := envRange("ARGON2_ITERATIONS", defaultArgon2Iterations, 1, math.MaxUint32)
+	if err != nil {
+		return PasswordHashConfig{}, err
+	}
+	parallelism, err := envRange("ARGON2_PARALLELISM", defaultArgon2Parallelism, 1, math.MaxUint8)
+	if err != nil {
+		return PasswordHashConfig{}, err
+	}
+
+	cfg.BcryptCost = int(cost)
+	cfg.Argon2 = Argon2Params{Memory: uint32(memory), Iterations: uint32(iterations), Parallelism: uint8(parallelism)}
+	return cfg, nil
+}
+
+// envRange parses the integer in key, or returns fallback when it is unset.
+func envRange(key string, fallback, min, max int64) (int64, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %q is not an integer", key, raw)
+	}
+	if value < min || value > max {
+		return 0, fmt.Errorf("%s: %d is out of range %d-%d", key, value, min, max)
+	}
+	return value, nil
+}
+
+// HashPassword hashes a plain text password with the configured algorithm and cost.
+func HashPassword(password string) (string, error) {
+	cfg := passwordHashConfig
+
+	if cfg.Algorithm == PasswordAlgoArgon2id {
+		return hashArgon2id(password, cfg.Argon2)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), cfg.BcryptCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedPassword), nil
+}
+
+// VerifyPassword reports whether password matches the stored hash.
+// The algorithm is picked from the hash prefix, not from the current config.
+func VerifyPassword(encodedHash, password string) (bool, error) {
+	if strings.HasPrefix(encodedHash, "$argon2id$") {
+		params, salt, key, err := decodeArgon2id(encodedHash)
+		if err != nil {
+			return false, err
+		}
+		candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, candidate) == 1, nil
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PasswordNeedsRehash reports whether a stored hash was produced with a different
+// algorithm or weaker parameters than the current config asks for.
+// Callers should only act on it after a successful VerifyPassword.
+func PasswordNeedsRehash(encodedHash string) bool {
+	cfg := passwordHashConfig
+
+	if strings.HasPrefix(encodedHash, "$argon2id$") {
+		if cfg.Algorithm != PasswordAlgoArgon2id {
+			return true
+		}
+		params, _, _, err := decodeArgon2id(encodedHash)
+		if err != nil {
+			return true
+		}
+		return params.Memory < cfg.Argon2.Memory ||
+			params.Iterations < cfg.Argon2.Iterations ||
+			params.Parallelism < cfg.Argon2.Parallelism
+	}
+
+	if cfg.Algorithm != PasswordAlgoBcrypt {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(encodedHash))
+	if err != nil {
+		return true
+	}
+	return cost < cfg.BcryptCost
+}
+
+// hashArgon2id encodes the hash in the PHC string format:
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+func hashArgon2id(password string, params Argon2Params) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func decodeArgon2id(encodedHash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[1] != PasswordAlgoArgon2id {
+		return params, nil, nil, errInvalidPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, errInvalidPasswordHash
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, errInvalidPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, errInvalidPasswordHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errInvalidPasswordHash
+	}
+
+	return params, salt, key, nil
+}
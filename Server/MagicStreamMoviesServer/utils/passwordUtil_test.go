@@ -0,0 +1,175 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/utils"
+)
+
+// useHashConfig loads the hashing configuration from env, with cheap defaults so
+// the tests stay fast, and makes it current.
+func useHashConfig(t *testing.T, env map[string]string) {
+	t.Helper()
+	cheap := map[string]string{
+		"PASSWORD_HASH_ALGORITHM": "bcrypt",
+		"BCRYPT_COST":             "4",
+		"ARGON2_MEMORY_KIB":       "64",
+		"ARGON2_ITERATIONS":       "1",
+		"ARGON2_PARALLELISM":      "1",
+	}
+	for k, v := range env {
+		cheap[k] = v
+	}
+	for k, v := range cheap {
+		t.Setenv(k, v)
+	}
+	cfg, err := utils.LoadPasswordHashConfig()
+	if err != nil {
+		t.Fatalf("LoadPasswordHashConfig: %v", err)
+	}
+	utils.SetPasswordHashConfig(cfg)
+}
+
+func hash(t *testing.T, password string) string {
+	t.Helper()
+	encoded, err := utils.HashPassword(password)
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	return encoded
+}
+
+func verify(t *testing.T, encoded, password string) bool {
+	t.Helper()
+	ok, err := utils.VerifyPassword(encoded, password)
+	if err != nil {
+		t.Fatalf("VerifyPassword: %v", err)
+	}
+	return ok
+}
+
+func TestPasswordRoundTrip(t *testing.T) {
+	for _, algorithm := range []string{utils.PasswordAlgoBcrypt, utils.PasswordAlgoArgon2id} {
+		t.Run(algorithm, func(t *testing.T) {
+			useHashConfig(t, map[string]string{"PASSWORD_HASH_ALGORITHM": algorithm})
+
+			encoded := hash(t, "correct horse")
+			if !verify(t, encoded, "correct horse") {
+				t.Error("VerifyPassword rejected the right password")
+			}
+			if verify(t, encoded, "wrong horse") {
+				t.Error("VerifyPassword accepted a wrong password")
+			}
+			if utils.PasswordNeedsRehash(encoded) {
+				t.Errorf("PasswordNeedsRehash(%q) under the config that made it", encoded)
+			}
+		})
+	}
+}
+
+func TestPasswordRehashOnParameterChange(t *testing.T) {
+	tests := []struct {
+		name       string
+		from, to   map[string]string
+		wantRehash bool
+	}{
+		{"bcrypt cost raised", map[string]string{"BCRYPT_COST": "4"}, map[string]string{"BCRYPT_COST": "5"}, true},
+		{"bcrypt cost lowered", map[string]string{"BCRYPT_COST": "5"}, map[string]string{"BCRYPT_COST": "4"}, false},
+		{"argon2 memory raised",
+			map[string]string{"PASSWORD_HASH_ALGORITHM": "argon2id"},
+			map[string]string{"PASSWORD_HASH_ALGORITHM": "argon2id", "ARGON2_MEMORY_KIB": "128"}, true},
+		{"argon2 iterations raised",
+			map[string]string{"PASSWORD_HASH_ALGORITHM": "argon2id"},
+			map[string]string{"PASSWORD_HASH_ALGORITHM": "argon2id", "ARGON2_ITERATIONS": "2"}, true},
+		{"argon2 parallelism raised",
+			map[string]string{"PASSWORD_HASH_ALGORITHM": "argon2id"},
+			map[string]string{"PASSWORD_HASH_ALGORITHM": "argon2id", "ARGON2_PARALLELISM": "2"}, true},
+		{"argon2 to bcrypt",
+			map[string]string{"PASSWORD_HASH_ALGORITHM": "argon2id"},
+			map[string]string{"PASSWORD_HASH_ALGORITHM": "bcrypt"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useHashConfig(t, tt.from)
+			encoded := hash(t, "correct horse")
+
+			useHashConfig(t, tt.to)
+			if got := utils.PasswordNeedsRehash(encoded); got != tt.wantRehash {
+				t.Errorf("PasswordNeedsRehash = %v, want %v", got, tt.wantRehash)
+			}
+			if !verify(t, encoded, "correct horse") {
+				t.Error("VerifyPassword rejected the old hash after the config changed")
+			}
+		})
+	}
+}
+
+// TestPasswordUpgradeFromBcrypt follows a login after switching to argon2id: the
+// bcrypt hash still verifies, asks to be rehashed, and its replacement is argon2id.
+func TestPasswordUpgradeFromBcrypt(t *testing.T) {
+	useHashConfig(t, nil)
+	old := hash(t, "correct horse")
+	if !strings.HasPrefix(old, "$2a$") {
+		t.Fatalf("bcrypt hash %q lacks the $2a$ prefix", old)
+	}
+
+	useHashConfig(t, map[string]string{"PASSWORD_HASH_ALGORITHM": "argon2id"})
+	if !verify(t, old, "correct horse") {
+		t.Fatal("VerifyPassword rejected the bcrypt hash under argon2id")
+	}
+	if !utils.PasswordNeedsRehash(old) {
+		t.Fatal("PasswordNeedsRehash kept the bcrypt hash under argon2id")
+	}
+
+	upgraded := hash(t, "correct horse")
+	if !strings.HasPrefix(upgraded, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("upgraded hash %q is not argon2id with the configured parameters", upgraded)
+	}
+	if !verify(t, upgraded, "correct horse") || utils.PasswordNeedsRehash(upgraded) {
+		t.Errorf("upgraded hash %q does not verify or still needs a rehash", upgraded)
+	}
+}
+
+func TestLoadPasswordHashConfigRejectsBadValues(t *testing.T) {
+	tests := map[string]string{
+		"PASSWORD_HASH_ALGORITHM": "md5",
+		"BCRYPT_COST":             "32",
+		"ARGON2_MEMORY_KIB":       "4294967296",
+		"ARGON2_ITERATIONS":       "0",
+		"ARGON2_PARALLELISM":      "256",
+	}
+	for key, value := range tests {
+		t.Run(key+"="+value, func(t *testing.T) {
+			t.Setenv(key, value)
+			if _, err := utils.LoadPasswordHashConfig(); err == nil || !strings.Contains(err.Error(), key) {
+				t.Errorf("LoadPasswordHashConfig with %s=%s: err = %v, want one naming %s", key, value, err, key)
+			}
+		})
+	}
+
+	t.Run("not an integer", func(t *testing.T) {
+		t.Setenv("ARGON2_PARALLELISM", "two")
+		if _, err := utils.LoadPasswordHashConfig(); err == nil {
+			t.Error("LoadPasswordHashConfig accepted ARGON2_PARALLELISM=two")
+		}
+	})
+}
+
+func TestLoadPasswordHashConfigDefaults(t *testing.T) {
+	for _, key := range []string{"PASSWORD_HASH_ALGORITHM", "BCRYPT_COST", "ARGON2_MEMORY_KIB", "ARGON2_ITERATIONS", "ARGON2_PARALLELISM"} {
+		t.Setenv(key, "")
+	}
+	cfg, err := utils.LoadPasswordHashConfig()
+	if err != nil {
+		t.Fatalf("LoadPasswordHashConfig: %v", err)
+	}
+	want := utils.PasswordHashConfig{
+		Algorithm:  utils.PasswordAlgoBcrypt,
+		BcryptCost: 12,
+		Argon2:     utils.Argon2Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2},
+	}
+	if cfg != want {
+		t.Errorf("LoadPasswordHashConfig = %+v, want %+v", cfg, want)
+	}
+}
@@ -6,17 +6,13 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"log"
 	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/database"
 	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/models"
-	"github.com/joho/godotenv"
-	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
+	"github.com/ice-wiz/MagicStreamMovies/Server/MagicStreamMoviesServer/repository"
 )
 
 type SignedDetails struct {
@@ -33,15 +29,11 @@ type SignedDetails struct {
 
 func GenerateAllTokens(user models.User) (accessToken string, refreshToken string, err error) {
 
-	if err := godotenv.Load(".env"); err != nil {
-		log.Println("Warn: unable to find .env")
-	}
-
 	accessSecret := os.Getenv("SECRET_KEY")
 	refreshSecret := os.Getenv("SECRET_REFRESH_KEY")
 
 	if accessSecret == "" || refreshSecret == "" {
-		log.Fatal("jwt secrets not set in env")
+		return "", "", errors.New("jwt secrets not set in env")
 	}
 
 	// ---------- ACCESS TOKEN CLAIMS ----------
@@ -91,6 +83,23 @@ func GenerateAllTokens(user models.User) (accessToken string, refreshToken strin
 	return accessToken, refreshToken, nil
 }
 
+// JWTKeysLoaded reports an error when either JWT signing secret is missing.
+// Used by the readiness probe; GenerateAllTokens would otherwise fail on first login.
+func JWTKeysLoaded() error {
+	if os.Getenv("SECRET_KEY") == "" {
+		return errors.New("SECRET_KEY not set")
+	}
+	if os.Getenv("SECRET_REFRESH_KEY") == "" {
+		return errors.New("SECRET_REFRESH_KEY not set")
+	}
+	return nil
+}
+
+// ErrTokenExpired is returned by ValidateAccessToken and ValidateRefreshToken for
+// well-formed tokens whose expiry has passed, so callers can tell "log in again"
+// apart from tampered or malformed tokens.
+var ErrTokenExpired = errors.New("token has expired")
+
 func validateToken(tokenString string, secret string) (*SignedDetails, error) {
 	claims := &SignedDetails{}
 
@@ -101,6 +110,9 @@ func validateToken(tokenString string, secret string) (*SignedDetails, error) {
 		return []byte(secret), nil
 	})
 
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrTokenExpired
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -113,9 +125,6 @@ func validateToken(tokenString string, secret string) (*SignedDetails, error) {
 }
 
 func ValidateAccessToken(tokenString string) (*SignedDetails, error) {
-	if err := godotenv.Load(".env"); err != nil {
-		log.Println("Warn: unable to find .env")
-	}
 	secret := os.Getenv("SECRET_KEY")
 	claims, err := validateToken(tokenString, secret)
 	if err != nil {
@@ -128,16 +137,13 @@ func ValidateAccessToken(tokenString string) (*SignedDetails, error) {
 
 	// Check expiration explicitly
 	if claims.ExpiresAt != nil && claims.ExpiresAt.Time.Before(time.Now()) {
-		return nil, errors.New("token has expired")
+		return nil, ErrTokenExpired
 	}
 
 	return claims, nil
 }
 
 func ValidateRefreshToken(tokenString string) (*SignedDetails, error) {
-	if err := godotenv.Load(".env"); err != nil {
-		log.Println("Warn: unable to find .env")
-	}
 	secret := os.Getenv("SECRET_REFRESH_KEY")
 	claims, err := validateToken(tokenString, secret)
 	if err != nil {
@@ -150,7 +156,7 @@ func ValidateRefreshToken(tokenString string) (*SignedDetails, error) {
 
 	// Check expiration explicitly
 	if claims.ExpiresAt != nil && claims.ExpiresAt.Time.Before(time.Now()) {
-		return nil, errors.New("refresh token has expired")
+		return nil, ErrTokenExpired
 	}
 
 	return claims, nil
@@ -170,32 +176,15 @@ func hashToken(token string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-func UpdateAllTokens(userId, accessToken, refreshToken string, client *mongo.Client) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-	defer cancel()
-
+func UpdateAllTokens(ctx context.Context, userId, accessToken, refreshToken string, users repository.UserRepository) error {
 	// Hash the refresh token using SHA-256 before storing (security best practice)
 	// This prevents plain-text token exposure if database is compromised
 	// Note: We use SHA-256 instead of bcrypt because JWTs exceed bcrypt's 72-byte limit
 	hashedRefreshToken := hashToken(refreshToken)
 
-	updateAt := time.Now()
-	updateData := bson.M{
-		"$set": bson.M{
-			// Access token NOT stored - it's stateless, validated by signature only
-			// Storing it would defeat the purpose of JWT and require DB lookup on every request
-			"refresh_token_hash": hashedRefreshToken, // SHA-256 hashed for security
-			"updated_at":         updateAt,
-		},
-		// Remove old plain-text tokens if they exist (migration)
-		"$unset": bson.M{
-			"token":         "",
-			"refresh_token": "",
-		},
-	}
-
-	userCollection := database.OpenCollection("users", client)
-	_, err := userCollection.UpdateOne(ctx, bson.M{"user_id": userId}, updateData)
+	// Access token NOT stored - it's stateless, validated by signature only
+	// Storing it would defeat the purpose of JWT and require DB lookup on every request
+	err := users.SetRefreshTokenHash(ctx, userId, hashedRefreshToken)
 	if err != nil {
 		return fmt.Errorf("failed to update tokens in database: %w", err)
 	}
@@ -205,23 +194,13 @@ func UpdateAllTokens(userId, accessToken, refreshToken string, client *mongo.Cli
 
 // ValidateRefreshTokenFromDB validates a refresh token by comparing it with the hashed version in database.
 // This is called during token refresh to ensure the token hasn't been revoked.
-func ValidateRefreshTokenFromDB(userId, refreshToken string, client *mongo.Client) error {
-
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-	defer cancel()
-
-	userCollection := database.OpenCollection("users", client)
-
-	// Get user document with refresh_token_hash
-	var user bson.M
-	err := userCollection.FindOne(ctx, bson.M{"user_id": userId}).Decode(&user)
+func ValidateRefreshTokenFromDB(ctx context.Context, userId, refreshToken string, users repository.UserRepository) error {
+	// Get the hashed refresh token from database
+	storedHash, err := users.RefreshTokenHash(ctx, userId)
 	if err != nil {
 		return fmt.Errorf("user not found: %w", err)
 	}
-
-	// Get the hashed refresh token from database
-	storedHash, ok := user["refresh_token_hash"].(string)
-	if !ok || storedHash == "" {
+	if storedHash == "" {
 		return errors.New("refresh token not found for user")
 	}
 
@@ -235,22 +214,8 @@ func ValidateRefreshTokenFromDB(userId, refreshToken string, client *mongo.Clien
 }
 
 // RevokeRefreshToken clears the refresh token for a user (logout).
-func RevokeRefreshToken(userId string, client *mongo.Client) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-	defer cancel()
-
-	updateData := bson.M{
-		"$unset": bson.M{
-			"refresh_token_hash": "",
-		},
-		"$set": bson.M{
-			"updated_at": time.Now(),
-		},
-	}
-
-	userCollection := database.OpenCollection("users", client)
-	_, err := userCollection.UpdateOne(ctx, bson.M{"user_id": userId}, updateData)
-	return err
+func RevokeRefreshToken(ctx context.Context, userId string, users repository.UserRepository) error {
+	return users.ClearRefreshTokenHash(ctx, userId)
 }
 
 func GetAccessToken(c *gin.Context) (string, error) {